@@ -54,7 +54,7 @@ func newChannelCreateCmd() *cobra.Command {
 				Invitees:    invitees,
 			}
 
-			channelID, err := p.CreateChannel(opts)
+			channelID, err := p.CreateChannel(cmd.Context(), opts)
 			if err != nil {
 				return fmt.Errorf("failed to create channel: %w", err)
 			}
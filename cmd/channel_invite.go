@@ -51,7 +51,7 @@ User groups can be specified by handle (e.g. @team-infra).`,
 				Invitees: invitees,
 			}
 
-			if err := p.InviteToChannel(opts); err != nil {
+			if err := p.InviteToChannel(cmd.Context(), opts); err != nil {
 				return fmt.Errorf("failed to invite users: %w", err)
 			}
 
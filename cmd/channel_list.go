@@ -41,7 +41,7 @@ func newChannelListCmd() *cobra.Command {
 					continue
 				}
 
-				channels, err := prov.ListChannels()
+				channels, err := prov.ListChannels(cmd.Context())
 				if err != nil {
 					fmt.Fprintf(os.Stderr, "Warning: could not list channels for profile '%s': %v\n", profileName, err)
 					continue
@@ -0,0 +1,46 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+
+	"github.com/nlink-jp/scat/internal/provider"
+)
+
+// Process exit codes returned by scat. Scripts can rely on these values to
+// react to specific failures without parsing error messages.
+const (
+	ExitOK           = 0   // Success.
+	ExitError        = 1   // Any error not covered below.
+	ExitAuth         = 3   // The token is missing, invalid, expired, or revoked.
+	ExitMissingScope = 4   // The token lacks a required OAuth scope.
+	ExitNotFound     = 5   // The channel or user does not exist.
+	ExitNotInChannel = 6   // The bot is not a member of the channel and could not join.
+	ExitRateLimited  = 7   // The provider kept rate limiting the request.
+	ExitTimeout      = 124 // The --timeout deadline was exceeded.
+	ExitInterrupted  = 130 // The command was interrupted (SIGINT or SIGTERM).
+)
+
+// ExitCode maps an error returned by Execute to a process exit code.
+func ExitCode(err error) int {
+	switch {
+	case err == nil:
+		return ExitOK
+	case errors.Is(err, provider.ErrAuth):
+		return ExitAuth
+	case errors.Is(err, provider.ErrMissingScope):
+		return ExitMissingScope
+	case errors.Is(err, provider.ErrChannelNotFound), errors.Is(err, provider.ErrUserNotFound), errors.Is(err, provider.ErrMessageNotFound):
+		return ExitNotFound
+	case errors.Is(err, provider.ErrNotInChannel):
+		return ExitNotInChannel
+	case errors.Is(err, provider.ErrRateLimited):
+		return ExitRateLimited
+	case errors.Is(err, context.DeadlineExceeded):
+		return ExitTimeout
+	case errors.Is(err, context.Canceled):
+		return ExitInterrupted
+	default:
+		return ExitError
+	}
+}
@@ -0,0 +1,36 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/nlink-jp/scat/internal/provider"
+)
+
+func TestExitCode(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want int
+	}{
+		{"nil", nil, ExitOK},
+		{"generic", errors.New("boom"), ExitError},
+		{"auth", fmt.Errorf("failed to post message: %w", &provider.APIError{Provider: "slack", Code: "invalid_auth", Err: provider.ErrAuth}), ExitAuth},
+		{"missing scope", &provider.APIError{Provider: "slack", Code: "missing_scope", Err: &provider.MissingScopeError{Needed: "chat:write"}}, ExitMissingScope},
+		{"channel not found", fmt.Errorf("%w: \"dev\"", provider.ErrChannelNotFound), ExitNotFound},
+		{"user not found", fmt.Errorf("%w: 'alice'", provider.ErrUserNotFound), ExitNotFound},
+		{"not in channel", fmt.Errorf("failed to join: %w", provider.ErrNotInChannel), ExitNotInChannel},
+		{"rate limited", fmt.Errorf("%w on chat.postMessage", provider.ErrRateLimited), ExitRateLimited},
+		{"timeout", fmt.Errorf("failed to export log: %w", context.DeadlineExceeded), ExitTimeout},
+		{"interrupted", context.Canceled, ExitInterrupted},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ExitCode(tt.err); got != tt.want {
+				t.Errorf("ExitCode(%v) = %d, want %d", tt.err, got, tt.want)
+			}
+		})
+	}
+}
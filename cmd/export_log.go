@@ -2,24 +2,39 @@
 package cmd
 
 import (
+	"bufio"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
 	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/nlink-jp/scat/internal/appcontext"
 	"github.com/nlink-jp/scat/internal/export"
+	"github.com/nlink-jp/scat/internal/provider"
+	"github.com/nlink-jp/scat/internal/util"
 	"github.com/spf13/cobra"
 )
 
+// manifestFileName is the name of the index written by an export of several channels.
+const manifestFileName = "index.json"
+
 // newExportLogCmd creates the command for exporting channel logs.
 func newExportLogCmd() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "log",
-		Short: "Export a channel log",
-		Long:  `Exports a channel log from a supported provider, saving messages and optionally files to a local directory.`,
+		Short: "Export channel logs",
+		Long: `Exports a channel log from a supported provider, saving messages and optionally files to a local directory.
+
+Several channels can be exported in one run by repeating --channel or by selecting
+channels with --all-channels or --channel-regex. Each channel is then written to its
+own file in the --output directory, next to an index.json manifest.`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			appCtx := cmd.Context().Value(appcontext.CtxKey).(appcontext.Context)
 
@@ -50,25 +65,75 @@ func newExportLogCmd() *cobra.Command {
 			}
 
 			// Get flags
-			channelName, _ := cmd.Flags().GetString("channel")
+			channelNames, _ := cmd.Flags().GetStringArray("channel")
+			allChannels, _ := cmd.Flags().GetBool("all-channels")
+			channelRegex, _ := cmd.Flags().GetString("channel-regex")
+			includeArchived, _ := cmd.Flags().GetBool("include-archived")
+			workers, _ := cmd.Flags().GetInt("workers")
+			concurrency, _ := cmd.Flags().GetInt("concurrency")
 			startTimeStr, _ := cmd.Flags().GetString("start-time")
 			endTimeStr, _ := cmd.Flags().GetString("end-time")
 			outputFile, _ := cmd.Flags().GetString("output")
 			outputFiles, _ := cmd.Flags().GetString("output-files")
 			outputFormat, _ := cmd.Flags().GetString("output-format")
+			incremental, _ := cmd.Flags().GetBool("incremental")
+			statePath, _ := cmd.Flags().GetString("state")
+			appendOutput, _ := cmd.Flags().GetBool("append")
+			threadWindow, _ := cmd.Flags().GetDuration("thread-window")
+			timezone, _ := cmd.Flags().GetString("timezone")
+			raw, _ := cmd.Flags().GetBool("raw")
 
-			// Determine file output behavior
-			includeFiles := outputFiles != ""
-			filesDir := ""
-			if includeFiles {
-				if outputFiles == "auto" {
-					filesDir = fmt.Sprintf("./scat-export-%s-%s", strings.TrimPrefix(channelName, "#"), time.Now().UTC().Format("20060102T150405Z"))
-				} else {
-					filesDir = outputFiles
+			// Validate incremental export flags
+			toFile := outputFile != "-" && outputFile != ""
+			if incremental && statePath == "" {
+				return fmt.Errorf("--incremental requires --state")
+			}
+			if statePath != "" && !incremental {
+				return fmt.Errorf("--state can only be used with --incremental")
+			}
+			if (incremental || appendOutput) && !toFile {
+				return fmt.Errorf("--incremental and --append require --output to be a file")
+			}
+			if threadWindow < 0 {
+				return fmt.Errorf("--thread-window must not be negative")
+			}
+			switch outputFormat {
+			case "json", "text", "ndjson":
+			case "slack-export", "html", "markdown", "csv":
+				if outputFormat == "slack-export" && !toFile {
+					return fmt.Errorf("--output-format slack-export requires --output")
 				}
-				if err := os.MkdirAll(filesDir, 0700); err != nil {
-					return fmt.Errorf("failed to create files directory %s: %w", filesDir, err)
+				if incremental || appendOutput {
+					return fmt.Errorf("--incremental and --append cannot be used with --output-format %s", outputFormat)
 				}
+			default:
+				return fmt.Errorf("unsupported output format: %s", outputFormat)
+			}
+			if raw && outputFormat != "json" && outputFormat != "ndjson" {
+				return fmt.Errorf("--raw can only be used with --output-format json or ndjson")
+			}
+			location, err := time.LoadLocation(timezone)
+			if err != nil {
+				return fmt.Errorf("invalid value for --timezone: %w", err)
+			}
+
+			// Validate channel selection flags
+			if includeArchived && !allChannels && channelRegex == "" {
+				return fmt.Errorf("--include-archived can only be used with --all-channels or --channel-regex")
+			}
+			if workers < 1 {
+				return fmt.Errorf("--workers must be at least 1")
+			}
+			if concurrency < 1 {
+				return fmt.Errorf("--concurrency must be at least 1")
+			}
+			channels, err := selectExportChannels(cmd.Context(), prov, channelNames, allChannels, channelRegex, includeArchived)
+			if err != nil {
+				return err
+			}
+			multi := len(channels) > 1 || allChannels || channelRegex != ""
+			if multi && !toFile {
+				return fmt.Errorf("exporting several channels requires --output to be a directory")
 			}
 
 			// Parse timestamps
@@ -81,34 +146,64 @@ func newExportLogCmd() *cobra.Command {
 				return fmt.Errorf("invalid end time: %w", err)
 			}
 
-			if !appCtx.Silent {
-				var timeRangeStr strings.Builder
-				timeRangeStr.WriteString("for all time")
-				if !startTime.IsZero() || !endTime.IsZero() {
-					timeRangeStr.Reset()
-					timeRangeStr.WriteString(fmt.Sprintf("from %s to %s (UTC: %s to %s)",
-						displayTime(startTime, "(beginning of time)"), displayTime(endTime, "now"),
-						displayTime(startTime.UTC(), "(beginning of time)"), displayTime(endTime.UTC(), "now")))
+			e := &logExport{
+				prov:         prov,
+				silent:       appCtx.Silent,
+				format:       outputFormat,
+				startTime:    toUnixTimestampString(startTime),
+				endTime:      toUnixTimestampString(endTime),
+				concurrency:  concurrency,
+				raw:          raw,
+				incremental:  incremental,
+				appendOutput: appendOutput,
+				threadWindow: threadWindow,
+				location:     location,
+				statePath:    statePath,
+			}
+			if incremental {
+				if e.state, err = export.LoadState(statePath); err != nil {
+					return err
 				}
-				fmt.Fprintf(os.Stderr, "Exporting messages for channel %s %s\n", channelName, timeRangeStr.String())
 			}
+			defer e.reportFailedDownloads()
 
-			// Create options and run the export
-			opts := export.Options{
-				ChannelName:  channelName,
-				StartTime:    toUnixTimestampString(startTime),
-				EndTime:      toUnixTimestampString(endTime),
-				IncludeFiles: includeFiles,
-				OutputDir:    filesDir,
+			var timeRangeStr strings.Builder
+			timeRangeStr.WriteString("for all time")
+			if !startTime.IsZero() || !endTime.IsZero() {
+				timeRangeStr.Reset()
+				timeRangeStr.WriteString(fmt.Sprintf("from %s to %s (UTC: %s to %s)",
+					displayTime(startTime, "(beginning of time)"), displayTime(endTime, "now"),
+					displayTime(startTime.UTC(), "(beginning of time)"), displayTime(endTime.UTC(), "now")))
 			}
 
-			exportedLog, err := prov.ExportLog(opts)
-			if err != nil {
-				return fmt.Errorf("failed to export log: %w", err)
+			if multi {
+				if !appCtx.Silent {
+					fmt.Fprintf(os.Stderr, "Exporting messages for %d channels %s with %d workers\n", len(channels), timeRangeStr.String(), workers)
+				}
+				return e.exportChannels(cmd.Context(), channels, outputFile, outputFiles, workers)
 			}
+			channelName := channels[0]
 
-			// Save the log to the specified output
-			if err := saveExportedLog(exportedLog, outputFile, outputFormat); err != nil {
+			// Determine file output behavior
+			includeFiles := outputFiles != ""
+			filesDir := ""
+			if includeFiles {
+				if outputFiles == "auto" {
+					filesDir = fmt.Sprintf("./scat-export-%s-%s", strings.TrimPrefix(channelName, "#"), time.Now().UTC().Format("20060102T150405Z"))
+				} else {
+					filesDir = outputFiles
+				}
+				if err := os.MkdirAll(filesDir, 0700); err != nil {
+					return fmt.Errorf("failed to create files directory %s: %w", filesDir, err)
+				}
+			}
+
+			if !appCtx.Silent {
+				fmt.Fprintf(os.Stderr, "Exporting messages for channel %s %s\n", channelName, timeRangeStr.String())
+			}
+
+			exportedCount, err := e.exportChannel(cmd.Context(), channelName, outputFile, filesDir)
+			if err != nil {
 				return err
 			}
 
@@ -116,7 +211,10 @@ func newExportLogCmd() *cobra.Command {
 			if !appCtx.Silent {
 				var parts []string
 				parts = append(parts, "Log export completed successfully.")
-				if outputFile != "-" && outputFile != "" {
+				if incremental {
+					parts = append(parts, fmt.Sprintf("%d new messages exported.", exportedCount))
+				}
+				if toFile {
 					parts = append(parts, fmt.Sprintf("Log saved to %s.", outputFile))
 				}
 				if includeFiles {
@@ -130,19 +228,477 @@ func newExportLogCmd() *cobra.Command {
 	}
 
 	cmd.Flags().StringP("profile", "p", "", "Profile to use for this export")
-	cmd.Flags().StringP("channel", "c", "", "Channel to export from (required)")
-	_ = cmd.MarkFlagRequired("channel")
+	cmd.Flags().StringArrayP("channel", "c", nil, "Channel to export from (repeatable)")
+	cmd.Flags().Bool("all-channels", false, "Export every channel the provider lists")
+	cmd.Flags().String("channel-regex", "", "Export the listed channels whose name matches this regular expression")
+	cmd.Flags().Bool("include-archived", false, "Also export archived channels selected by --all-channels or --channel-regex")
+	cmd.Flags().Int("workers", 4, "Number of channels to export in parallel")
+	cmd.Flags().Int("concurrency", 4, "Number of threads per channel whose replies are fetched in parallel")
+	cmd.MarkFlagsOneRequired("channel", "all-channels", "channel-regex")
 
-	cmd.Flags().String("output", "-", "Output file path for the log. Use '-' for stdout.")
+	cmd.Flags().String("output", "-", "Output file path for the log. Use '-' for stdout. A directory when exporting several channels.")
 	cmd.Flags().String("output-files", "", "Directory to save downloaded files. If set to 'auto', a directory is auto-generated.")
-	cmd.Flags().String("output-format", "json", "Output format (json or text)")
+	cmd.Flags().String("output-format", "json", "Output format (json, ndjson, text, slack-export, html, markdown, or csv)")
+	cmd.Flags().Bool("raw", false, "Include the untouched provider JSON of each message in the json and ndjson formats")
+	cmd.Flags().String("timezone", "Local", "Time zone of timestamps in the html, markdown, and csv formats, e.g. UTC or Asia/Tokyo")
 	cmd.Flags().String("start-time", "", "Start of time range (RFC3339 format, e.g., 2023-01-01T15:04:05Z)")
 	cmd.Flags().String("end-time", "", "End of time range (RFC3339 format)")
+	cmd.Flags().Bool("incremental", false, "Export only messages and thread replies newer than the previous run recorded in --state")
+	cmd.Flags().String("state", "", "State file recording the progress of incremental exports")
+	cmd.Flags().Bool("append", false, "Add messages to an existing output file instead of overwriting it")
+	cmd.Flags().Duration("thread-window", export.DefaultThreadWindow, "How far back incremental exports look for new thread replies")
 
 	return cmd
 }
 
-func saveExportedLog(log *export.ExportedLog, outputFile, format string) error {
+// selectExportChannels returns the channels to export: the channels given with
+// --channel, followed by the listed channels selected by --all-channels or
+// --channel-regex in name order. Archived channels are only selected from the
+// list if includeArchived is set.
+func selectExportChannels(ctx context.Context, prov provider.Interface, names []string, all bool, pattern string, includeArchived bool) ([]string, error) {
+	var re *regexp.Regexp
+	if pattern != "" {
+		var err error
+		if re, err = regexp.Compile(pattern); err != nil {
+			return nil, fmt.Errorf("invalid value for --channel-regex: %w", err)
+		}
+	}
+
+	var selected []string
+	seen := make(map[string]bool)
+	add := func(name string) {
+		key := strings.TrimPrefix(name, "#")
+		if key != "" && !seen[key] {
+			seen[key] = true
+			selected = append(selected, name)
+		}
+	}
+	for _, name := range names {
+		add(name)
+	}
+	if !all && re == nil {
+		if len(selected) == 0 {
+			return nil, fmt.Errorf("no channel to export")
+		}
+		return selected, nil
+	}
+
+	if !prov.Capabilities().CanListChannels {
+		return nil, fmt.Errorf("the provider cannot list channels, which --all-channels and --channel-regex require")
+	}
+	listed, err := prov.ListChannels(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list channels: %w", err)
+	}
+	sort.Slice(listed, func(i, j int) bool { return listed[i].Name < listed[j].Name })
+	for _, ch := range listed {
+		if ch.IsArchived && !includeArchived {
+			continue
+		}
+		if re != nil && !re.MatchString(ch.Name) {
+			continue
+		}
+		add("#" + ch.Name)
+	}
+	if len(selected) == 0 {
+		return nil, fmt.Errorf("no channels match the selection")
+	}
+	return selected, nil
+}
+
+// logExport holds the settings shared by the channels of one export run.
+type logExport struct {
+	prov         provider.Interface
+	silent       bool
+	format       string
+	startTime    string
+	endTime      string
+	concurrency  int  // Threads fetched in parallel per channel.
+	raw          bool // Include the provider JSON of messages.
+	incremental  bool
+	appendOutput bool
+	threadWindow time.Duration
+	location     *time.Location // Time zone of timestamps in the html, markdown, and csv formats.
+
+	statePath string
+	state     *export.State
+	stateMu   sync.Mutex // Guards state, which channels exported in parallel share.
+
+	// slackExport, if set, receives the logs of all channels instead of a
+	// file per channel.
+	slackExport *export.SlackExportWriter
+
+	failedMu        sync.Mutex
+	failedDownloads []failedDownload // Attachments that could not be downloaded.
+}
+
+// failedDownload is an attachment that could not be downloaded.
+type failedDownload struct {
+	channelName string
+	file        export.ExportedFile
+}
+
+// recordFailedDownloads notes the attachments of msgs that could not be
+// downloaded.
+func (e *logExport) recordFailedDownloads(channelName string, msgs []export.ExportedMessage) {
+	e.failedMu.Lock()
+	defer e.failedMu.Unlock()
+	for _, msg := range msgs {
+		for _, f := range msg.Files {
+			if f.DownloadError != "" {
+				e.failedDownloads = append(e.failedDownloads, failedDownload{channelName: channelName, file: f})
+			}
+		}
+	}
+}
+
+// failedDownloadCount returns the number of attachments of a channel that
+// could not be downloaded.
+func (e *logExport) failedDownloadCount(channelName string) int {
+	e.failedMu.Lock()
+	defer e.failedMu.Unlock()
+	n := 0
+	for _, failed := range e.failedDownloads {
+		if failed.channelName == channelName {
+			n++
+		}
+	}
+	return n
+}
+
+// reportFailedDownloads ends the export with a summary of the attachments
+// that could not be downloaded, if any.
+func (e *logExport) reportFailedDownloads() {
+	e.failedMu.Lock()
+	defer e.failedMu.Unlock()
+	if len(e.failedDownloads) == 0 {
+		return
+	}
+	sort.SliceStable(e.failedDownloads, func(i, j int) bool {
+		return e.failedDownloads[i].channelName < e.failedDownloads[j].channelName
+	})
+	fmt.Fprintf(os.Stderr, "Warning: %d attachments could not be downloaded:\n", len(e.failedDownloads))
+	for _, failed := range e.failedDownloads {
+		fmt.Fprintf(os.Stderr, "  %s: %s (%s): %s\n", failed.channelName, failed.file.Name, failed.file.ID, failed.file.DownloadError)
+	}
+}
+
+// exportChannel exports a channel to outputFile and returns the number of
+// messages written. Files are downloaded to filesDir if it is set.
+func (e *logExport) exportChannel(ctx context.Context, channelName, outputFile, filesDir string) (int, error) {
+	opts := export.Options{
+		ChannelName:  channelName,
+		StartTime:    e.startTime,
+		EndTime:      e.endTime,
+		IncludeFiles: filesDir != "",
+		OutputDir:    filesDir,
+		Concurrency:  e.concurrency,
+		IncludeRaw:   e.raw,
+	}
+
+	if !e.incremental {
+		if e.format == "ndjson" {
+			return e.streamChannel(ctx, opts, outputFile)
+		}
+		exportedLog, err := export.Collect(func(onPage export.PageFunc) (*export.LogInfo, error) {
+			return e.prov.ExportLog(ctx, opts, onPage)
+		})
+		if err != nil {
+			return 0, fmt.Errorf("failed to export log: %w", err)
+		}
+		e.recordFailedDownloads(channelName, exportedLog.Messages)
+
+		// Save the log to the specified output
+		switch {
+		case e.slackExport != nil:
+			err = e.slackExport.AddLog(exportedLog)
+		case e.appendOutput:
+			err = appendExportedLog(outputFile, e.format, channelName, exportedLog.Messages)
+		default:
+			err = saveExportedLog(exportedLog, outputFile, e.format, e.location)
+		}
+		if err != nil {
+			return 0, err
+		}
+		return len(exportedLog.Messages), nil
+	}
+
+	e.stateMu.Lock()
+	chState := e.state.Channel(channelName)
+	resuming, latestTS := chState.Pending != nil, chState.LatestTS
+	opts = chState.Resume(opts, e.threadWindow)
+	e.stateMu.Unlock()
+
+	if !e.silent {
+		switch {
+		case resuming:
+			fmt.Fprintf(os.Stderr, "Resuming the interrupted export of channel %s.\n", channelName)
+		case latestTS != "":
+			fmt.Fprintf(os.Stderr, "Exporting messages of channel %s newer than %s and new thread replies.\n", channelName, latestTS)
+		}
+	}
+
+	// The log is written and the state is saved after each page, so that an
+	// interrupted export resumes after the last saved page. NDJSON and text
+	// logs are appended to. A JSON log is read once, and the merged log is
+	// rewritten after each page.
+	var jsonLog *export.ExportedLog
+	if e.format == "json" {
+		var err error
+		if jsonLog, err = readExportedLog(outputFile); err != nil {
+			return 0, err
+		}
+	}
+	exportedCount := 0
+	onPage := func(page export.Page) error {
+		e.recordFailedDownloads(channelName, page.Messages)
+		var err error
+		if jsonLog != nil {
+			jsonLog.Messages = export.MergeMessages(jsonLog.Messages, page.Messages)
+			err = writeExportedLog(outputFile, channelName, jsonLog)
+		} else {
+			err = appendExportedLog(outputFile, e.format, channelName, page.Messages)
+		}
+		if err != nil {
+			return err
+		}
+		exportedCount += len(page.Messages)
+
+		e.stateMu.Lock()
+		defer e.stateMu.Unlock()
+		chState.Record(opts, page, time.Now().UTC())
+		return e.state.Save(e.statePath)
+	}
+	if _, err := e.prov.ExportLog(ctx, opts, onPage); err != nil {
+		e.stateMu.Lock()
+		pending := chState.Pending != nil
+		e.stateMu.Unlock()
+		if pending {
+			return exportedCount, fmt.Errorf("failed to export log: %w (progress saved to %s; run the command again to resume)", err, e.statePath)
+		}
+		return exportedCount, fmt.Errorf("failed to export log: %w", err)
+	}
+
+	e.stateMu.Lock()
+	defer e.stateMu.Unlock()
+	chState.Prune(e.threadWindow)
+	return exportedCount, e.state.Save(e.statePath)
+}
+
+// streamChannel exports a channel in the ndjson format, writing each page to
+// outputFile as soon as it has been fetched, so that memory use does not grow
+// with the size of the channel. The messages are in the order of export.Page.
+func (e *logExport) streamChannel(ctx context.Context, opts export.Options, outputFile string) (int, error) {
+	f := os.Stdout
+	if outputFile != "-" && outputFile != "" {
+		flags := os.O_WRONLY | os.O_CREATE | os.O_TRUNC
+		if e.appendOutput {
+			flags = os.O_WRONLY | os.O_CREATE | os.O_APPEND
+		}
+		var err error
+		f, err = os.OpenFile(outputFile, flags, 0600)
+		if err != nil {
+			return 0, fmt.Errorf("failed to create output file: %w", err)
+		}
+		defer f.Close()
+	}
+
+	bw := bufio.NewWriter(f)
+	exportedCount := 0
+	_, err := e.prov.ExportLog(ctx, opts, func(page export.Page) error {
+		e.recordFailedDownloads(opts.ChannelName, page.Messages)
+		if err := export.WriteNDJSON(bw, page.Messages); err != nil {
+			return err
+		}
+		exportedCount += len(page.Messages)
+		return bw.Flush()
+	})
+	if err != nil {
+		return exportedCount, fmt.Errorf("failed to export log: %w", err)
+	}
+	if f != os.Stdout {
+		if err := f.Close(); err != nil {
+			return exportedCount, fmt.Errorf("failed to write output file: %w", err)
+		}
+	}
+	return exportedCount, nil
+}
+
+// exportChannels exports channels into outputDir with up to workers channels
+// in parallel, one log file per channel, and writes a manifest indexing them.
+// In the slack-export format, all channels go into a single Slack export at
+// outputDir instead, which channels.json indexes. The failure of one channel
+// does not stop the others. With outputFiles set, files are downloaded into a
+// subdirectory per channel.
+func (e *logExport) exportChannels(ctx context.Context, channels []string, outputDir, outputFiles string, workers int) error {
+	filesRoot := outputFiles
+	if e.format == "slack-export" {
+		w, err := export.NewSlackExportWriter(outputDir)
+		if err != nil {
+			return err
+		}
+		e.slackExport = w
+		if outputFiles == "auto" {
+			// Keep downloaded files out of the export itself.
+			filesRoot = strings.TrimSuffix(outputDir, filepath.Ext(outputDir)) + "-files"
+		}
+	} else {
+		if err := os.MkdirAll(outputDir, 0700); err != nil {
+			return fmt.Errorf("failed to create output directory %s: %w", outputDir, err)
+		}
+		if outputFiles == "auto" {
+			filesRoot = filepath.Join(outputDir, "files")
+		}
+	}
+
+	entries := make([]export.ManifestEntry, len(channels))
+	fileNames := export.ChannelFileNames(channels)
+	for i, channelName := range channels {
+		base := fileNames[i]
+		entries[i] = export.ManifestEntry{ChannelName: channelName, File: base + logFileExt(e.format)}
+		if filesRoot != "" {
+			entries[i].FilesDir = filepath.Join(filesRoot, base)
+		}
+	}
+
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < min(workers, len(channels)); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				entry := &entries[i]
+				if err := ctx.Err(); err != nil {
+					entry.Error = err.Error()
+					continue
+				}
+				err := e.exportManifestEntry(ctx, entry, outputDir)
+				if err != nil {
+					entry.Error = err.Error()
+				}
+				if e.silent {
+					continue
+				}
+				if err != nil {
+					fmt.Fprintf(os.Stderr, "Failed to export channel %s: %v\n", entry.ChannelName, err)
+				} else {
+					fmt.Fprintf(os.Stderr, "Exported channel %s: %d messages.\n", entry.ChannelName, entry.MessageCount)
+				}
+			}
+		}()
+	}
+	for i := range channels {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	if e.slackExport != nil {
+		return e.finishSlackExport(ctx, entries, outputDir)
+	}
+
+	manifestPath := filepath.Join(outputDir, manifestFileName)
+	manifest := export.Manifest{
+		FormatVersion:   export.FormatVersion,
+		ExportTimestamp: time.Now().UTC().Format(time.RFC3339),
+		Format:          e.format,
+		Channels:        entries,
+	}
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal manifest: %w", err)
+	}
+	if err := util.WriteFileAtomic(manifestPath, append(data, '\n')); err != nil {
+		return fmt.Errorf("failed to write manifest: %w", err)
+	}
+
+	if err := ctx.Err(); err != nil {
+		return fmt.Errorf("export interrupted: %w", err)
+	}
+	failed := 0
+	for _, entry := range entries {
+		if entry.Error != "" {
+			failed++
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("failed to export %d of %d channels; see %s", failed, len(channels), manifestPath)
+	}
+	if !e.silent {
+		fmt.Fprintf(os.Stderr, "Log export completed successfully. %d channels saved to %s, indexed in %s.\n", len(channels), outputDir, manifestPath)
+	}
+	return nil
+}
+
+// finishSlackExport completes the Slack export of several channels.
+func (e *logExport) finishSlackExport(ctx context.Context, entries []export.ManifestEntry, output string) error {
+	if err := e.slackExport.Close(); err != nil {
+		return err
+	}
+	if err := ctx.Err(); err != nil {
+		return fmt.Errorf("export interrupted: %w", err)
+	}
+	failed := 0
+	for _, entry := range entries {
+		if entry.Error != "" {
+			failed++
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("failed to export %d of %d channels", failed, len(entries))
+	}
+	if !e.silent {
+		fmt.Fprintf(os.Stderr, "Log export completed successfully. %d channels saved to %s.\n", len(entries), output)
+	}
+	return nil
+}
+
+// exportManifestEntry exports the channel of entry into outputDir and records
+// the number of messages written.
+func (e *logExport) exportManifestEntry(ctx context.Context, entry *export.ManifestEntry, outputDir string) error {
+	if entry.FilesDir != "" {
+		if err := os.MkdirAll(entry.FilesDir, 0700); err != nil {
+			return fmt.Errorf("failed to create files directory %s: %w", entry.FilesDir, err)
+		}
+	}
+	count, err := e.exportChannel(ctx, entry.ChannelName, filepath.Join(outputDir, entry.File), entry.FilesDir)
+	entry.MessageCount = count
+	entry.FailedDownloads = e.failedDownloadCount(entry.ChannelName)
+	return err
+}
+
+// logFileExt returns the file name extension of a log in the given output
+// format. A channel of a Slack export is a directory without an extension.
+func logFileExt(format string) string {
+	switch format {
+	case "text":
+		return ".txt"
+	case "markdown":
+		return ".md"
+	case "slack-export":
+		return ""
+	}
+	return "." + format
+}
+
+func saveExportedLog(log *export.ExportedLog, outputFile, format string, location *time.Location) error {
+	// A Slack export is a directory or zip archive rather than a single stream.
+	if format == "slack-export" {
+		if outputFile == "-" || outputFile == "" {
+			return fmt.Errorf("--output-format slack-export requires --output")
+		}
+		w, err := export.NewSlackExportWriter(outputFile)
+		if err != nil {
+			return err
+		}
+		if err := w.AddLog(log); err != nil {
+			w.Close()
+			return err
+		}
+		return w.Close()
+	}
+
 	// Determine output writer
 	var writer io.Writer
 	if outputFile == "-" || outputFile == "" {
@@ -162,27 +718,134 @@ func saveExportedLog(log *export.ExportedLog, outputFile, format string) error {
 		encoder := json.NewEncoder(writer)
 		encoder.SetIndent("", "  ")
 		return encoder.Encode(log)
+	case "ndjson":
+		return export.WriteNDJSON(writer, log.Messages)
 	case "text":
 		var content strings.Builder
-		content.WriteString(fmt.Sprintf("# Log export for channel %s on %s\n", log.ChannelName, log.ExportTimestamp))
-		for _, msg := range log.Messages {
-			content.WriteString("---\n")
-			indent := ""
-			if msg.IsReply {
-				indent = "    " // 4 spaces for indentation
-			}
-			content.WriteString(fmt.Sprintf("%s[%s] %s: %s\n", indent, msg.Timestamp, msg.UserName, msg.Text))
-			for _, file := range msg.Files {
-				content.WriteString(fmt.Sprintf("%s  - Attachment: %s (saved to: %s)\n", indent, file.Name, file.LocalPath))
-			}
-		}
+		content.WriteString(textLogHeader(log.ChannelName, log.ExportTimestamp))
+		content.WriteString(formatTextMessages(log.Messages))
 		_, err := writer.Write([]byte(content.String()))
 		return err
+	case "html", "markdown":
+		baseDir := "."
+		if outputFile != "-" && outputFile != "" {
+			baseDir = filepath.Dir(outputFile)
+		}
+		opts := export.RenderOptions{Location: location, BaseDir: baseDir}
+		if format == "markdown" {
+			return export.WriteMarkdown(writer, log, opts)
+		}
+		return export.WriteHTML(writer, log, opts)
+	case "csv":
+		return export.WriteCSV(writer, log, location)
 	default:
 		return fmt.Errorf("unsupported output format: %s", format)
 	}
 }
 
+// readExportedLog reads the JSON log at outputFile. A missing file gives an
+// empty log.
+func readExportedLog(outputFile string) (*export.ExportedLog, error) {
+	log := &export.ExportedLog{}
+	data, err := os.ReadFile(outputFile)
+	if err == nil {
+		if err := json.Unmarshal(data, log); err != nil {
+			return nil, fmt.Errorf("failed to parse existing output file %s: %w", outputFile, err)
+		}
+	} else if !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to read existing output file: %w", err)
+	}
+	return log, nil
+}
+
+// writeExportedLog replaces the JSON log at outputFile with log, stamped with
+// the current export time.
+func writeExportedLog(outputFile, channelName string, log *export.ExportedLog) error {
+	log.FormatVersion = export.FormatVersion
+	log.ExportTimestamp = time.Now().UTC().Format(time.RFC3339)
+	log.ChannelName = channelName
+
+	data, err := json.MarshalIndent(log, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal log: %w", err)
+	}
+	if err := util.WriteFileAtomic(outputFile, append(data, '\n')); err != nil {
+		return fmt.Errorf("failed to write output file: %w", err)
+	}
+	return nil
+}
+
+// appendExportedLog adds messages to the log file at outputFile, creating it
+// if necessary. A JSON log is rewritten with the messages merged in, replacing
+// messages that were exported before; NDJSON and text logs are appended to.
+func appendExportedLog(outputFile, format, channelName string, msgs []export.ExportedMessage) error {
+	exportTimestamp := time.Now().UTC().Format(time.RFC3339)
+	switch format {
+	case "json":
+		log, err := readExportedLog(outputFile)
+		if err != nil {
+			return err
+		}
+		log.Messages = export.MergeMessages(log.Messages, msgs)
+		return writeExportedLog(outputFile, channelName, log)
+	case "ndjson":
+		f, err := os.OpenFile(outputFile, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0600)
+		if err != nil {
+			return fmt.Errorf("failed to open output file: %w", err)
+		}
+		defer f.Close()
+		bw := bufio.NewWriter(f)
+		if err := export.WriteNDJSON(bw, msgs); err != nil {
+			return err
+		}
+		if err := bw.Flush(); err != nil {
+			return fmt.Errorf("failed to write output file: %w", err)
+		}
+		return f.Close()
+	case "text":
+		_, statErr := os.Stat(outputFile)
+		f, err := os.OpenFile(outputFile, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0600)
+		if err != nil {
+			return fmt.Errorf("failed to open output file: %w", err)
+		}
+		defer f.Close()
+
+		var content strings.Builder
+		if os.IsNotExist(statErr) {
+			content.WriteString(textLogHeader(channelName, exportTimestamp))
+		}
+		content.WriteString(formatTextMessages(msgs))
+		if _, err := f.WriteString(content.String()); err != nil {
+			return fmt.Errorf("failed to write output file: %w", err)
+		}
+		return f.Close()
+	default:
+		return fmt.Errorf("unsupported output format: %s", format)
+	}
+}
+
+// textLogHeader returns the first line of a log in text format.
+func textLogHeader(channelName, exportTimestamp string) string {
+	return fmt.Sprintf("# Log export for channel %s on %s\n", channelName, exportTimestamp)
+}
+
+// formatTextMessages formats messages for a log in text format.
+func formatTextMessages(msgs []export.ExportedMessage) string {
+	var content strings.Builder
+	for _, msg := range msgs {
+		content.WriteString("---\n")
+		indent := ""
+		if msg.IsReply {
+			indent = "    " // 4 spaces for indentation
+		}
+		content.WriteString(fmt.Sprintf("%s[%s] %s: %s\n", indent, msg.Timestamp, msg.UserName, msg.Text))
+		for _, file := range msg.Files {
+			content.WriteString(fmt.Sprintf("%s  - Attachment: %s (saved to: %s)\n", indent, file.Name, file.LocalPath))
+		}
+	}
+	return content.String()
+}
+
 // parseTime parses a string into a time.Time object.
 // It accepts RFC3339 format or a local time format.
 func parseTime(timeStr string) (time.Time, error) {
@@ -1,14 +1,20 @@
 package cmd
 
 import (
+	"archive/zip"
+	"context"
+	"encoding/json"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"strings"
 	"testing"
 	"time"
 
-	_ "github.com/nlink-jp/scat/internal/provider/testprovider"
+	"github.com/nlink-jp/scat/internal/export"
+	"github.com/nlink-jp/scat/internal/provider"
+	"github.com/nlink-jp/scat/internal/provider/testprovider"
 )
 
 func TestExportLog_Default(t *testing.T) {
@@ -134,4 +140,714 @@ func TestExportLog_WithFileOutput(t *testing.T) {
 	if _, err := os.Stat(outputFilesDir); os.IsNotExist(err) {
 		t.Errorf("Expected output directory '%s' to be created, but it was not", outputFilesDir)
 	}
-}
\ No newline at end of file
+}
+func TestExportLog_Incremental(t *testing.T) {
+	configPath, cleanup := setupTest(t)
+	defer cleanup()
+
+	tempDir := t.TempDir()
+	outputFile := filepath.Join(tempDir, "output.json")
+	statePath := filepath.Join(tempDir, "state.json")
+	args := []string{"--config", configPath, "export", "log", "--channel", "#test-channel", "--output", outputFile, "--incremental", "--state", statePath}
+
+	// The first run exports everything and records the newest message.
+	rootCmd := newRootCmd()
+	rootCmd.AddCommand(newExportCmd())
+	_, stderr, err := testExecuteCommandAndCapture(rootCmd, args...)
+	if err != nil {
+		t.Fatalf("First run returned an error: %v\nStderr: %s", err, stderr)
+	}
+	if strings.Contains(stderr, "ExportLog incremental:") {
+		t.Errorf("Expected the first run to export everything, got: %s", stderr)
+	}
+	if !strings.Contains(stderr, "1 new messages exported.") {
+		t.Errorf("Expected stderr to report 1 new message, got: %s", stderr)
+	}
+
+	state, err := export.LoadState(statePath)
+	if err != nil {
+		t.Fatalf("Failed to load the state file: %v", err)
+	}
+	if got := state.Channels["test-channel"].LatestTS; got != testprovider.TestExportMessageTS {
+		t.Errorf("Expected LatestTS %s, got %s", testprovider.TestExportMessageTS, got)
+	}
+
+	// The second run only asks for newer messages and keeps the output intact.
+	rootCmd = newRootCmd()
+	rootCmd.AddCommand(newExportCmd())
+	_, stderr, err = testExecuteCommandAndCapture(rootCmd, args...)
+	if err != nil {
+		t.Fatalf("Second run returned an error: %v\nStderr: %s", err, stderr)
+	}
+	expectedLog := fmt.Sprintf("ExportLog incremental: {Cursor: ExportedUntil:%s Threads:0}", testprovider.TestExportMessageTS)
+	if !strings.Contains(stderr, expectedLog) {
+		t.Errorf("Expected stderr to contain '%s', got: '%s'", expectedLog, stderr)
+	}
+	if !strings.Contains(stderr, "0 new messages exported.") {
+		t.Errorf("Expected stderr to report no new messages, got: %s", stderr)
+	}
+
+	var log export.ExportedLog
+	data, err := os.ReadFile(outputFile)
+	if err != nil {
+		t.Fatalf("Failed to read output file: %v", err)
+	}
+	if err := json.Unmarshal(data, &log); err != nil {
+		t.Fatalf("Output file is not valid JSON: %v", err)
+	}
+	if len(log.Messages) != 1 {
+		t.Errorf("Expected 1 message in the output file, got %d", len(log.Messages))
+	}
+}
+
+func TestExportLog_IncrementalResume(t *testing.T) {
+	configPath, cleanup := setupTest(t)
+	defer cleanup()
+
+	tempDir := t.TempDir()
+	outputFile := filepath.Join(tempDir, "output.txt")
+	statePath := filepath.Join(tempDir, "state.json")
+
+	// Simulate a run that was interrupted after its first page.
+	state, _ := export.LoadState(statePath)
+	state.Channel("#test-channel").Pending = &export.PendingExport{Oldest: "1672000000.000000", Cursor: "dXNlcjpVMDYxTkZUVDI="}
+	if err := state.Save(statePath); err != nil {
+		t.Fatal(err)
+	}
+
+	rootCmd := newRootCmd()
+	rootCmd.AddCommand(newExportCmd())
+	_, stderr, err := testExecuteCommandAndCapture(rootCmd, "--config", configPath, "export", "log", "--channel", "#test-channel",
+		"--output", outputFile, "--output-format", "text", "--incremental", "--state", statePath)
+	if err != nil {
+		t.Fatalf("testExecuteCommandAndCapture returned an error: %v\nStderr: %s", err, stderr)
+	}
+	for _, want := range []string{
+		"Resuming the interrupted export of channel #test-channel.",
+		"ExportLog called with opts: {ChannelName:#test-channel StartTime:1672000000.000000 EndTime: IncludeFiles:false OutputDir:}",
+		"ExportLog incremental: {Cursor:dXNlcjpVMDYxTkZUVDI= ExportedUntil: Threads:0}",
+	} {
+		if !strings.Contains(stderr, want) {
+			t.Errorf("Expected stderr to contain '%s', got: '%s'", want, stderr)
+		}
+	}
+
+	state, err = export.LoadState(statePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ch := state.Channels["test-channel"]; ch.Pending != nil || ch.LatestTS != testprovider.TestExportMessageTS {
+		t.Errorf("Expected the resumed export to complete, got state: %+v", ch)
+	}
+	content, err := os.ReadFile(outputFile)
+	if err != nil {
+		t.Fatalf("Failed to read output file: %v", err)
+	}
+	if !strings.HasPrefix(string(content), "# Log export for channel #test-channel") {
+		t.Errorf("Expected a new text log to start with a header, got: %s", content)
+	}
+}
+
+// pagedProvider hands over a fixed list of pages from ExportLog. Its other
+// methods are not implemented.
+type pagedProvider struct {
+	provider.Interface
+	pages  []export.Page
+	onPage func(i int) // Called after page i has been handed over.
+}
+
+func (p *pagedProvider) ExportLog(ctx context.Context, opts export.Options, onPage export.PageFunc) (*export.LogInfo, error) {
+	for i, page := range p.pages {
+		if err := onPage(page); err != nil {
+			return nil, err
+		}
+		p.onPage(i)
+	}
+	return &export.LogInfo{ChannelName: opts.ChannelName}, nil
+}
+
+func TestLogExport_IncrementalJSONSavesEachPage(t *testing.T) {
+	tempDir := t.TempDir()
+	outputFile := filepath.Join(tempDir, "output.json")
+	statePath := filepath.Join(tempDir, "state.json")
+
+	prov := &pagedProvider{pages: []export.Page{
+		{Messages: []export.ExportedMessage{{Text: "third", TimestampUnix: "1700000300.000000"}}, NextCursor: "page2"},
+		{Messages: []export.ExportedMessage{{Text: "second", TimestampUnix: "1700000200.000000"}}, NextCursor: "page3"},
+		{Messages: []export.ExportedMessage{{Text: "first", TimestampUnix: "1700000100.000000"}}},
+	}}
+	// After each page, the log holds the messages so far and the state holds
+	// the cursor of the next page, so that an interrupted run can resume.
+	prov.onPage = func(i int) {
+		var log export.ExportedLog
+		data, _ := os.ReadFile(outputFile)
+		if err := json.Unmarshal(data, &log); err != nil {
+			t.Fatalf("Output file after page %d is not valid JSON: %v", i, err)
+		}
+		if len(log.Messages) != i+1 {
+			t.Errorf("Expected %d messages after page %d, got: %+v", i+1, i, log.Messages)
+		}
+		state, err := export.LoadState(statePath)
+		if err != nil {
+			t.Fatal(err)
+		}
+		ch := state.Channels["test-channel"]
+		if next := prov.pages[i].NextCursor; next != "" && (ch.Pending == nil || ch.Pending.Cursor != next) {
+			t.Errorf("Expected the cursor %q saved after page %d, got: %+v", next, i, ch.Pending)
+		}
+	}
+
+	state, err := export.LoadState(statePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	e := &logExport{prov: prov, silent: true, format: "json", incremental: true, statePath: statePath, state: state, threadWindow: export.DefaultThreadWindow}
+	n, err := e.exportChannel(context.Background(), "#test-channel", outputFile, "")
+	if err != nil {
+		t.Fatalf("exportChannel() returned an unexpected error: %v", err)
+	}
+	if n != 3 {
+		t.Errorf("exportChannel() = %d, want 3", n)
+	}
+
+	var log export.ExportedLog
+	data, _ := os.ReadFile(outputFile)
+	if err := json.Unmarshal(data, &log); err != nil {
+		t.Fatalf("Output file is not valid JSON: %v", err)
+	}
+	if len(log.Messages) != 3 || log.Messages[0].Text != "first" || log.Messages[2].Text != "third" {
+		t.Errorf("Unexpected messages: %+v", log.Messages)
+	}
+	state, err = export.LoadState(statePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ch := state.Channels["test-channel"]; ch.Pending != nil || ch.LatestTS != "1700000300.000000" {
+		t.Errorf("Expected the completed export in the state, got: %+v", ch)
+	}
+}
+
+func TestExportLog_Append(t *testing.T) {
+	configPath, cleanup := setupTest(t)
+	defer cleanup()
+
+	tempDir := t.TempDir()
+	jsonFile := filepath.Join(tempDir, "output.json")
+	textFile := filepath.Join(tempDir, "output.txt")
+
+	existing := export.ExportedLog{
+		ChannelName: "#test-channel",
+		Messages:    []export.ExportedMessage{{Text: "Older message", TimestampUnix: "1672000000.000000"}},
+	}
+	data, _ := json.Marshal(existing)
+	if err := os.WriteFile(jsonFile, data, 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(textFile, []byte("# Log export for channel #test-channel on earlier\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < 2; i++ {
+		for _, output := range []struct{ file, format string }{{jsonFile, "json"}, {textFile, "text"}} {
+			rootCmd := newRootCmd()
+			rootCmd.AddCommand(newExportCmd())
+			_, stderr, err := testExecuteCommandAndCapture(rootCmd, "--config", configPath, "export", "log", "--channel", "#test-channel",
+				"--output", output.file, "--output-format", output.format, "--append")
+			if err != nil {
+				t.Fatalf("testExecuteCommandAndCapture returned an error: %v\nStderr: %s", err, stderr)
+			}
+		}
+	}
+
+	// JSON logs are merged, so exporting the same message twice keeps one copy.
+	var log export.ExportedLog
+	data, _ = os.ReadFile(jsonFile)
+	if err := json.Unmarshal(data, &log); err != nil {
+		t.Fatalf("Output file is not valid JSON: %v", err)
+	}
+	if len(log.Messages) != 2 || log.Messages[0].Text != "Older message" || log.Messages[1].Text != "Test message from ExportLog" {
+		t.Errorf("Unexpected merged messages: %+v", log.Messages)
+	}
+
+	// Text logs are appended to, keeping the original header.
+	content, _ := os.ReadFile(textFile)
+	if strings.Count(string(content), "# Log export") != 1 || strings.Count(string(content), "Test message from ExportLog") != 2 {
+		t.Errorf("Unexpected appended text log: %s", content)
+	}
+}
+
+func TestExportLog_IncrementalFlagErrors(t *testing.T) {
+	configPath, cleanup := setupTest(t)
+	defer cleanup()
+
+	tempDir := t.TempDir()
+	outputFile := filepath.Join(tempDir, "output.json")
+	statePath := filepath.Join(tempDir, "state.json")
+
+	tests := []struct {
+		name        string
+		args        []string
+		expectedErr string
+	}{
+		{"incremental without state", []string{"--output", outputFile, "--incremental"}, "--incremental requires --state"},
+		{"state without incremental", []string{"--output", outputFile, "--state", statePath}, "--state can only be used with --incremental"},
+		{"incremental to stdout", []string{"--incremental", "--state", statePath}, "--incremental and --append require --output to be a file"},
+		{"append to stdout", []string{"--append"}, "--incremental and --append require --output to be a file"},
+		{"unknown format", []string{"--output-format", "xml"}, "unsupported output format: xml"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rootCmd := newRootCmd()
+			rootCmd.AddCommand(newExportCmd())
+			args := append([]string{"--config", configPath, "export", "log", "--channel", "#test-channel"}, tt.args...)
+			_, _, err := testExecuteCommandAndCapture(rootCmd, args...)
+			if err == nil || !strings.Contains(err.Error(), tt.expectedErr) {
+				t.Errorf("Expected error containing '%s', got: %v", tt.expectedErr, err)
+			}
+		})
+	}
+}
+
+func TestExportLog_MultipleChannels(t *testing.T) {
+	configPath, cleanup := setupTest(t)
+	defer cleanup()
+
+	tests := []struct {
+		name             string
+		args             []string
+		expectedChannels []string
+	}{
+		{"repeated channel", []string{"--channel", "#alpha", "--channel", "beta", "--channel", "#alpha"}, []string{"#alpha", "beta"}},
+		{"all channels", []string{"--all-channels"}, []string{"#test-channel-1"}},
+		{"all channels with archived", []string{"--all-channels", "--include-archived", "--workers", "1"}, []string{"#test-channel-1", "#test-channel-2"}},
+		{"channel regex", []string{"--channel-regex", "-2$", "--include-archived"}, []string{"#test-channel-2"}},
+		{"non-ASCII names", []string{"--channel", "#開発", "--channel", "#営業", "--channel", "#開発!", "--channel", "#開発?"}, []string{"#開発", "#営業", "#開発!", "#開発?"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			outputDir := filepath.Join(t.TempDir(), "export")
+			rootCmd := newRootCmd()
+			rootCmd.AddCommand(newExportCmd())
+			args := append([]string{"--config", configPath, "export", "log", "--output", outputDir, "--output-files", "auto"}, tt.args...)
+			_, stderr, err := testExecuteCommandAndCapture(rootCmd, args...)
+			if err != nil {
+				t.Fatalf("testExecuteCommandAndCapture returned an error: %v\nStderr: %s", err, stderr)
+			}
+
+			data, err := os.ReadFile(filepath.Join(outputDir, "index.json"))
+			if err != nil {
+				t.Fatalf("Failed to read the manifest: %v", err)
+			}
+			var manifest export.Manifest
+			if err := json.Unmarshal(data, &manifest); err != nil {
+				t.Fatalf("The manifest is not valid JSON: %v", err)
+			}
+			if len(manifest.Channels) != len(tt.expectedChannels) {
+				t.Fatalf("Expected %d channels in the manifest, got %+v", len(tt.expectedChannels), manifest.Channels)
+			}
+			for i, entry := range manifest.Channels {
+				if entry.ChannelName != tt.expectedChannels[i] || entry.MessageCount != 1 || entry.Error != "" {
+					t.Errorf("Unexpected manifest entry %d: %+v", i, entry)
+				}
+				content, err := os.ReadFile(filepath.Join(outputDir, entry.File))
+				if err != nil {
+					t.Errorf("Failed to read the log of %s: %v", entry.ChannelName, err)
+				} else if !strings.Contains(string(content), "Test message from ExportLog") {
+					t.Errorf("The log of %s does not contain the exported message: %s", entry.ChannelName, content)
+				}
+				expectedLog := fmt.Sprintf("ExportLog called with opts: {ChannelName:%s StartTime: EndTime: IncludeFiles:true OutputDir:%s}", entry.ChannelName, entry.FilesDir)
+				if !strings.Contains(stderr, expectedLog) {
+					t.Errorf("Expected stderr to contain '%s', got: '%s'", expectedLog, stderr)
+				}
+			}
+
+			// Every channel needs a log and a files directory of its own.
+			seen := make(map[string]string)
+			for _, entry := range manifest.Channels {
+				for _, path := range []string{entry.File, entry.FilesDir} {
+					if other, ok := seen[path]; ok {
+						t.Errorf("%s and %s share %s", other, entry.ChannelName, path)
+					}
+					seen[path] = entry.ChannelName
+				}
+			}
+		})
+	}
+}
+
+func TestExportLog_MultipleChannelsIncremental(t *testing.T) {
+	configPath, cleanup := setupTest(t)
+	defer cleanup()
+
+	tempDir := t.TempDir()
+	outputDir := filepath.Join(tempDir, "export")
+	statePath := filepath.Join(tempDir, "state.json")
+
+	rootCmd := newRootCmd()
+	rootCmd.AddCommand(newExportCmd())
+	_, stderr, err := testExecuteCommandAndCapture(rootCmd, "--config", configPath, "export", "log", "--all-channels", "--include-archived",
+		"--output", outputDir, "--output-format", "text", "--incremental", "--state", statePath)
+	if err != nil {
+		t.Fatalf("testExecuteCommandAndCapture returned an error: %v\nStderr: %s", err, stderr)
+	}
+
+	state, err := export.LoadState(statePath)
+	if err != nil {
+		t.Fatalf("Failed to load the state file: %v", err)
+	}
+	for _, name := range []string{"test-channel-1", "test-channel-2"} {
+		if ch := state.Channels[name]; ch == nil || ch.LatestTS != testprovider.TestExportMessageTS {
+			t.Errorf("Expected the state of %s to be recorded, got %+v", name, ch)
+		}
+		if _, err := os.Stat(filepath.Join(outputDir, name+".txt")); err != nil {
+			t.Errorf("Expected a text log for %s: %v", name, err)
+		}
+	}
+}
+
+func TestExportLog_ChannelSelectionErrors(t *testing.T) {
+	configPath, cleanup := setupTest(t)
+	defer cleanup()
+
+	outputDir := t.TempDir()
+	tests := []struct {
+		name        string
+		args        []string
+		expectedErr string
+	}{
+		{"no channel", nil, "at least one of the flags in the group [channel all-channels channel-regex] is required"},
+		{"several channels to stdout", []string{"--channel", "#a", "--channel", "#b"}, "exporting several channels requires --output to be a directory"},
+		{"all channels to stdout", []string{"--all-channels"}, "exporting several channels requires --output to be a directory"},
+		{"include archived alone", []string{"--channel", "#a", "--include-archived"}, "--include-archived can only be used with --all-channels or --channel-regex"},
+		{"no workers", []string{"--all-channels", "--output", outputDir, "--workers", "0"}, "--workers must be at least 1"},
+		{"no concurrency", []string{"--channel", "#a", "--concurrency", "0"}, "--concurrency must be at least 1"},
+		{"raw text", []string{"--channel", "#a", "--raw", "--output-format", "text"}, "--raw can only be used with --output-format json or ndjson"},
+		{"invalid regex", []string{"--channel-regex", "(", "--output", outputDir}, "invalid value for --channel-regex"},
+		{"no match", []string{"--channel-regex", "^nothing$", "--output", outputDir}, "no channels match the selection"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rootCmd := newRootCmd()
+			rootCmd.AddCommand(newExportCmd())
+			args := append([]string{"--config", configPath, "export", "log"}, tt.args...)
+			_, _, err := testExecuteCommandAndCapture(rootCmd, args...)
+			if err == nil || !strings.Contains(err.Error(), tt.expectedErr) {
+				t.Errorf("Expected error containing '%s', got: %v", tt.expectedErr, err)
+			}
+		})
+	}
+}
+
+func TestExportLog_SlackExportFormat(t *testing.T) {
+	configPath, cleanup := setupTest(t)
+	defer cleanup()
+
+	tests := []struct {
+		name          string
+		args          []string
+		output        string
+		expectedFiles []string
+	}{
+		{"single channel zip", []string{"--channel", "#test-channel"}, "export.zip", []string{"channels.json", "users.json", "test-channel/2023-01-01.json"}},
+		{"all channels directory", []string{"--all-channels", "--include-archived"}, "export", []string{"channels.json", "users.json", "test-channel-1/2023-01-01.json", "test-channel-2/2023-01-01.json"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			output := filepath.Join(t.TempDir(), tt.output)
+			rootCmd := newRootCmd()
+			rootCmd.AddCommand(newExportCmd())
+			args := append([]string{"--config", configPath, "export", "log", "--output-format", "slack-export", "--output", output}, tt.args...)
+			_, stderr, err := testExecuteCommandAndCapture(rootCmd, args...)
+			if err != nil {
+				t.Fatalf("testExecuteCommandAndCapture returned an error: %v\nStderr: %s", err, stderr)
+			}
+
+			files := map[string]bool{}
+			if filepath.Ext(output) == ".zip" {
+				zr, err := zip.OpenReader(output)
+				if err != nil {
+					t.Fatalf("Invalid zip archive: %v", err)
+				}
+				for _, f := range zr.File {
+					files[f.Name] = true
+				}
+				zr.Close()
+			} else {
+				_ = filepath.Walk(output, func(p string, info os.FileInfo, err error) error {
+					if err == nil && !info.IsDir() {
+						rel, _ := filepath.Rel(output, p)
+						files[filepath.ToSlash(rel)] = true
+					}
+					return err
+				})
+			}
+			if len(files) != len(tt.expectedFiles) {
+				t.Errorf("Expected %d files in the export, got %v", len(tt.expectedFiles), files)
+			}
+			for _, name := range tt.expectedFiles {
+				if !files[name] {
+					t.Errorf("Expected %s in the export, got %v", name, files)
+				}
+			}
+		})
+	}
+}
+
+func TestExportLog_SlackExportFormatErrors(t *testing.T) {
+	configPath, cleanup := setupTest(t)
+	defer cleanup()
+
+	tempDir := t.TempDir()
+	tests := []struct {
+		name        string
+		args        []string
+		expectedErr string
+	}{
+		{"stdout", nil, "--output-format slack-export requires --output"},
+		{"incremental", []string{"--output", filepath.Join(tempDir, "export.zip"), "--incremental", "--state", filepath.Join(tempDir, "state.json")}, "--incremental and --append cannot be used with --output-format slack-export"},
+		{"append", []string{"--output", filepath.Join(tempDir, "export.zip"), "--append"}, "--incremental and --append cannot be used with --output-format slack-export"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rootCmd := newRootCmd()
+			rootCmd.AddCommand(newExportCmd())
+			args := append([]string{"--config", configPath, "export", "log", "--channel", "#test-channel", "--output-format", "slack-export"}, tt.args...)
+			_, _, err := testExecuteCommandAndCapture(rootCmd, args...)
+			if err == nil || !strings.Contains(err.Error(), tt.expectedErr) {
+				t.Errorf("Expected error containing '%s', got: %v", tt.expectedErr, err)
+			}
+		})
+	}
+}
+
+func TestExportLog_HTMLFormat(t *testing.T) {
+	configPath, cleanup := setupTest(t)
+	defer cleanup()
+
+	outputFile := filepath.Join(t.TempDir(), "transcript.html")
+	rootCmd := newRootCmd()
+	rootCmd.AddCommand(newExportCmd())
+	_, stderr, err := testExecuteCommandAndCapture(rootCmd, "--config", configPath, "export", "log", "--channel", "#test-channel",
+		"--output", outputFile, "--output-format", "html", "--timezone", "Asia/Tokyo")
+	if err != nil {
+		t.Fatalf("testExecuteCommandAndCapture returned an error: %v\nStderr: %s", err, stderr)
+	}
+
+	content, err := os.ReadFile(outputFile)
+	if err != nil {
+		t.Fatalf("Failed to read output file: %v", err)
+	}
+	for _, want := range []string{"<!DOCTYPE html>", "<h1>#test-channel</h1>", "times in Asia/Tokyo", ">09:00:00</time>", "Test message from ExportLog"} {
+		if !strings.Contains(string(content), want) {
+			t.Errorf("Expected the transcript to contain %q, got:\n%s", want, content)
+		}
+	}
+}
+
+func TestExportLog_HTMLFormatErrors(t *testing.T) {
+	configPath, cleanup := setupTest(t)
+	defer cleanup()
+
+	outputFile := filepath.Join(t.TempDir(), "transcript.html")
+	tests := []struct {
+		name        string
+		args        []string
+		expectedErr string
+	}{
+		{"invalid timezone", []string{"--timezone", "Mars/Olympus_Mons"}, "invalid value for --timezone"},
+		{"append", []string{"--output", outputFile, "--append"}, "--incremental and --append cannot be used with --output-format html"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rootCmd := newRootCmd()
+			rootCmd.AddCommand(newExportCmd())
+			args := append([]string{"--config", configPath, "export", "log", "--channel", "#test-channel", "--output-format", "html"}, tt.args...)
+			_, _, err := testExecuteCommandAndCapture(rootCmd, args...)
+			if err == nil || !strings.Contains(err.Error(), tt.expectedErr) {
+				t.Errorf("Expected error containing '%s', got: %v", tt.expectedErr, err)
+			}
+		})
+	}
+}
+
+func TestExportLog_MarkdownAndCSVFormats(t *testing.T) {
+	configPath, cleanup := setupTest(t)
+	defer cleanup()
+
+	tests := []struct {
+		format string
+		want   []string
+	}{
+		{"markdown", []string{"# \\#test-channel", "## Sunday, January 1, 2023", "**testuser** · 09:00:00  \nTest message from ExportLog"}},
+		{"csv", []string{"timestamp,ts,thread_ts,is_reply,user_id,user_name,post_type,text,files\n", "2023-01-01T09:00:00+09:00,1672531200.000000,,false,", ",testuser,", ",Test message from ExportLog,"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			rootCmd := newRootCmd()
+			rootCmd.AddCommand(newExportCmd())
+			stdout, stderr, err := testExecuteCommandAndCapture(rootCmd, "--config", configPath, "export", "log", "--channel", "#test-channel",
+				"--output-format", tt.format, "--timezone", "Asia/Tokyo")
+			if err != nil {
+				t.Fatalf("testExecuteCommandAndCapture returned an error: %v\nStderr: %s", err, stderr)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(stdout, want) {
+					t.Errorf("Expected the output to contain %q, got:\n%s", want, stdout)
+				}
+			}
+		})
+	}
+
+	// --append is not supported by either format.
+	for _, format := range []string{"markdown", "csv"} {
+		rootCmd := newRootCmd()
+		rootCmd.AddCommand(newExportCmd())
+		outputFile := filepath.Join(t.TempDir(), "log"+logFileExt(format))
+		_, _, err := testExecuteCommandAndCapture(rootCmd, "--config", configPath, "export", "log", "--channel", "#test-channel",
+			"--output-format", format, "--output", outputFile, "--append")
+		want := "--incremental and --append cannot be used with --output-format " + format
+		if err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("Expected error containing '%s', got: %v", want, err)
+		}
+	}
+}
+
+func TestExportLog_NDJSONFormat(t *testing.T) {
+	configPath, cleanup := setupTest(t)
+	defer cleanup()
+
+	// Each message is a line of JSON on stdout.
+	rootCmd := newRootCmd()
+	rootCmd.AddCommand(newExportCmd())
+	stdout, stderr, err := testExecuteCommandAndCapture(rootCmd, "--config", configPath, "export", "log", "--channel", "#test-channel", "--output-format", "ndjson")
+	if err != nil {
+		t.Fatalf("testExecuteCommandAndCapture returned an error: %v\nStderr: %s", err, stderr)
+	}
+	var msg export.ExportedMessage
+	if err := json.Unmarshal([]byte(stdout), &msg); err != nil {
+		t.Fatalf("Output is not a line of JSON: %v\n%s", err, stdout)
+	}
+	if msg.Text != "Test message from ExportLog" || !strings.HasSuffix(stdout, "}\n") {
+		t.Errorf("Unexpected output: %q", stdout)
+	}
+
+	// Incremental runs and --append add lines to the file.
+	tempDir := t.TempDir()
+	outputFile := filepath.Join(tempDir, "log.ndjson")
+	for _, args := range [][]string{
+		{"--incremental", "--state", filepath.Join(tempDir, "state.json")},
+		{"--append"},
+	} {
+		rootCmd := newRootCmd()
+		rootCmd.AddCommand(newExportCmd())
+		args = append([]string{"--config", configPath, "export", "log", "--channel", "#test-channel", "--output-format", "ndjson", "--output", outputFile}, args...)
+		if _, stderr, err := testExecuteCommandAndCapture(rootCmd, args...); err != nil {
+			t.Fatalf("testExecuteCommandAndCapture returned an error: %v\nStderr: %s", err, stderr)
+		}
+	}
+	data, err := os.ReadFile(outputFile)
+	if err != nil {
+		t.Fatalf("Failed to read output file: %v", err)
+	}
+	lines := strings.Split(strings.TrimSuffix(string(data), "\n"), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("Expected 2 lines in the output file, got %d:\n%s", len(lines), data)
+	}
+	for i, line := range lines {
+		if err := json.Unmarshal([]byte(line), &msg); err != nil {
+			t.Errorf("Line %d is not valid JSON: %v", i+1, err)
+		}
+	}
+}
+
+func TestExportLog_Concurrency(t *testing.T) {
+	configPath, cleanup := setupTest(t)
+	defer cleanup()
+
+	rootCmd := newRootCmd()
+	rootCmd.AddCommand(newExportCmd())
+	_, stderr, err := testExecuteCommandAndCapture(rootCmd, "--config", configPath, "export", "log", "--channel", "#test-channel", "--concurrency", "8")
+	if err != nil {
+		t.Fatalf("testExecuteCommandAndCapture returned an error: %v\nStderr: %s", err, stderr)
+	}
+	if !strings.Contains(stderr, "[TESTPROVIDER] ExportLog concurrency: 8") {
+		t.Errorf("Expected the concurrency to be passed to the provider, got: %s", stderr)
+	}
+}
+
+func TestExportLog_Raw(t *testing.T) {
+	configPath, cleanup := setupTest(t)
+	defer cleanup()
+
+	for _, format := range []string{"json", "ndjson"} {
+		t.Run(format, func(t *testing.T) {
+			outputPath := filepath.Join(t.TempDir(), "log."+format)
+			rootCmd := newRootCmd()
+			rootCmd.AddCommand(newExportCmd())
+			_, stderr, err := testExecuteCommandAndCapture(rootCmd, "--config", configPath, "export", "log", "--channel", "#test-channel", "--output-format", format, "--output", outputPath, "--raw")
+			if err != nil {
+				t.Fatalf("testExecuteCommandAndCapture returned an error: %v\nStderr: %s", err, stderr)
+			}
+			if !strings.Contains(stderr, "[TESTPROVIDER] ExportLog include raw: true") {
+				t.Errorf("Expected --raw to be passed to the provider, got: %s", stderr)
+			}
+
+			data, err := os.ReadFile(outputPath)
+			if err != nil {
+				t.Fatalf("Failed to read output file: %v", err)
+			}
+			var msg export.ExportedMessage
+			if format == "json" {
+				var log export.ExportedLog
+				if err := json.Unmarshal(data, &log); err != nil {
+					t.Fatalf("Failed to parse output: %v", err)
+				}
+				if log.FormatVersion != export.FormatVersion {
+					t.Errorf("FormatVersion = %d, want %d", log.FormatVersion, export.FormatVersion)
+				}
+				if len(log.Messages) != 1 {
+					t.Fatalf("Expected 1 message, got %d", len(log.Messages))
+				}
+				msg = log.Messages[0]
+			} else if err := json.Unmarshal(data, &msg); err != nil {
+				t.Fatalf("Failed to parse output: %v", err)
+			}
+			var raw map[string]any
+			if err := json.Unmarshal(msg.Raw, &raw); err != nil {
+				t.Fatalf("Expected the raw message in the output, got %q: %v", msg.Raw, err)
+			}
+			if raw["ts"] != testprovider.TestExportMessageTS {
+				t.Errorf("Unexpected raw message: %v", raw)
+			}
+		})
+	}
+}
+
+func TestLogExport_ReportFailedDownloads(t *testing.T) {
+	e := &logExport{}
+	e.recordFailedDownloads("#random", []export.ExportedMessage{
+		{Files: []export.ExportedFile{{ID: "F02", Name: "gone.txt", DownloadError: "download failed with status code 404"}}},
+	})
+	e.recordFailedDownloads("#general", []export.ExportedMessage{
+		{Files: []export.ExportedFile{{ID: "F01", Name: "ok.txt", LocalPath: "files/F01_ok.txt"}}},
+		{Files: []export.ExportedFile{{ID: "F03", Name: "secret.pdf", DownloadError: "downloaded 20 bytes, but the file has 1000"}}},
+	})
+	if n := e.failedDownloadCount("#general"); n != 1 {
+		t.Errorf("failedDownloadCount(#general) = %d, want 1", n)
+	}
+
+	oldStderr := os.Stderr
+	r, w, _ := os.Pipe()
+	os.Stderr = w
+	e.reportFailedDownloads()
+	_ = w.Close()
+	os.Stderr = oldStderr
+	out, _ := io.ReadAll(r)
+
+	want := "Warning: 2 attachments could not be downloaded:\n" +
+		"  #general: secret.pdf (F03): downloaded 20 bytes, but the file has 1000\n" +
+		"  #random: gone.txt (F02): download failed with status code 404\n"
+	if string(out) != want {
+		t.Errorf("reportFailedDownloads() printed:\n%s\nwant:\n%s", out, want)
+	}
+}
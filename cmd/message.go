@@ -0,0 +1,23 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// newMessageCmd creates the command for managing posted messages.
+func newMessageCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "message",
+		Short: "Update or delete previously posted messages",
+		Long:  `The message command and its subcommands operate on messages that have already been posted. A message is identified by its timestamp (ts) together with --channel, or by its permalink.`,
+		Run: func(cmd *cobra.Command, args []string) {
+			_ = cmd.Help()
+		},
+	}
+
+	// Add subcommands
+	cmd.AddCommand(newMessageUpdateCmd()) // from message_update.go
+	cmd.AddCommand(newMessageDeleteCmd()) // from message_delete.go
+
+	return cmd
+}
@@ -0,0 +1,68 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/nlink-jp/scat/internal/appcontext"
+	"github.com/nlink-jp/scat/internal/provider"
+	"github.com/spf13/cobra"
+)
+
+func newMessageDeleteCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "delete <ts|permalink>",
+		Short: "Delete a posted message",
+		Long:  `Deletes a posted message, identified by its timestamp (ts) together with --channel, or by its permalink.`,
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			appCtx := cmd.Context().Value(appcontext.CtxKey).(appcontext.Context)
+
+			cfg := appCtx.Config
+			if cfg == nil {
+				return fmt.Errorf("configuration file not found. Please run 'scat config init' to create a default configuration")
+			}
+
+			profileName, _ := cmd.Flags().GetString("profile")
+			if profileName == "" {
+				profileName = cfg.CurrentProfile
+			}
+			profile, ok := cfg.Profiles[profileName]
+			if !ok {
+				return fmt.Errorf("profile '%s' not found", profileName)
+			}
+
+			channel, _ := cmd.Flags().GetString("channel")
+			channel, ts, err := resolveMessageRef(args[0], channel)
+			if err != nil {
+				return err
+			}
+
+			prov, err := GetProvider(appCtx, profile)
+			if err != nil {
+				return err
+			}
+			if !prov.Capabilities().CanEditMessages {
+				return fmt.Errorf("the provider for profile '%s' does not support deleting messages", profileName)
+			}
+
+			opts := provider.DeleteMessageOptions{
+				Channel:   channel,
+				Timestamp: ts,
+			}
+			if err := prov.DeleteMessage(cmd.Context(), opts); err != nil {
+				return fmt.Errorf("failed to delete message: %w", err)
+			}
+			if !appCtx.Silent {
+				fmt.Fprintf(os.Stderr, "Message %s deleted successfully.\n", ts)
+			}
+
+			return nil
+		},
+	}
+
+	cmd.Flags().StringP("profile", "p", "", "Profile to use for this command")
+	cmd.Flags().StringP("channel", "c", "", "Channel containing the message (not needed when a permalink is given)")
+
+	return cmd
+}
@@ -0,0 +1,117 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestMessageUpdateCmd(t *testing.T) {
+	configPath, cleanup := setupTest(t)
+	defer cleanup()
+
+	tests := []struct {
+		name       string
+		args       []string
+		wantErr    bool
+		wantOutput string
+	}{
+		{
+			name:       "update by channel and ts",
+			args:       []string{"message", "update", "1700000000.000100", "--channel", "#deploys", "deployed"},
+			wantOutput: "UpdateMessage called with opts: {Channel:#deploys Timestamp:1700000000.000100 Text:deployed Blocks:}",
+		},
+		{
+			name:       "update by permalink",
+			args:       []string{"message", "update", "https://example.slack.com/archives/C0123ABCD/p1700000000000100", "deployed"},
+			wantOutput: "UpdateMessage called with opts: {Channel:C0123ABCD Timestamp:1700000000.000100 Text:deployed Blocks:}",
+		},
+		{
+			name:       "update with blocks",
+			args:       []string{"message", "update", "1700000000.000100", "--format", "blocks", `{"blocks": [{"type": "divider"}]}`},
+			wantOutput: `UpdateMessage called with opts: {Channel: Timestamp:1700000000.000100 Text: Blocks:[{"type": "divider"}]}`,
+		},
+		{
+			name:       "invalid message reference",
+			args:       []string{"message", "update", "not-a-ts", "deployed"},
+			wantErr:    true,
+			wantOutput: "invalid message reference",
+		},
+		{
+			name:       "missing message reference",
+			args:       []string{"message", "update"},
+			wantErr:    true,
+			wantOutput: "requires at least 1 arg(s)",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rootCmd := newRootCmd()
+			rootCmd.AddCommand(newMessageCmd())
+
+			_, stderr, err := testExecuteCommandAndCapture(rootCmd, append([]string{"--config", configPath}, tt.args...)...)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Execute() error = %v, wantErr %v\nStderr: %s", err, tt.wantErr, stderr)
+			}
+			if tt.wantErr {
+				if !strings.Contains(err.Error(), tt.wantOutput) {
+					t.Errorf("Expected error to contain '%s', got: %v", tt.wantOutput, err)
+				}
+				return
+			}
+			if !strings.Contains(stderr, tt.wantOutput) {
+				t.Errorf("Expected stderr to contain '%s', got: '%s'", tt.wantOutput, stderr)
+			}
+		})
+	}
+}
+
+func TestMessageDeleteCmd(t *testing.T) {
+	configPath, cleanup := setupTest(t)
+	defer cleanup()
+
+	tests := []struct {
+		name       string
+		args       []string
+		wantErr    bool
+		wantOutput string
+	}{
+		{
+			name:       "delete by channel and ts",
+			args:       []string{"message", "delete", "1700000000.000100", "--channel", "#deploys"},
+			wantOutput: "DeleteMessage called with opts: {Channel:#deploys Timestamp:1700000000.000100}",
+		},
+		{
+			name:       "delete by permalink",
+			args:       []string{"message", "delete", "https://example.slack.com/archives/C0123ABCD/p1700000000000100"},
+			wantOutput: "DeleteMessage called with opts: {Channel:C0123ABCD Timestamp:1700000000.000100}",
+		},
+		{
+			name:       "too many arguments",
+			args:       []string{"message", "delete", "1700000000.000100", "extra"},
+			wantErr:    true,
+			wantOutput: "accepts 1 arg(s), received 2",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rootCmd := newRootCmd()
+			rootCmd.AddCommand(newMessageCmd())
+
+			_, stderr, err := testExecuteCommandAndCapture(rootCmd, append([]string{"--config", configPath}, tt.args...)...)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Execute() error = %v, wantErr %v\nStderr: %s", err, tt.wantErr, stderr)
+			}
+			if tt.wantErr {
+				if !strings.Contains(err.Error(), tt.wantOutput) {
+					t.Errorf("Expected error to contain '%s', got: %v", tt.wantOutput, err)
+				}
+				return
+			}
+			if !strings.Contains(stderr, tt.wantOutput) {
+				t.Errorf("Expected stderr to contain '%s', got: '%s'", tt.wantOutput, stderr)
+			}
+		})
+	}
+}
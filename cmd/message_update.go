@@ -0,0 +1,104 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/nlink-jp/scat/internal/appcontext"
+	"github.com/nlink-jp/scat/internal/provider"
+	"github.com/spf13/cobra"
+)
+
+func newMessageUpdateCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "update <ts|permalink> [new text]",
+		Short: "Replace the text or blocks of a posted message",
+		Long: `Replaces the content of a posted message.
+
+The message is identified by its timestamp (ts) together with --channel, or by its permalink.
+The new content is sourced in the same order as 'scat post': 1. Command-line arguments. 2. --from-file flag. 3. Standard input.`,
+		Args: cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			appCtx := cmd.Context().Value(appcontext.CtxKey).(appcontext.Context)
+
+			cfg := appCtx.Config
+			if cfg == nil {
+				return fmt.Errorf("configuration file not found. Please run 'scat config init' to create a default configuration")
+			}
+
+			profileName, _ := cmd.Flags().GetString("profile")
+			if profileName == "" {
+				profileName = cfg.CurrentProfile
+			}
+			profile, ok := cfg.Profiles[profileName]
+			if !ok {
+				return fmt.Errorf("profile '%s' not found", profileName)
+			}
+
+			channel, _ := cmd.Flags().GetString("channel")
+			fromFile, _ := cmd.Flags().GetString("from-file")
+			format, _ := cmd.Flags().GetString("format")
+			jsonOutput, _ := cmd.Flags().GetBool("json")
+
+			if format != "text" && format != "blocks" {
+				return fmt.Errorf("invalid value for --format: %s. Must be 'text' or 'blocks'", format)
+			}
+
+			channel, ts, err := resolveMessageRef(args[0], channel)
+			if err != nil {
+				return err
+			}
+
+			prov, err := GetProvider(appCtx, profile)
+			if err != nil {
+				return err
+			}
+			if !prov.Capabilities().CanEditMessages {
+				return fmt.Errorf("the provider for profile '%s' does not support updating messages", profileName)
+			}
+			if format == "blocks" && !prov.Capabilities().CanPostBlocks {
+				return fmt.Errorf("the provider for profile '%s' does not support posting Block Kit messages", profileName)
+			}
+
+			content, err := readMessageContent(args[1:], fromFile, profile.Limits.MaxStdinSizeBytes)
+			if err != nil {
+				return err
+			}
+
+			opts := provider.UpdateMessageOptions{
+				Channel:   channel,
+				Timestamp: ts,
+				Text:      content,
+			}
+			if format == "blocks" {
+				blocks, err := parseBlocks(content)
+				if err != nil {
+					return err
+				}
+				opts.Text = ""
+				opts.Blocks = blocks
+			}
+
+			result, err := prov.UpdateMessage(cmd.Context(), opts)
+			if err != nil {
+				return fmt.Errorf("failed to update message: %w", err)
+			}
+			if !appCtx.Silent {
+				fmt.Fprintf(os.Stderr, "Message %s updated successfully.\n", ts)
+			}
+			if jsonOutput {
+				return printJSON(result)
+			}
+
+			return nil
+		},
+	}
+
+	cmd.Flags().StringP("profile", "p", "", "Profile to use for this command")
+	cmd.Flags().StringP("channel", "c", "", "Channel containing the message (not needed when a permalink is given)")
+	cmd.Flags().String("from-file", "", "Read the new message body from a file")
+	cmd.Flags().String("format", "text", "Message format (text or blocks)")
+	cmd.Flags().Bool("json", false, "Print the channel ID, timestamp, and permalink of the updated message as JSON")
+
+	return cmd
+}
@@ -0,0 +1,24 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// newPinCmd creates the command for managing pinned messages.
+func newPinCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "pin",
+		Short: "Pin, unpin, or list pinned messages in a channel",
+		Long:  `The pin command and its subcommands manage the messages pinned to a channel. A message is identified by its timestamp (ts) together with --channel, or by its permalink.`,
+		Run: func(cmd *cobra.Command, args []string) {
+			_ = cmd.Help()
+		},
+	}
+
+	// Add subcommands
+	cmd.AddCommand(newPinAddCmd())    // from pin_add.go
+	cmd.AddCommand(newPinRemoveCmd()) // from pin_add.go
+	cmd.AddCommand(newPinListCmd())   // from pin_list.go
+
+	return cmd
+}
@@ -0,0 +1,84 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/nlink-jp/scat/internal/appcontext"
+	"github.com/nlink-jp/scat/internal/provider"
+	"github.com/spf13/cobra"
+)
+
+func newPinAddCmd() *cobra.Command {
+	return newPinChangeCmd("add", "Pin a message to its channel", "pinned",
+		func(ctx context.Context, prov provider.Interface, opts provider.PinOptions) error {
+			return prov.AddPin(ctx, opts)
+		})
+}
+
+func newPinRemoveCmd() *cobra.Command {
+	return newPinChangeCmd("remove", "Unpin a message from its channel", "unpinned",
+		func(ctx context.Context, prov provider.Interface, opts provider.PinOptions) error {
+			return prov.RemovePin(ctx, opts)
+		})
+}
+
+// newPinChangeCmd builds the add and remove subcommands, which differ only in
+// the provider method they call.
+func newPinChangeCmd(use, short, verb string, apply func(context.Context, provider.Interface, provider.PinOptions) error) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   use + " <ts|permalink>",
+		Short: short,
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			appCtx := cmd.Context().Value(appcontext.CtxKey).(appcontext.Context)
+
+			cfg := appCtx.Config
+			if cfg == nil {
+				return fmt.Errorf("configuration file not found. Please run 'scat config init' to create a default configuration")
+			}
+
+			profileName, _ := cmd.Flags().GetString("profile")
+			if profileName == "" {
+				profileName = cfg.CurrentProfile
+			}
+			profile, ok := cfg.Profiles[profileName]
+			if !ok {
+				return fmt.Errorf("profile '%s' not found", profileName)
+			}
+
+			channel, _ := cmd.Flags().GetString("channel")
+			channel, ts, err := resolveMessageRef(args[0], channel)
+			if err != nil {
+				return err
+			}
+
+			prov, err := GetProvider(appCtx, profile)
+			if err != nil {
+				return err
+			}
+			if !prov.Capabilities().CanPin {
+				return fmt.Errorf("the provider for profile '%s' does not support pinning messages", profileName)
+			}
+
+			opts := provider.PinOptions{
+				Channel:   channel,
+				Timestamp: ts,
+			}
+			if err := apply(cmd.Context(), prov, opts); err != nil {
+				return fmt.Errorf("failed to %s pin: %w", use, err)
+			}
+			if !appCtx.Silent {
+				fmt.Fprintf(os.Stderr, "Message %s %s successfully.\n", ts, verb)
+			}
+
+			return nil
+		},
+	}
+
+	cmd.Flags().StringP("profile", "p", "", "Profile to use for this command")
+	cmd.Flags().StringP("channel", "c", "", "Channel containing the message (not needed when a permalink is given)")
+
+	return cmd
+}
@@ -0,0 +1,82 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/nlink-jp/scat/internal/appcontext"
+	"github.com/nlink-jp/scat/internal/provider"
+	"github.com/spf13/cobra"
+)
+
+// pinListTextWidth is the number of characters of message text shown in the table.
+const pinListTextWidth = 50
+
+func newPinListCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List messages pinned to a channel",
+		Long:  `Lists the messages pinned to the channel given with --channel, or to the profile's default channel.`,
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			appCtx := cmd.Context().Value(appcontext.CtxKey).(appcontext.Context)
+
+			cfg := appCtx.Config
+			if cfg == nil {
+				return fmt.Errorf("configuration file not found. Please run 'scat config init' to create a default configuration")
+			}
+
+			profileName, _ := cmd.Flags().GetString("profile")
+			if profileName == "" {
+				profileName = cfg.CurrentProfile
+			}
+			profile, ok := cfg.Profiles[profileName]
+			if !ok {
+				return fmt.Errorf("profile '%s' not found", profileName)
+			}
+
+			channel, _ := cmd.Flags().GetString("channel")
+			jsonOutput, _ := cmd.Flags().GetBool("json")
+
+			prov, err := GetProvider(appCtx, profile)
+			if err != nil {
+				return err
+			}
+			if !prov.Capabilities().CanPin {
+				return fmt.Errorf("the provider for profile '%s' does not support pinning messages", profileName)
+			}
+
+			pins, err := prov.ListPins(cmd.Context(), provider.ListPinsOptions{Channel: channel})
+			if err != nil {
+				return fmt.Errorf("failed to list pinned messages: %w", err)
+			}
+
+			if jsonOutput {
+				if pins == nil {
+					pins = []provider.PinnedMessage{}
+				}
+				return printJSON(pins)
+			}
+
+			if len(pins) == 0 {
+				if !appCtx.Silent {
+					fmt.Fprintln(os.Stderr, "No pinned messages.")
+				}
+				return nil
+			}
+			w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+			fmt.Fprintln(w, "TS\tUSER\tPINNED AT\tTEXT")
+			for _, pin := range pins {
+				fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", pin.Timestamp, pin.UserID, displayTime(pin.PinnedAt.Local(), "-"), summarizeText(pin.Text, pinListTextWidth))
+			}
+			return w.Flush()
+		},
+	}
+
+	cmd.Flags().StringP("profile", "p", "", "Profile to use for this command")
+	cmd.Flags().StringP("channel", "c", "", "Channel to list pinned messages for")
+	cmd.Flags().Bool("json", false, "Output in JSON format")
+
+	return cmd
+}
@@ -0,0 +1,104 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/nlink-jp/scat/internal/provider/testprovider"
+)
+
+func TestPinCmd(t *testing.T) {
+	configPath, cleanup := setupTest(t)
+	defer cleanup()
+
+	tests := []struct {
+		name       string
+		args       []string
+		wantErr    bool
+		wantStdout string
+		wantStderr string
+	}{
+		{
+			name:       "add by channel and ts",
+			args:       []string{"pin", "add", "1700000000.000100", "--channel", "#releases"},
+			wantStderr: "AddPin called with opts: {Channel:#releases Timestamp:1700000000.000100}",
+		},
+		{
+			name:       "remove by permalink",
+			args:       []string{"pin", "remove", "https://example.slack.com/archives/C0123ABCD/p1700000000000100"},
+			wantStderr: "RemovePin called with opts: {Channel:C0123ABCD Timestamp:1700000000.000100}",
+		},
+		{
+			name:       "list",
+			args:       []string{"pin", "list", "--channel", "#releases"},
+			wantStdout: testprovider.TestMessageTS,
+			wantStderr: "ListPins called with opts: {Channel:#releases}",
+		},
+		{
+			name:       "list as json",
+			args:       []string{"pin", "list", "--json"},
+			wantStdout: `"pinned_by": "U0000000002"`,
+		},
+		{
+			name:       "invalid message reference",
+			args:       []string{"pin", "add", "not-a-ts"},
+			wantErr:    true,
+			wantStderr: "invalid message reference",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rootCmd := newRootCmd()
+			rootCmd.AddCommand(newPinCmd())
+
+			stdout, stderr, err := testExecuteCommandAndCapture(rootCmd, append([]string{"--config", configPath}, tt.args...)...)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Execute() error = %v, wantErr %v\nStderr: %s", err, tt.wantErr, stderr)
+			}
+			if tt.wantErr {
+				if !strings.Contains(err.Error(), tt.wantStderr) {
+					t.Errorf("Expected error to contain '%s', got: %v", tt.wantStderr, err)
+				}
+				return
+			}
+			if !strings.Contains(stdout, tt.wantStdout) {
+				t.Errorf("Expected stdout to contain '%s', got: %s", tt.wantStdout, stdout)
+			}
+			if !strings.Contains(stderr, tt.wantStderr) {
+				t.Errorf("Expected stderr to contain '%s', got: %s", tt.wantStderr, stderr)
+			}
+		})
+	}
+}
+
+func TestPost_Pin(t *testing.T) {
+	configPath, cleanup := setupTest(t)
+	defer cleanup()
+
+	rootCmd := newRootCmd()
+	rootCmd.AddCommand(newPostCmd())
+
+	_, stderr, err := testExecuteCommandAndCapture(rootCmd, "--config", configPath, "post", "--pin", "Release notes v1.15.0")
+	if err != nil {
+		t.Fatalf("Execute() returned an unexpected error: %v\nStderr: %s", err, stderr)
+	}
+
+	expected := "[TESTPROVIDER] AddPin called with opts: {Channel:" + testprovider.TestChannelID + " Timestamp:" + testprovider.TestMessageTS + "}"
+	if !strings.Contains(stderr, expected) {
+		t.Errorf("Expected stderr to contain '%s', got: %s", expected, stderr)
+	}
+}
+
+func TestPost_PinWithScheduleError(t *testing.T) {
+	configPath, cleanup := setupTest(t)
+	defer cleanup()
+
+	rootCmd := newRootCmd()
+	rootCmd.AddCommand(newPostCmd())
+
+	_, _, err := testExecuteCommandAndCapture(rootCmd, "--config", configPath, "post", "--pin", "--in", "1h", "hello")
+	if err == nil || !strings.Contains(err.Error(), "cannot use --pin with --at or --in") {
+		t.Errorf("Expected error about --pin with --in, got: %v", err)
+	}
+}
@@ -2,7 +2,9 @@ package cmd
 
 import (
 	"bufio"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"os"
@@ -46,11 +48,57 @@ func newPostCmd() *cobra.Command {
 			tee, _ := cmd.Flags().GetBool("tee")
 			fromFile, _ := cmd.Flags().GetString("from-file")
 			format, _ := cmd.Flags().GetString("format")
+			jsonOutput, _ := cmd.Flags().GetBool("json")
+			threadTSFlag, _ := cmd.Flags().GetString("thread-ts")
+			broadcast, _ := cmd.Flags().GetBool("broadcast")
+			threadKey, _ := cmd.Flags().GetString("thread-key")
+			threadKeyTTL, _ := cmd.Flags().GetDuration("thread-key-ttl")
+			atFlag, _ := cmd.Flags().GetString("at")
+			inFlag, _ := cmd.Flags().GetDuration("in")
+			ephemeralTo, _ := cmd.Flags().GetString("ephemeral-to")
+			pin, _ := cmd.Flags().GetBool("pin")
 
 			// --- Flag Validation and Exclusive Handling ---
 			if user != "" && channel != "" {
 				return fmt.Errorf("cannot use --user and --channel flags simultaneously")
 			}
+			if broadcast && threadTSFlag == "" {
+				return fmt.Errorf("--broadcast requires --thread-ts")
+			}
+			if threadKey != "" && threadTSFlag != "" {
+				return fmt.Errorf("cannot use --thread-key and --thread-ts flags simultaneously")
+			}
+			if threadKeyTTL <= 0 {
+				return fmt.Errorf("invalid value for --thread-key-ttl: must be positive")
+			}
+			postAt, err := resolvePostAt(atFlag, inFlag, time.Now())
+			if err != nil {
+				return err
+			}
+			if !postAt.IsZero() && threadKey != "" {
+				return fmt.Errorf("cannot use --thread-key with --at or --in")
+			}
+			if ephemeralTo != "" {
+				switch {
+				case user != "":
+					return fmt.Errorf("cannot use --ephemeral-to with --user; ephemeral messages are posted in a channel")
+				case !postAt.IsZero():
+					return fmt.Errorf("cannot use --ephemeral-to with --at or --in")
+				case threadKey != "":
+					return fmt.Errorf("cannot use --ephemeral-to with --thread-key")
+				case broadcast:
+					return fmt.Errorf("cannot use --ephemeral-to with --broadcast")
+				case pin:
+					return fmt.Errorf("cannot use --ephemeral-to with --pin")
+				}
+			}
+			if pin && !postAt.IsZero() {
+				return fmt.Errorf("cannot use --pin with --at or --in")
+			}
+			threadTS, channel, err := resolveThreadTS(threadTSFlag, channel, user)
+			if err != nil {
+				return err
+			}
 
 			// Get provider instance
 			prov, err := GetProvider(appCtx, profile)
@@ -70,62 +118,64 @@ func newPostCmd() *cobra.Command {
 				return fmt.Errorf("cannot use --stream with --format blocks")
 			}
 
+			if stream && jsonOutput {
+				return fmt.Errorf("cannot use --json with --stream")
+			}
+
+			if stream && threadKey != "" {
+				return fmt.Errorf("cannot use --thread-key with --stream")
+			}
+
+			if stream && !postAt.IsZero() {
+				return fmt.Errorf("cannot use --at or --in with --stream")
+			}
+
+			if stream && ephemeralTo != "" {
+				return fmt.Errorf("cannot use --ephemeral-to with --stream")
+			}
+
+			if stream && pin {
+				return fmt.Errorf("cannot use --pin with --stream")
+			}
+
+			if pin && !prov.Capabilities().CanPin {
+				return fmt.Errorf("the provider for profile '%s' does not support pinning messages", profileName)
+			}
+
+			if ephemeralTo != "" && !prov.Capabilities().CanPostEphemeral {
+				return fmt.Errorf("the provider for profile '%s' does not support ephemeral messages", profileName)
+			}
+
+			if !postAt.IsZero() && !prov.Capabilities().CanScheduleMessages {
+				return fmt.Errorf("the provider for profile '%s' does not support scheduling messages", profileName)
+			}
+
 			if stream {
-				return handleStream(prov, channel, user, profileName, username, iconEmoji, tee, appCtx.Silent)
+				streamOpts := provider.PostMessageOptions{
+					TargetChannel:    channel,
+					TargetUserID:     user,
+					OverrideUsername: username,
+					IconEmoji:        iconEmoji,
+					ThreadTS:         threadTS,
+					ReplyBroadcast:   broadcast,
+				}
+				return handleStream(cmd.Context(), prov, streamOpts, profileName, tee, appCtx.Silent)
 			}
 
 			// --- Determine message content and format ---
-			var content string
 			var blocks json.RawMessage
 
 			// Read content from args, file, or stdin
-			if len(args) > 0 {
-				content = strings.Join(args, " ")
-			} else if fromFile != "" {
-				fileContent, err := os.ReadFile(fromFile)
-				if err != nil {
-					return fmt.Errorf("failed to read from file %s: %w", fromFile, err)
-				}
-				content = string(fileContent)
-			} else {
-				stat, _ := os.Stdin.Stat()
-				if (stat.Mode() & os.ModeCharDevice) == 0 {
-					limit := profile.Limits.MaxStdinSizeBytes
-					var limitedReader io.Reader = os.Stdin
-					if limit > 0 {
-						limitedReader = io.LimitReader(os.Stdin, limit+1)
-					}
-					stdinContent, err := io.ReadAll(limitedReader)
-					if err != nil {
-						return fmt.Errorf("failed to read from stdin: %w", err)
-					}
-					if limit > 0 && int64(len(stdinContent)) > limit {
-						return fmt.Errorf("stdin size exceeds the configured limit (%d bytes)", limit)
-					}
-					content = string(stdinContent)
-				} else {
-					return fmt.Errorf("no message content provided via argument, --from-file, or stdin")
-				}
+			content, err := readMessageContent(args, fromFile, profile.Limits.MaxStdinSizeBytes)
+			if err != nil {
+				return err
 			}
 
 			// If format is blocks, parse content as JSON
 			if format == "blocks" {
-				// Attempt to unmarshal into a temporary map to check for the "blocks" key
-				var tempMap map[string]json.RawMessage
-				if err := json.Unmarshal([]byte(content), &tempMap); err != nil {
-					// If it's not a map, or unmarshalling fails, try to unmarshal directly as an array
-					var tempArray []interface{}
-					if err := json.Unmarshal([]byte(content), &tempArray); err != nil {
-						return fmt.Errorf("failed to parse block kit JSON: expected a JSON object with a 'blocks' key or a JSON array of blocks: %w", err)
-					}
-					// If it's a direct array, use the content as is
-					blocks = json.RawMessage(content)
-				} else if rawBlocks, ok := tempMap["blocks"]; ok {
-					// If it's a map with a "blocks" key, extract the value of "blocks"
-					blocks = rawBlocks
-				} else {
-					// If it's a map but no "blocks" key, it's an invalid format for Block Kit
-					return fmt.Errorf("failed to parse block kit JSON: expected a JSON object with a 'blocks' key or a JSON array of blocks")
+				blocks, err = parseBlocks(content)
+				if err != nil {
+					return err
 				}
 			}
 
@@ -142,6 +192,10 @@ func newPostCmd() *cobra.Command {
 				OverrideUsername: username,
 				IconEmoji:        iconEmoji,
 				Blocks:           blocks,
+				ThreadTS:         threadTS,
+				ReplyBroadcast:   broadcast,
+				PostAt:           postAt,
+				EphemeralUserID:  ephemeralTo,
 			}
 			// If blocks are present, clear text to ensure blocks are prioritized by provider
 			if len(opts.Blocks) > 0 {
@@ -153,11 +207,39 @@ func newPostCmd() *cobra.Command {
 				return fmt.Errorf("the provider for profile '%s' does not support posting Block Kit messages", profileName)
 			}
 
-			if err := prov.PostMessage(opts); err != nil {
+			var result *provider.PostResult
+			if threadKey != "" {
+				result, err = postWithThreadKey(cmd.Context(), appCtx, prov, profileName, threadKey, threadKeyTTL, opts)
+			} else {
+				result, err = prov.PostMessage(cmd.Context(), opts)
+			}
+			if err != nil {
 				return fmt.Errorf("failed to post message: %w", err)
 			}
 			if !appCtx.Silent {
-				fmt.Fprintf(os.Stderr, "Message posted successfully to profile '%s'.\n", profileName)
+				switch {
+				case ephemeralTo != "":
+					fmt.Fprintf(os.Stderr, "Ephemeral message for '%s' posted successfully to profile '%s'.\n", ephemeralTo, profileName)
+				case postAt.IsZero():
+					fmt.Fprintf(os.Stderr, "Message posted successfully to profile '%s'.\n", profileName)
+				default:
+					fmt.Fprintf(os.Stderr, "Message scheduled for %s to profile '%s' (ID: %s).\n", postAt.Format(time.RFC3339), profileName, result.ScheduledMessageID)
+				}
+			}
+			if pin {
+				if result.Timestamp == "" {
+					return fmt.Errorf("message posted, but cannot pin it: the provider did not report its timestamp")
+				}
+				pinOpts := provider.PinOptions{Channel: result.ChannelID, Timestamp: result.Timestamp}
+				if err := prov.AddPin(cmd.Context(), pinOpts); err != nil {
+					return fmt.Errorf("message posted, but failed to pin it: %w", err)
+				}
+				if !appCtx.Silent {
+					fmt.Fprintln(os.Stderr, "Message pinned successfully.")
+				}
+			}
+			if jsonOutput {
+				return printJSON(result)
 			}
 
 			return nil
@@ -173,11 +255,26 @@ func newPostCmd() *cobra.Command {
 	cmd.Flags().StringP("username", "u", "", "Override the username for this post")
 	cmd.Flags().StringP("iconemoji", "i", "", "Icon emoji to use for the post (slack provider only)")
 	cmd.Flags().String("format", "text", "Message format (text or blocks)")
+	cmd.Flags().String("thread-ts", "", "Reply in a thread, given the parent message's timestamp or permalink")
+	cmd.Flags().Bool("broadcast", false, "Also send the thread reply to the channel (requires --thread-ts)")
+	cmd.Flags().String("thread-key", "", "Post into the thread remembered under this key, starting it if needed")
+	cmd.Flags().Duration("thread-key-ttl", defaultThreadKeyTTL, "How long an unused thread key is remembered")
+	cmd.Flags().String("at", "", "Schedule the message for a time (RFC3339, e.g. 2026-11-01T09:00:00+09:00)")
+	cmd.Flags().Duration("in", 0, "Schedule the message after a delay (e.g. 30m, 2h)")
+	cmd.Flags().String("ephemeral-to", "", "Post the message so that only this user (ID or mention name) can see it in the channel")
+	cmd.Flags().Bool("pin", false, "Pin the message to the channel after posting it")
+	cmd.Flags().Bool("json", false, "Print the channel ID, timestamp, and permalink of the posted message as JSON")
 
 	return cmd
 }
 
-func handleStream(prov provider.Interface, channel, user, profileName, overrideUsername, iconEmoji string, tee bool, silent bool) error {
+// streamFlushTimeout bounds how long handleStream waits for the final flush
+// after its context has been cancelled.
+const streamFlushTimeout = 10 * time.Second
+
+// handleStream posts lines read from stdin in batches. Each batch is posted
+// with baseOpts, with Text replaced by the buffered lines.
+func handleStream(ctx context.Context, prov provider.Interface, baseOpts provider.PostMessageOptions, profileName string, tee bool, silent bool) error {
 	if !silent {
 		fmt.Fprintf(os.Stderr, "Starting stream to profile '%s'. Press Ctrl+C to exit.\n", profileName)
 	}
@@ -199,46 +296,114 @@ func handleStream(prov provider.Interface, channel, user, profileName, overrideU
 	ticker := CreateTicker(3 * time.Second)
 	defer ticker.Stop()
 
+	post := func(ctx context.Context) error {
+		opts := baseOpts
+		opts.Text = strings.Join(buffer, "\n")
+		_, err := prov.PostMessage(ctx, opts)
+		return err
+	}
+
 	for {
 		select {
 		case line, ok := <-lines:
 			if !ok {
 				if len(buffer) > 0 {
 					fmt.Fprintf(os.Stderr, "Flushing %d remaining lines...\n", len(buffer))
-					opts := provider.PostMessageOptions{
-						TargetChannel:    channel,
-						TargetUserID:     user,
-						Text:             strings.Join(buffer, "\n"),
-						OverrideUsername: overrideUsername,
-						IconEmoji:        iconEmoji,
-					}
-							if err := prov.PostMessage(opts); err != nil {
-								fmt.Fprintf(os.Stderr, "Error flushing remaining lines: %v\n", err)
-							}
-					}
-					if !silent {
-						fmt.Fprintln(os.Stderr, "Stream finished.")
+					if err := post(ctx); err != nil {
+						fmt.Fprintf(os.Stderr, "Error flushing remaining lines: %v\n", err)
 					}
-					return nil
+				}
+				if !silent {
+					fmt.Fprintln(os.Stderr, "Stream finished.")
+				}
+				return nil
 			}
 			buffer = append(buffer, line)
 		case <-ticker.C:
 			if len(buffer) > 0 {
-				opts := provider.PostMessageOptions{
-					TargetChannel:    channel,
-					TargetUserID:     user,
-					Text:             strings.Join(buffer, "\n"),
-					OverrideUsername: overrideUsername,
-					IconEmoji:        iconEmoji,
+				if err := post(ctx); err != nil {
+					fmt.Fprintf(os.Stderr, "Error posting message: %v\n", err)
 				}
-							if err := prov.PostMessage(opts); err != nil {
-								fmt.Fprintf(os.Stderr, "Error posting message: %v\n", err)
-							}
-							if !silent {
-								fmt.Fprintf(os.Stderr, "Posted %d lines to profile '%s'.\n", len(buffer), profileName)
-							}
-					buffer = nil
+				if !silent {
+					fmt.Fprintf(os.Stderr, "Posted %d lines to profile '%s'.\n", len(buffer), profileName)
 				}
+				buffer = nil
+			}
+		case <-ctx.Done():
+			// The stream was interrupted (Ctrl+C) or timed out. Flush what has
+			// been buffered using a detached, short-lived context so the final
+			// lines are not lost along with the cancelled one.
+			if len(buffer) > 0 {
+				fmt.Fprintf(os.Stderr, "Flushing %d remaining lines...\n", len(buffer))
+				flushCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), streamFlushTimeout)
+				err := post(flushCtx)
+				cancel()
+				if err != nil {
+					fmt.Fprintf(os.Stderr, "Error flushing remaining lines: %v\n", err)
+				}
+			}
+			if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+				return fmt.Errorf("stream stopped: %w", ctx.Err())
+			}
+			if !silent {
+				fmt.Fprintln(os.Stderr, "Stream interrupted.")
+			}
+			return nil
 		}
 	}
-}
\ No newline at end of file
+}
+
+// readMessageContent returns the message body from the command-line arguments,
+// the file given by --from-file, or stdin, in that order of precedence.
+// Stdin is limited to stdinLimit bytes if stdinLimit is positive.
+func readMessageContent(args []string, fromFile string, stdinLimit int64) (string, error) {
+	if len(args) > 0 {
+		return strings.Join(args, " "), nil
+	}
+	if fromFile != "" {
+		fileContent, err := os.ReadFile(fromFile)
+		if err != nil {
+			return "", fmt.Errorf("failed to read from file %s: %w", fromFile, err)
+		}
+		return string(fileContent), nil
+	}
+
+	stat, _ := os.Stdin.Stat()
+	if (stat.Mode() & os.ModeCharDevice) != 0 {
+		return "", fmt.Errorf("no message content provided via argument, --from-file, or stdin")
+	}
+	var limitedReader io.Reader = os.Stdin
+	if stdinLimit > 0 {
+		limitedReader = io.LimitReader(os.Stdin, stdinLimit+1)
+	}
+	stdinContent, err := io.ReadAll(limitedReader)
+	if err != nil {
+		return "", fmt.Errorf("failed to read from stdin: %w", err)
+	}
+	if stdinLimit > 0 && int64(len(stdinContent)) > stdinLimit {
+		return "", fmt.Errorf("stdin size exceeds the configured limit (%d bytes)", stdinLimit)
+	}
+	return string(stdinContent), nil
+}
+
+// parseBlocks extracts Block Kit blocks from content, which may be either a
+// JSON object with a "blocks" key or a JSON array of blocks.
+func parseBlocks(content string) (json.RawMessage, error) {
+	// Attempt to unmarshal into a temporary map to check for the "blocks" key
+	var tempMap map[string]json.RawMessage
+	if err := json.Unmarshal([]byte(content), &tempMap); err != nil {
+		// If it's not a map, or unmarshalling fails, try to unmarshal directly as an array
+		var tempArray []interface{}
+		if err := json.Unmarshal([]byte(content), &tempArray); err != nil {
+			return nil, fmt.Errorf("failed to parse block kit JSON: expected a JSON object with a 'blocks' key or a JSON array of blocks: %w", err)
+		}
+		// If it's a direct array, use the content as is
+		return json.RawMessage(content), nil
+	}
+	if rawBlocks, ok := tempMap["blocks"]; ok {
+		// If it's a map with a "blocks" key, extract the value of "blocks"
+		return rawBlocks, nil
+	}
+	// If it's a map but no "blocks" key, it's an invalid format for Block Kit
+	return nil, fmt.Errorf("failed to parse block kit JSON: expected a JSON object with a 'blocks' key or a JSON array of blocks")
+}
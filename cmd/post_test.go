@@ -1,12 +1,17 @@
 package cmd
 
 import (
+	"encoding/json"
 	"fmt"
 	"os"
+	"reflect"
 	"strings"
 	"sync"
 	"testing"
 	"time"
+
+	"github.com/nlink-jp/scat/internal/provider"
+	"github.com/nlink-jp/scat/internal/provider/testprovider"
 )
 
 func TestPost_FromArgument(t *testing.T) {
@@ -367,3 +372,199 @@ func TestPost_InvalidFormatFlag(t *testing.T) {
 		t.Errorf("Expected error message to contain 'invalid value for --format', got: %v", err)
 	}
 }
+
+func TestPost_NegativeTimeout(t *testing.T) {
+	configPath, cleanup := setupTest(t)
+	defer cleanup()
+
+	rootCmd := newRootCmd()
+	rootCmd.AddCommand(newPostCmd())
+
+	_, _, err := testExecuteCommandAndCapture(rootCmd, "--config", configPath, "--timeout", "-1s", "post", "test message")
+	if err == nil {
+		t.Fatal("Expected an error for a negative --timeout, but got nil")
+	}
+
+	if !strings.Contains(err.Error(), "invalid value for --timeout") {
+		t.Errorf("Expected error message to contain 'invalid value for --timeout', got: %v", err)
+	}
+}
+
+func TestPost_WithTimeout(t *testing.T) {
+	configPath, cleanup := setupTest(t)
+	defer cleanup()
+
+	rootCmd := newRootCmd()
+	rootCmd.AddCommand(newPostCmd())
+
+	message := "hello with timeout"
+	_, stderr, err := testExecuteCommandAndCapture(rootCmd, "--config", configPath, "--timeout", "30s", "post", message)
+	if err != nil {
+		t.Fatalf("testExecuteCommandAndCapture returned an error: %v\nStderr: %s", err, stderr)
+	}
+
+	if !strings.Contains(stderr, "Text:"+message) {
+		t.Errorf("Expected stderr to contain the posted message, got: '%s'", stderr)
+	}
+}
+
+func TestPost_JSONOutput(t *testing.T) {
+	configPath, cleanup := setupTest(t)
+	defer cleanup()
+
+	rootCmd := newRootCmd()
+	rootCmd.AddCommand(newPostCmd())
+
+	stdout, stderr, err := testExecuteCommandAndCapture(rootCmd, "--config", configPath, "post", "--json", "hello json")
+	if err != nil {
+		t.Fatalf("testExecuteCommandAndCapture returned an error: %v\nStderr: %s", err, stderr)
+	}
+
+	var result provider.PostResult
+	if err := json.Unmarshal([]byte(stdout), &result); err != nil {
+		t.Fatalf("Failed to parse JSON output: %v\nOutput: %s", err, stdout)
+	}
+	want := provider.PostResult{
+		ChannelID: testprovider.TestChannelID,
+		Timestamp: testprovider.TestMessageTS,
+		Permalink: testprovider.TestPermalink,
+	}
+	if !reflect.DeepEqual(result, want) {
+		t.Errorf("Expected result %+v, got %+v", want, result)
+	}
+}
+
+func TestPost_JSONAndStreamError(t *testing.T) {
+	configPath, cleanup := setupTest(t)
+	defer cleanup()
+
+	rootCmd := newRootCmd()
+	rootCmd.AddCommand(newPostCmd())
+
+	_, _, err := testExecuteCommandAndCapture(rootCmd, "--config", configPath, "post", "--json", "--stream")
+	if err == nil {
+		t.Fatal("Expected an error for --json and --stream, but got nil")
+	}
+
+	if !strings.Contains(err.Error(), "cannot use --json with --stream") {
+		t.Errorf("Expected error message to contain 'cannot use --json with --stream', got: %v", err)
+	}
+}
+
+func TestPost_ThreadReplyFromPermalink(t *testing.T) {
+	configPath, cleanup := setupTest(t)
+	defer cleanup()
+
+	rootCmd := newRootCmd()
+	rootCmd.AddCommand(newPostCmd())
+
+	permalink := "https://example.slack.com/archives/C0123ABCD/p1700000050000200?thread_ts=1700000000.000100&cid=C0123ABCD"
+	_, stderr, err := testExecuteCommandAndCapture(rootCmd, "--config", configPath, "post", "--thread-ts", permalink, "--broadcast", "reply")
+	if err != nil {
+		t.Fatalf("testExecuteCommandAndCapture returned an error: %v\nStderr: %s", err, stderr)
+	}
+
+	// The channel comes from the permalink and the reply goes to the thread parent.
+	expectedLog := "PostMessage called with opts: {TargetChannel:C0123ABCD TargetUserID: Text:reply OverrideUsername: IconEmoji: Blocks:}"
+	if !strings.Contains(stderr, expectedLog) {
+		t.Errorf("Expected stderr to contain '%s', got: '%s'", expectedLog, stderr)
+	}
+	expectedThreadLog := "PostMessage thread: {ThreadTS:1700000000.000100 ReplyBroadcast:true}"
+	if !strings.Contains(stderr, expectedThreadLog) {
+		t.Errorf("Expected stderr to contain '%s', got: '%s'", expectedThreadLog, stderr)
+	}
+}
+
+func TestPost_ThreadReplyWithChannel(t *testing.T) {
+	configPath, cleanup := setupTest(t)
+	defer cleanup()
+
+	rootCmd := newRootCmd()
+	rootCmd.AddCommand(newPostCmd())
+
+	_, stderr, err := testExecuteCommandAndCapture(rootCmd, "--config", configPath, "post", "--channel", "#ops", "--thread-ts", "1700000000.000100", "reply")
+	if err != nil {
+		t.Fatalf("testExecuteCommandAndCapture returned an error: %v\nStderr: %s", err, stderr)
+	}
+
+	if !strings.Contains(stderr, "TargetChannel:#ops") {
+		t.Errorf("Expected the --channel flag to be kept, got: '%s'", stderr)
+	}
+	if !strings.Contains(stderr, "PostMessage thread: {ThreadTS:1700000000.000100 ReplyBroadcast:false}") {
+		t.Errorf("Expected stderr to contain the thread options, got: '%s'", stderr)
+	}
+}
+
+func TestPost_BroadcastWithoutThreadError(t *testing.T) {
+	configPath, cleanup := setupTest(t)
+	defer cleanup()
+
+	rootCmd := newRootCmd()
+	rootCmd.AddCommand(newPostCmd())
+
+	_, _, err := testExecuteCommandAndCapture(rootCmd, "--config", configPath, "post", "--broadcast", "hello")
+	if err == nil {
+		t.Fatal("Expected an error for --broadcast without --thread-ts, but got nil")
+	}
+
+	if !strings.Contains(err.Error(), "--broadcast requires --thread-ts") {
+		t.Errorf("Expected error message to contain '--broadcast requires --thread-ts', got: %v", err)
+	}
+}
+
+func TestPost_InvalidThreadTS(t *testing.T) {
+	configPath, cleanup := setupTest(t)
+	defer cleanup()
+
+	rootCmd := newRootCmd()
+	rootCmd.AddCommand(newPostCmd())
+
+	_, _, err := testExecuteCommandAndCapture(rootCmd, "--config", configPath, "post", "--thread-ts", "not-a-ts", "hello")
+	if err == nil {
+		t.Fatal("Expected an error for an invalid --thread-ts, but got nil")
+	}
+
+	if !strings.Contains(err.Error(), "invalid value for --thread-ts") {
+		t.Errorf("Expected error message to contain 'invalid value for --thread-ts', got: %v", err)
+	}
+}
+
+func TestPost_Ephemeral(t *testing.T) {
+	configPath, cleanup := setupTest(t)
+	defer cleanup()
+
+	rootCmd := newRootCmd()
+	rootCmd.AddCommand(newPostCmd())
+
+	_, stderr, err := testExecuteCommandAndCapture(rootCmd, "--config", configPath, "post", "--ephemeral-to", "@alice", "--channel", "#dev", "your PR failed lint")
+	if err != nil {
+		t.Fatalf("Execute() returned an unexpected error: %v\nStderr: %s", err, stderr)
+	}
+
+	if !strings.Contains(stderr, "PostMessage called with opts: {TargetChannel:#dev TargetUserID: Text:your PR failed lint") {
+		t.Errorf("Expected stderr to contain the channel and text, got: '%s'", stderr)
+	}
+	if !strings.Contains(stderr, "[TESTPROVIDER] PostMessage ephemeral: {EphemeralUserID:@alice}") {
+		t.Errorf("Expected stderr to contain the ephemeral recipient, got: '%s'", stderr)
+	}
+	if !strings.Contains(stderr, "Ephemeral message for '@alice' posted successfully") {
+		t.Errorf("Expected an ephemeral success message, got: '%s'", stderr)
+	}
+}
+
+func TestPost_EphemeralWithUserError(t *testing.T) {
+	configPath, cleanup := setupTest(t)
+	defer cleanup()
+
+	rootCmd := newRootCmd()
+	rootCmd.AddCommand(newPostCmd())
+
+	_, _, err := testExecuteCommandAndCapture(rootCmd, "--config", configPath, "post", "--ephemeral-to", "@alice", "--user", "@bob", "hello")
+	if err == nil {
+		t.Fatal("Expected an error for --ephemeral-to with --user, but got nil")
+	}
+
+	if !strings.Contains(err.Error(), "cannot use --ephemeral-to with --user") {
+		t.Errorf("Expected error message to contain 'cannot use --ephemeral-to with --user', got: %v", err)
+	}
+}
@@ -36,7 +36,17 @@ func newProfileAddCmd() *cobra.Command {
 			channel, _ := cmd.Flags().GetString("channel")
 			username, _ := cmd.Flags().GetString("username")
 			maxFile, _ := cmd.Flags().GetInt64("limits-max-file-size-bytes")
+			maxTotal, _ := cmd.Flags().GetInt64("limits-max-upload-total-bytes")
 			maxStdin, _ := cmd.Flags().GetInt64("limits-max-stdin-size-bytes")
+			retryAttempts, _ := cmd.Flags().GetInt("retry-max-attempts")
+			retryElapsed, _ := cmd.Flags().GetInt("retry-max-elapsed-seconds")
+
+			if retryAttempts < 1 {
+				return fmt.Errorf("invalid value for --retry-max-attempts: %d. Must be a positive integer", retryAttempts)
+			}
+			if retryElapsed < 0 {
+				return fmt.Errorf("invalid value for --retry-max-elapsed-seconds: %d. Must not be negative", retryElapsed)
+			}
 
 			newProfile := config.Profile{
 				Provider: provider,
@@ -44,8 +54,13 @@ func newProfileAddCmd() *cobra.Command {
 				Username: username,
 				Limits: config.Limits{
 					MaxFileSizeBytes: maxFile,
+					MaxUploadTotalBytes: maxTotal,
 					MaxStdinSizeBytes: maxStdin,
 				},
+				Retry: config.Retry{
+					MaxAttempts:       retryAttempts,
+					MaxElapsedSeconds: retryElapsed,
+				},
 			}
 
 			// Prompt for token securely using the new utility function
@@ -70,7 +85,10 @@ func newProfileAddCmd() *cobra.Command {
 	cmd.Flags().String("channel", "", "Channel name or ID (for slack provider)")
 	cmd.Flags().String("username", "", "Default username for posts")
 	cmd.Flags().Int64("limits-max-file-size-bytes", 1024*1024*1024, "Max file size for uploads in bytes (1GB)")
+	cmd.Flags().Int64("limits-max-upload-total-bytes", config.NewDefaultLimits().MaxUploadTotalBytes, "Max total size of a multi-file upload in bytes (10GB)")
 	cmd.Flags().Int64("limits-max-stdin-size-bytes", 10*1024*1024, "Max size for stdin in bytes (10MB)")
+	cmd.Flags().Int("retry-max-attempts", config.NewDefaultRetry().MaxAttempts, "Max attempts for calls that fail transiently (1 disables retries)")
+	cmd.Flags().Int("retry-max-elapsed-seconds", config.NewDefaultRetry().MaxElapsedSeconds, "Max seconds spent retrying a single call (0 for no limit)")
 
 	return cmd
 }
@@ -70,14 +70,32 @@ For the 'token' key, run 'scat profile set token' and you will be prompted to en
 					return fmt.Errorf("invalid integer value for %s: %s", key, value)
 				}
 				profile.Limits.MaxFileSizeBytes = size
+			case "limits.max_upload_total_bytes":
+				size, err := strconv.ParseInt(value, 10, 64)
+				if err != nil {
+					return fmt.Errorf("invalid integer value for %s: %s", key, value)
+				}
+				profile.Limits.MaxUploadTotalBytes = size
 			case "limits.max_stdin_size_bytes":
 				size, err := strconv.ParseInt(value, 10, 64)
 				if err != nil {
 					return fmt.Errorf("invalid integer value for %s: %s", key, value)
 				}
 				profile.Limits.MaxStdinSizeBytes = size
+			case "retry.max_attempts":
+				attempts, err := strconv.Atoi(value)
+				if err != nil || attempts < 1 {
+					return fmt.Errorf("invalid value for %s: %s. Must be a positive integer", key, value)
+				}
+				profile.Retry.MaxAttempts = attempts
+			case "retry.max_elapsed_seconds":
+				seconds, err := strconv.Atoi(value)
+				if err != nil || seconds < 0 {
+					return fmt.Errorf("invalid value for %s: %s. Must be a non-negative integer", key, value)
+				}
+				profile.Retry.MaxElapsedSeconds = seconds
 			default:
-				availableKeys := []string{"provider", "channel", "token", "username", "limits.max_file_size_bytes", "limits.max_stdin_size_bytes"}
+				availableKeys := []string{"provider", "channel", "token", "username", "limits.max_file_size_bytes", "limits.max_upload_total_bytes", "limits.max_stdin_size_bytes", "retry.max_attempts", "retry.max_elapsed_seconds"}
 				return fmt.Errorf("unknown configuration key '%s'.\nAvailable keys: %s", key, strings.Join(availableKeys, ", "))
 			}
 
@@ -67,6 +67,13 @@ func TestProfileSet_Success(t *testing.T) {
 				return cfg.Profiles["default"].Limits.MaxFileSizeBytes == 2000000
 			},
 		},
+		{
+			key:   "limits.max_upload_total_bytes",
+			value: "3000000",
+			expected: func(cfg *config.Config) bool {
+				return cfg.Profiles["default"].Limits.MaxUploadTotalBytes == 3000000
+			},
+		},
 		{
 			key:   "limits.max_stdin_size_bytes",
 			value: "200000",
@@ -74,6 +81,20 @@ func TestProfileSet_Success(t *testing.T) {
 				return cfg.Profiles["default"].Limits.MaxStdinSizeBytes == 200000
 			},
 		},
+		{
+			key:   "retry.max_attempts",
+			value: "5",
+			expected: func(cfg *config.Config) bool {
+				return cfg.Profiles["default"].Retry.MaxAttempts == 5
+			},
+		},
+		{
+			key:   "retry.max_elapsed_seconds",
+			value: "120",
+			expected: func(cfg *config.Config) bool {
+				return cfg.Profiles["default"].Retry.MaxElapsedSeconds == 120
+			},
+		},
 	}
 
 	for _, tt := range tests {
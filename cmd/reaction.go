@@ -0,0 +1,41 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// newReactionCmd creates the command for managing emoji reactions.
+func newReactionCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "reaction",
+		Short: "Add, remove, or list emoji reactions on a message",
+		Long:  `The reaction command and its subcommands manage emoji reactions on a posted message. A message is identified by its timestamp (ts) together with --channel, or by its permalink.`,
+		Run: func(cmd *cobra.Command, args []string) {
+			_ = cmd.Help()
+		},
+	}
+
+	// Add subcommands
+	cmd.AddCommand(newReactionAddCmd())    // from reaction_add.go
+	cmd.AddCommand(newReactionRemoveCmd()) // from reaction_add.go
+	cmd.AddCommand(newReactionListCmd())   // from reaction_list.go
+
+	return cmd
+}
+
+// parseEmojiNames validates emoji names given as arguments, accepting both
+// "white_check_mark" and ":white_check_mark:".
+func parseEmojiNames(args []string) ([]string, error) {
+	names := make([]string, 0, len(args))
+	for _, arg := range args {
+		name := strings.TrimSuffix(strings.TrimPrefix(arg, ":"), ":")
+		if name == "" || strings.ContainsAny(name, ": \t\n") {
+			return nil, fmt.Errorf("invalid emoji name: '%s'", arg)
+		}
+		names = append(names, name)
+	}
+	return names, nil
+}
@@ -0,0 +1,92 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/nlink-jp/scat/internal/appcontext"
+	"github.com/nlink-jp/scat/internal/provider"
+	"github.com/spf13/cobra"
+)
+
+func newReactionAddCmd() *cobra.Command {
+	return newReactionChangeCmd("add", "Add emoji reactions to a message", "added",
+		func(ctx context.Context, prov provider.Interface, opts provider.ReactionOptions) error {
+			return prov.AddReaction(ctx, opts)
+		})
+}
+
+func newReactionRemoveCmd() *cobra.Command {
+	return newReactionChangeCmd("remove", "Remove emoji reactions from a message", "removed",
+		func(ctx context.Context, prov provider.Interface, opts provider.ReactionOptions) error {
+			return prov.RemoveReaction(ctx, opts)
+		})
+}
+
+// newReactionChangeCmd builds the add and remove subcommands, which differ
+// only in the provider method they call.
+func newReactionChangeCmd(use, short, verb string, apply func(context.Context, provider.Interface, provider.ReactionOptions) error) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   use + " <ts|permalink> <emoji>...",
+		Short: short,
+		Long:  short + `. Emoji names may be given with or without surrounding colons (e.g. white_check_mark or :white_check_mark:).`,
+		Args:  cobra.MinimumNArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			appCtx := cmd.Context().Value(appcontext.CtxKey).(appcontext.Context)
+
+			cfg := appCtx.Config
+			if cfg == nil {
+				return fmt.Errorf("configuration file not found. Please run 'scat config init' to create a default configuration")
+			}
+
+			profileName, _ := cmd.Flags().GetString("profile")
+			if profileName == "" {
+				profileName = cfg.CurrentProfile
+			}
+			profile, ok := cfg.Profiles[profileName]
+			if !ok {
+				return fmt.Errorf("profile '%s' not found", profileName)
+			}
+
+			channel, _ := cmd.Flags().GetString("channel")
+			channel, ts, err := resolveMessageRef(args[0], channel)
+			if err != nil {
+				return err
+			}
+			names, err := parseEmojiNames(args[1:])
+			if err != nil {
+				return err
+			}
+
+			prov, err := GetProvider(appCtx, profile)
+			if err != nil {
+				return err
+			}
+			if !prov.Capabilities().CanReact {
+				return fmt.Errorf("the provider for profile '%s' does not support reactions", profileName)
+			}
+
+			for _, name := range names {
+				opts := provider.ReactionOptions{
+					Channel:   channel,
+					Timestamp: ts,
+					Name:      name,
+				}
+				if err := apply(cmd.Context(), prov, opts); err != nil {
+					return fmt.Errorf("failed to %s reaction :%s:: %w", use, name, err)
+				}
+				if !appCtx.Silent {
+					fmt.Fprintf(os.Stderr, "Reaction :%s: %s.\n", name, verb)
+				}
+			}
+
+			return nil
+		},
+	}
+
+	cmd.Flags().StringP("profile", "p", "", "Profile to use for this command")
+	cmd.Flags().StringP("channel", "c", "", "Channel containing the message (not needed when a permalink is given)")
+
+	return cmd
+}
@@ -0,0 +1,83 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/nlink-jp/scat/internal/appcontext"
+	"github.com/nlink-jp/scat/internal/provider"
+	"github.com/spf13/cobra"
+)
+
+func newReactionListCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "list <ts|permalink>",
+		Short: "List emoji reactions on a message",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			appCtx := cmd.Context().Value(appcontext.CtxKey).(appcontext.Context)
+
+			cfg := appCtx.Config
+			if cfg == nil {
+				return fmt.Errorf("configuration file not found. Please run 'scat config init' to create a default configuration")
+			}
+
+			profileName, _ := cmd.Flags().GetString("profile")
+			if profileName == "" {
+				profileName = cfg.CurrentProfile
+			}
+			profile, ok := cfg.Profiles[profileName]
+			if !ok {
+				return fmt.Errorf("profile '%s' not found", profileName)
+			}
+
+			channel, _ := cmd.Flags().GetString("channel")
+			jsonOutput, _ := cmd.Flags().GetBool("json")
+			channel, ts, err := resolveMessageRef(args[0], channel)
+			if err != nil {
+				return err
+			}
+
+			prov, err := GetProvider(appCtx, profile)
+			if err != nil {
+				return err
+			}
+			if !prov.Capabilities().CanReact {
+				return fmt.Errorf("the provider for profile '%s' does not support reactions", profileName)
+			}
+
+			reactions, err := prov.ListReactions(cmd.Context(), provider.ListReactionsOptions{Channel: channel, Timestamp: ts})
+			if err != nil {
+				return fmt.Errorf("failed to list reactions: %w", err)
+			}
+
+			if jsonOutput {
+				if reactions == nil {
+					reactions = []provider.Reaction{}
+				}
+				return printJSON(reactions)
+			}
+
+			if len(reactions) == 0 {
+				if !appCtx.Silent {
+					fmt.Fprintln(os.Stderr, "No reactions.")
+				}
+				return nil
+			}
+			w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+			fmt.Fprintln(w, "NAME\tCOUNT\tUSERS")
+			for _, r := range reactions {
+				fmt.Fprintf(w, ":%s:\t%d\t%s\n", r.Name, r.Count, strings.Join(r.Users, ","))
+			}
+			return w.Flush()
+		},
+	}
+
+	cmd.Flags().StringP("profile", "p", "", "Profile to use for this command")
+	cmd.Flags().StringP("channel", "c", "", "Channel containing the message (not needed when a permalink is given)")
+	cmd.Flags().Bool("json", false, "Output in JSON format")
+
+	return cmd
+}
@@ -0,0 +1,87 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestReactionCmd(t *testing.T) {
+	configPath, cleanup := setupTest(t)
+	defer cleanup()
+
+	tests := []struct {
+		name       string
+		args       []string
+		wantErr    bool
+		wantStdout string
+		wantStderr []string
+	}{
+		{
+			name:       "add by channel and ts",
+			args:       []string{"reaction", "add", "1700000000.000100", "--channel", "#builds", ":white_check_mark:"},
+			wantStderr: []string{"AddReaction called with opts: {Channel:#builds Timestamp:1700000000.000100 Name:white_check_mark}"},
+		},
+		{
+			name: "add several by permalink",
+			args: []string{"reaction", "add", "https://example.slack.com/archives/C0123ABCD/p1700000000000100", "eyes", "rocket"},
+			wantStderr: []string{
+				"AddReaction called with opts: {Channel:C0123ABCD Timestamp:1700000000.000100 Name:eyes}",
+				"AddReaction called with opts: {Channel:C0123ABCD Timestamp:1700000000.000100 Name:rocket}",
+			},
+		},
+		{
+			name:       "remove",
+			args:       []string{"reaction", "remove", "1700000000.000100", "eyes"},
+			wantStderr: []string{"RemoveReaction called with opts: {Channel: Timestamp:1700000000.000100 Name:eyes}"},
+		},
+		{
+			name:       "list",
+			args:       []string{"reaction", "list", "1700000000.000100", "--channel", "#builds"},
+			wantStdout: ":white_check_mark:  2      U0000000001,U0000000002",
+			wantStderr: []string{"ListReactions called with opts: {Channel:#builds Timestamp:1700000000.000100}"},
+		},
+		{
+			name:       "list as json",
+			args:       []string{"reaction", "list", "1700000000.000100", "--json"},
+			wantStdout: `"name": "white_check_mark"`,
+		},
+		{
+			name:       "invalid emoji name",
+			args:       []string{"reaction", "add", "1700000000.000100", "::"},
+			wantErr:    true,
+			wantStderr: []string{"invalid emoji name"},
+		},
+		{
+			name:       "missing emoji",
+			args:       []string{"reaction", "add", "1700000000.000100"},
+			wantErr:    true,
+			wantStderr: []string{"requires at least 2 arg(s)"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rootCmd := newRootCmd()
+			rootCmd.AddCommand(newReactionCmd())
+
+			stdout, stderr, err := testExecuteCommandAndCapture(rootCmd, append([]string{"--config", configPath}, tt.args...)...)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Execute() error = %v, wantErr %v\nStderr: %s", err, tt.wantErr, stderr)
+			}
+			if tt.wantErr {
+				if !strings.Contains(err.Error(), tt.wantStderr[0]) {
+					t.Errorf("Expected error to contain '%s', got: %v", tt.wantStderr[0], err)
+				}
+				return
+			}
+			if !strings.Contains(stdout, tt.wantStdout) {
+				t.Errorf("Expected stdout to contain '%s', got: %s", tt.wantStdout, stdout)
+			}
+			for _, want := range tt.wantStderr {
+				if !strings.Contains(stderr, want) {
+					t.Errorf("Expected stderr to contain '%s', got: %s", want, stderr)
+				}
+			}
+		})
+	}
+}
@@ -5,6 +5,8 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"os/signal"
+	"syscall"
 
 	"github.com/nlink-jp/scat/internal/appcontext"
 	"github.com/nlink-jp/scat/internal/config"
@@ -15,6 +17,12 @@ var version = "dev"
 
 // newRootCmd creates the root command for scat.
 func newRootCmd() *cobra.Command {
+	// cancelTimeout releases the context created for --timeout.
+	// It is replaced in PersistentPreRunE when a timeout is set. Cobra skips
+	// PersistentPostRun when a command fails, so Execute also releases the
+	// context by cancelling its parent.
+	cancelTimeout := context.CancelFunc(func() {})
+
 	cmd := &cobra.Command{
 		Use:     "scat",
 		Version: version,
@@ -34,6 +42,7 @@ Features:
 			noOp, _ := cmd.Flags().GetBool("noop")
 			silent, _ := cmd.Flags().GetBool("silent")
 			configPath, _ := cmd.Flags().GetString("config")
+			timeout, _ := cmd.Flags().GetDuration("timeout")
 
 			serverMode, err := config.DetectServerMode()
 			if err != nil {
@@ -65,10 +74,22 @@ Features:
 				// cfg remains nil if the file does not exist
 			}
 
+			if timeout < 0 {
+				return fmt.Errorf("invalid value for --timeout: %s. Must not be negative", timeout)
+			}
+
+			ctx := cmd.Context()
+			if timeout > 0 {
+				ctx, cancelTimeout = context.WithTimeout(ctx, timeout)
+			}
+
 			appCtx := appcontext.NewContext(debug, noOp, silent, configPath, serverMode, cfg)
-			cmd.SetContext(context.WithValue(cmd.Context(), appcontext.CtxKey, appCtx))
+			cmd.SetContext(context.WithValue(ctx, appcontext.CtxKey, appCtx))
 			return nil
 		},
+		PersistentPostRun: func(cmd *cobra.Command, args []string) {
+			cancelTimeout()
+		},
 	}
 
 	// Persistent flags
@@ -76,13 +97,24 @@ Features:
 	cmd.PersistentFlags().Bool("noop", false, "Dry run, do not actually post or upload")
 	cmd.PersistentFlags().Bool("silent", false, "Suppress informational messages")
 	cmd.PersistentFlags().String("config", "", "Path to an alternative config file")
+	cmd.PersistentFlags().Duration("timeout", 0, "Abort the command if it has not finished within this duration (e.g. 30s, 5m). 0 means no timeout")
 
 	return cmd
 }
 
 // Execute adds all child commands to the root command and sets flags appropriately.
 // This is called by main.main(). It only needs to happen once to the rootCmd.
+// An interrupt (Ctrl+C) or SIGTERM cancels the context passed to the commands,
+// letting in-flight provider calls abort and partial work be flushed.
 func Execute() error {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	// The --timeout context is derived from ctx, so cancelling ctx when the
+	// command returns releases its timer even if the command failed.
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
 	rootCmd := newRootCmd()
 
 	// Add child commands
@@ -93,6 +125,11 @@ func Execute() error {
 	rootCmd.AddCommand(newExportCmd())
 	rootCmd.AddCommand(newChannelCmd())
 	rootCmd.AddCommand(newUserCmd())
+	rootCmd.AddCommand(newMessageCmd())
+	rootCmd.AddCommand(newThreadKeyCmd())
+	rootCmd.AddCommand(newScheduleCmd())
+	rootCmd.AddCommand(newReactionCmd())
+	rootCmd.AddCommand(newPinCmd())
 
-	return rootCmd.Execute()
+	return rootCmd.ExecuteContext(ctx)
 }
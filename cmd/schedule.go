@@ -0,0 +1,51 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// newScheduleCmd creates the command for managing scheduled messages.
+func newScheduleCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "schedule",
+		Short: "List or cancel messages scheduled with 'post --at' or 'post --in'",
+		Long:  `The schedule command and its subcommands operate on messages that have been scheduled with 'scat post --at' or 'scat post --in' but not posted yet.`,
+		Run: func(cmd *cobra.Command, args []string) {
+			_ = cmd.Help()
+		},
+	}
+
+	// Add subcommands
+	cmd.AddCommand(newScheduleListCmd())   // from schedule_list.go
+	cmd.AddCommand(newScheduleDeleteCmd()) // from schedule_delete.go
+
+	return cmd
+}
+
+// resolvePostAt returns the time a message should be posted at, given the
+// values of --at and --in. It returns the zero time if neither is set.
+func resolvePostAt(at string, in time.Duration, now time.Time) (time.Time, error) {
+	if at != "" && in != 0 {
+		return time.Time{}, fmt.Errorf("cannot use --at and --in flags simultaneously")
+	}
+	if in < 0 {
+		return time.Time{}, fmt.Errorf("invalid value for --in: must be positive")
+	}
+	if in > 0 {
+		return now.Add(in), nil
+	}
+	if at == "" {
+		return time.Time{}, nil
+	}
+	postAt, err := parseTime(at)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid value for --at: %w", err)
+	}
+	if !postAt.After(now) {
+		return time.Time{}, fmt.Errorf("invalid value for --at: %s is not in the future", postAt.Format(time.RFC3339))
+	}
+	return postAt, nil
+}
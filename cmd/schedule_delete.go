@@ -0,0 +1,64 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/nlink-jp/scat/internal/appcontext"
+	"github.com/nlink-jp/scat/internal/provider"
+	"github.com/spf13/cobra"
+)
+
+func newScheduleDeleteCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "delete <id>",
+		Short: "Cancel a scheduled message",
+		Long:  `Cancels a scheduled message before it is posted. The ID is shown by 'scat schedule list' and by 'scat post --at ... --json'. Slack requires the channel the message is scheduled for; without --channel, the profile's default channel is used.`,
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			appCtx := cmd.Context().Value(appcontext.CtxKey).(appcontext.Context)
+
+			cfg := appCtx.Config
+			if cfg == nil {
+				return fmt.Errorf("configuration file not found. Please run 'scat config init' to create a default configuration")
+			}
+
+			profileName, _ := cmd.Flags().GetString("profile")
+			if profileName == "" {
+				profileName = cfg.CurrentProfile
+			}
+			profile, ok := cfg.Profiles[profileName]
+			if !ok {
+				return fmt.Errorf("profile '%s' not found", profileName)
+			}
+
+			channel, _ := cmd.Flags().GetString("channel")
+
+			prov, err := GetProvider(appCtx, profile)
+			if err != nil {
+				return err
+			}
+			if !prov.Capabilities().CanScheduleMessages {
+				return fmt.Errorf("the provider for profile '%s' does not support scheduling messages", profileName)
+			}
+
+			opts := provider.DeleteScheduledMessageOptions{
+				Channel: channel,
+				ID:      args[0],
+			}
+			if err := prov.DeleteScheduledMessage(cmd.Context(), opts); err != nil {
+				return fmt.Errorf("failed to delete scheduled message: %w", err)
+			}
+			if !appCtx.Silent {
+				fmt.Fprintf(os.Stderr, "Scheduled message %s deleted successfully.\n", args[0])
+			}
+
+			return nil
+		},
+	}
+
+	cmd.Flags().StringP("profile", "p", "", "Profile to use for this command")
+	cmd.Flags().StringP("channel", "c", "", "Channel the message is scheduled for")
+
+	return cmd
+}
@@ -0,0 +1,100 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/nlink-jp/scat/internal/appcontext"
+	"github.com/nlink-jp/scat/internal/provider"
+	"github.com/spf13/cobra"
+)
+
+// scheduleListTextWidth is the number of characters of message text shown in the table.
+const scheduleListTextWidth = 50
+
+func newScheduleListCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List scheduled messages",
+		Long:  `Lists messages that are scheduled but not posted yet, for all channels or for the channel given with --channel.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			appCtx := cmd.Context().Value(appcontext.CtxKey).(appcontext.Context)
+
+			cfg := appCtx.Config
+			if cfg == nil {
+				return fmt.Errorf("configuration file not found. Please run 'scat config init' to create a default configuration")
+			}
+
+			profileName, _ := cmd.Flags().GetString("profile")
+			if profileName == "" {
+				profileName = cfg.CurrentProfile
+			}
+			profile, ok := cfg.Profiles[profileName]
+			if !ok {
+				return fmt.Errorf("profile '%s' not found", profileName)
+			}
+
+			channel, _ := cmd.Flags().GetString("channel")
+			jsonOutput, _ := cmd.Flags().GetBool("json")
+
+			prov, err := GetProvider(appCtx, profile)
+			if err != nil {
+				return err
+			}
+			if !prov.Capabilities().CanScheduleMessages {
+				return fmt.Errorf("the provider for profile '%s' does not support scheduling messages", profileName)
+			}
+
+			messages, err := prov.ListScheduledMessages(cmd.Context(), provider.ListScheduledMessagesOptions{Channel: channel})
+			if err != nil {
+				return fmt.Errorf("failed to list scheduled messages: %w", err)
+			}
+			sort.SliceStable(messages, func(i, j int) bool {
+				return messages[i].PostAt.Before(messages[j].PostAt)
+			})
+
+			if jsonOutput {
+				if messages == nil {
+					messages = []provider.ScheduledMessage{}
+				}
+				return printJSON(messages)
+			}
+
+			if len(messages) == 0 {
+				if !appCtx.Silent {
+					fmt.Fprintln(os.Stderr, "No scheduled messages.")
+				}
+				return nil
+			}
+			w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+			fmt.Fprintln(w, "ID\tCHANNEL\tPOST AT\tTEXT")
+			for _, m := range messages {
+				fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", m.ID, m.ChannelID, m.PostAt.Local().Format(time.RFC3339), summarizeText(m.Text, scheduleListTextWidth))
+			}
+			return w.Flush()
+		},
+	}
+
+	cmd.Flags().StringP("profile", "p", "", "Profile to use for this command")
+	cmd.Flags().StringP("channel", "c", "", "Only list messages scheduled for this channel")
+	cmd.Flags().Bool("json", false, "Output in JSON format")
+
+	return cmd
+}
+
+// summarizeText returns the first line of text, shortened to at most width runes.
+func summarizeText(text string, width int) string {
+	line, _, multiline := strings.Cut(text, "\n")
+	runes := []rune(line)
+	if len(runes) > width {
+		return string(runes[:width-1]) + "…"
+	}
+	if multiline {
+		return line + " …"
+	}
+	return line
+}
@@ -0,0 +1,167 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nlink-jp/scat/internal/provider/testprovider"
+)
+
+func TestResolvePostAt(t *testing.T) {
+	now := time.Date(2026, 10, 1, 9, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		at      string
+		in      time.Duration
+		want    time.Time
+		wantErr string
+	}{
+		{name: "neither flag"},
+		{name: "in", in: 2 * time.Hour, want: now.Add(2 * time.Hour)},
+		{name: "at with offset", at: "2026-11-01T09:00:00+09:00", want: time.Date(2026, 11, 1, 0, 0, 0, 0, time.UTC)},
+		{name: "at and in", at: "2026-11-01T09:00:00Z", in: time.Hour, wantErr: "cannot use --at and --in flags simultaneously"},
+		{name: "negative in", in: -time.Hour, wantErr: "invalid value for --in"},
+		{name: "at in the past", at: "2026-09-30T09:00:00Z", wantErr: "is not in the future"},
+		{name: "malformed at", at: "tomorrow", wantErr: "invalid value for --at"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := resolvePostAt(tt.at, tt.in, now)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("Expected error containing '%s', got: %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("resolvePostAt() returned an unexpected error: %v", err)
+			}
+			if !got.Equal(tt.want) {
+				t.Errorf("resolvePostAt() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPost_Scheduled(t *testing.T) {
+	configPath, cleanup := setupTest(t)
+	defer cleanup()
+
+	postAt := time.Now().Add(24 * time.Hour).Truncate(time.Second)
+
+	rootCmd := newRootCmd()
+	rootCmd.AddCommand(newPostCmd())
+	stdout, stderr, err := testExecuteCommandAndCapture(rootCmd, "--config", configPath, "post", "--at", postAt.Format(time.RFC3339), "--json", "see you tomorrow")
+	if err != nil {
+		t.Fatalf("Execute() returned an unexpected error: %v\nStderr: %s", err, stderr)
+	}
+
+	expected := fmt.Sprintf("[TESTPROVIDER] PostMessage schedule: {PostAt:%d}", postAt.Unix())
+	if !strings.Contains(stderr, expected) {
+		t.Errorf("Expected stderr to contain '%s', got: %s", expected, stderr)
+	}
+	if !strings.Contains(stderr, "Message scheduled for") {
+		t.Errorf("Expected a scheduling notice, got: %s", stderr)
+	}
+
+	var result struct {
+		ChannelID          string    `json:"channel_id"`
+		ScheduledMessageID string    `json:"scheduled_message_id"`
+		PostAt             time.Time `json:"post_at"`
+	}
+	if err := json.Unmarshal([]byte(stdout), &result); err != nil {
+		t.Fatalf("Failed to parse JSON output: %v\nOutput: %s", err, stdout)
+	}
+	if result.ScheduledMessageID != testprovider.TestScheduledMessageID || !result.PostAt.Equal(postAt) {
+		t.Errorf("Unexpected JSON output: %s", stdout)
+	}
+}
+
+func TestPost_ScheduledInvalidFlags(t *testing.T) {
+	configPath, cleanup := setupTest(t)
+	defer cleanup()
+
+	tests := []struct {
+		name    string
+		args    []string
+		wantErr string
+	}{
+		{
+			name:    "with stream",
+			args:    []string{"post", "--in", "1h", "--stream"},
+			wantErr: "cannot use --at or --in with --stream",
+		},
+		{
+			name:    "with thread-key",
+			args:    []string{"post", "--in", "1h", "--thread-key", "k", "hi"},
+			wantErr: "cannot use --thread-key with --at or --in",
+		},
+		{
+			name:    "at in the past",
+			args:    []string{"post", "--at", "2020-01-01T00:00:00Z", "hi"},
+			wantErr: "is not in the future",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rootCmd := newRootCmd()
+			rootCmd.AddCommand(newPostCmd())
+			_, _, err := testExecuteCommandAndCapture(rootCmd, append([]string{"--config", configPath}, tt.args...)...)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Expected error containing '%s', got: %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestScheduleCmd(t *testing.T) {
+	configPath, cleanup := setupTest(t)
+	defer cleanup()
+
+	tests := []struct {
+		name       string
+		args       []string
+		wantStdout string
+		wantStderr string
+	}{
+		{
+			name:       "list",
+			args:       []string{"schedule", "list", "--channel", "#general"},
+			wantStdout: testprovider.TestScheduledMessageID,
+			wantStderr: "ListScheduledMessages called with opts: {Channel:#general}",
+		},
+		{
+			name:       "list as json",
+			args:       []string{"schedule", "list", "--json"},
+			wantStdout: `"id": "` + testprovider.TestScheduledMessageID + `"`,
+		},
+		{
+			name:       "delete",
+			args:       []string{"schedule", "delete", "Q1234ABCD", "--channel", "#general"},
+			wantStderr: "DeleteScheduledMessage called with opts: {Channel:#general ID:Q1234ABCD}",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rootCmd := newRootCmd()
+			rootCmd.AddCommand(newScheduleCmd())
+			stdout, stderr, err := testExecuteCommandAndCapture(rootCmd, append([]string{"--config", configPath}, tt.args...)...)
+			if err != nil {
+				t.Fatalf("Execute() returned an unexpected error: %v\nStderr: %s", err, stderr)
+			}
+			if !strings.Contains(stdout, tt.wantStdout) {
+				t.Errorf("Expected stdout to contain '%s', got: %s", tt.wantStdout, stdout)
+			}
+			if !strings.Contains(stderr, tt.wantStderr) {
+				t.Errorf("Expected stderr to contain '%s', got: %s", tt.wantStderr, stderr)
+			}
+		})
+	}
+}
@@ -13,7 +13,7 @@ func setServerModeEnv(t *testing.T, extra map[string]string) {
 	t.Setenv("SCAT_PROVIDER", "test")
 	t.Setenv("SCAT_TOKEN", "test-token")
 	// Clear optional vars so previous test runs don't bleed through.
-	for _, k := range []string{"SCAT_CHANNEL", "SCAT_USERNAME", "SCAT_MAX_FILE_SIZE", "SCAT_MAX_STDIN_SIZE"} {
+	for _, k := range []string{"SCAT_CHANNEL", "SCAT_USERNAME", "SCAT_MAX_FILE_SIZE", "SCAT_MAX_UPLOAD_TOTAL_SIZE", "SCAT_MAX_STDIN_SIZE", "SCAT_RETRY_MAX_ATTEMPTS", "SCAT_RETRY_MAX_ELAPSED_SECONDS"} {
 		t.Setenv(k, "")
 	}
 	for k, v := range extra {
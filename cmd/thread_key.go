@@ -0,0 +1,104 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/nlink-jp/scat/internal/appcontext"
+	"github.com/nlink-jp/scat/internal/provider"
+	"github.com/nlink-jp/scat/internal/threadkey"
+	"github.com/spf13/cobra"
+)
+
+// threadKeyFileEnvVar overrides the location of the thread key state file.
+// It is the only way to use thread keys in server mode, where there is no config file.
+const threadKeyFileEnvVar = "SCAT_THREAD_KEY_FILE"
+
+// defaultThreadKeyTTL is how long a thread key stays valid after its last use.
+const defaultThreadKeyTTL = 24 * time.Hour
+
+// newThreadKeyCmd creates the command for managing thread keys.
+func newThreadKeyCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "thread-key",
+		Short: "Manage stable thread keys used by 'post --thread-key'",
+		Long:  `Thread keys map a name of your choice (e.g. build-1234) to the parent message of a thread, so that independent 'scat post --thread-key' invocations reply in the same thread. Keys are stored in ` + threadkey.DefaultFileName + ` next to the configuration file and expire when unused.`,
+		Run: func(cmd *cobra.Command, args []string) {
+			_ = cmd.Help()
+		},
+	}
+
+	// Add subcommands
+	cmd.AddCommand(newThreadKeyListCmd())   // from thread_key_list.go
+	cmd.AddCommand(newThreadKeyForgetCmd()) // from thread_key_forget.go
+
+	return cmd
+}
+
+// threadKeyStore returns the store for thread keys: SCAT_THREAD_KEY_FILE if set,
+// otherwise thread-keys.json in the directory of the config file.
+func threadKeyStore(appCtx appcontext.Context) (*threadkey.Store, error) {
+	if path := os.Getenv(threadKeyFileEnvVar); path != "" {
+		return threadkey.NewStore(path), nil
+	}
+	if appCtx.ServerMode || appCtx.ConfigPath == "" {
+		return nil, fmt.Errorf("thread keys require a state file; set %s in server mode", threadKeyFileEnvVar)
+	}
+	return threadkey.NewStore(filepath.Join(filepath.Dir(appCtx.ConfigPath), threadkey.DefaultFileName)), nil
+}
+
+// postWithThreadKey posts opts into the thread recorded for key. If the key is
+// unknown or expired, the message becomes the thread's parent and is recorded.
+// The state file stays locked while posting, so that parallel invocations with
+// the same key create only one parent message.
+func postWithThreadKey(ctx context.Context, appCtx appcontext.Context, prov provider.Interface, profileName, key string, ttl time.Duration, opts provider.PostMessageOptions) (*provider.PostResult, error) {
+	store, err := threadKeyStore(appCtx)
+	if err != nil {
+		return nil, err
+	}
+
+	var result *provider.PostResult
+	err = store.Update(func(entries map[string]threadkey.Entry) error {
+		now := store.Now()
+		entry, ok := entries[key]
+		if ok {
+			if entry.Profile != profileName {
+				return fmt.Errorf("thread key '%s' belongs to profile '%s', not '%s'", key, entry.Profile, profileName)
+			}
+			opts.TargetChannel = entry.ChannelID
+			opts.TargetUserID = ""
+			opts.ThreadTS = entry.Timestamp
+		}
+
+		var err error
+		result, err = prov.PostMessage(ctx, opts)
+		if err != nil || appCtx.NoOp {
+			return err
+		}
+
+		if !ok {
+			if result.ChannelID == "" || result.Timestamp == "" {
+				if !appCtx.Silent {
+					fmt.Fprintf(os.Stderr, "Warning: the provider did not report the message timestamp; thread key '%s' was not recorded.\n", key)
+				}
+				return nil
+			}
+			entry = threadkey.Entry{
+				Profile:   profileName,
+				ChannelID: result.ChannelID,
+				Timestamp: result.Timestamp,
+				CreatedAt: now,
+			}
+		}
+		entry.ExpiresAt = now.Add(ttl)
+		entries[key] = entry
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
@@ -0,0 +1,62 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/nlink-jp/scat/internal/appcontext"
+	"github.com/nlink-jp/scat/internal/threadkey"
+	"github.com/spf13/cobra"
+)
+
+func newThreadKeyForgetCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "forget [key...]",
+		Short: "Forget thread keys so the next post starts a new thread",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			appCtx := cmd.Context().Value(appcontext.CtxKey).(appcontext.Context)
+			all, _ := cmd.Flags().GetBool("all")
+
+			if all && len(args) > 0 {
+				return fmt.Errorf("cannot use --all together with key arguments")
+			}
+			if !all && len(args) == 0 {
+				return fmt.Errorf("specify one or more keys to forget, or use --all")
+			}
+
+			store, err := threadKeyStore(appCtx)
+			if err != nil {
+				return err
+			}
+
+			var forgotten int
+			err = store.Update(func(entries map[string]threadkey.Entry) error {
+				if all {
+					forgotten = len(entries)
+					clear(entries)
+					return nil
+				}
+				for _, key := range args {
+					if _, ok := entries[key]; !ok {
+						return fmt.Errorf("thread key '%s' not found", key)
+					}
+					delete(entries, key)
+					forgotten++
+				}
+				return nil
+			})
+			if err != nil {
+				return err
+			}
+
+			if !appCtx.Silent {
+				fmt.Fprintf(os.Stderr, "Forgot %d thread key(s).\n", forgotten)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().Bool("all", false, "Forget all thread keys")
+
+	return cmd
+}
@@ -0,0 +1,76 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"text/tabwriter"
+	"time"
+
+	"github.com/nlink-jp/scat/internal/appcontext"
+	"github.com/spf13/cobra"
+)
+
+// threadKeyListEntry is the JSON representation of a thread key.
+type threadKeyListEntry struct {
+	Key       string    `json:"key"`
+	Profile   string    `json:"profile"`
+	ChannelID string    `json:"channel_id"`
+	Timestamp string    `json:"ts"`
+	CreatedAt time.Time `json:"created_at"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+func newThreadKeyListCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List active thread keys",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			appCtx := cmd.Context().Value(appcontext.CtxKey).(appcontext.Context)
+			jsonOutput, _ := cmd.Flags().GetBool("json")
+
+			store, err := threadKeyStore(appCtx)
+			if err != nil {
+				return err
+			}
+			entries, err := store.Entries()
+			if err != nil {
+				return err
+			}
+
+			list := make([]threadKeyListEntry, 0, len(entries))
+			for key, e := range entries {
+				list = append(list, threadKeyListEntry{
+					Key:       key,
+					Profile:   e.Profile,
+					ChannelID: e.ChannelID,
+					Timestamp: e.Timestamp,
+					CreatedAt: e.CreatedAt,
+					ExpiresAt: e.ExpiresAt,
+				})
+			}
+			sort.Slice(list, func(i, j int) bool { return list[i].Key < list[j].Key })
+
+			if jsonOutput {
+				return printJSON(list)
+			}
+
+			if len(list) == 0 {
+				if !appCtx.Silent {
+					fmt.Fprintln(os.Stderr, "No active thread keys.")
+				}
+				return nil
+			}
+			w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+			fmt.Fprintln(w, "KEY\tPROFILE\tCHANNEL\tTS\tEXPIRES")
+			for _, e := range list {
+				fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", e.Key, e.Profile, e.ChannelID, e.Timestamp, e.ExpiresAt.Local().Format(time.RFC3339))
+			}
+			return w.Flush()
+		},
+	}
+
+	cmd.Flags().Bool("json", false, "Output in JSON format")
+
+	return cmd
+}
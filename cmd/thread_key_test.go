@@ -0,0 +1,120 @@
+package cmd
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/nlink-jp/scat/internal/provider/testprovider"
+)
+
+func TestPostWithThreadKey(t *testing.T) {
+	configPath, cleanup := setupTest(t)
+	defer cleanup()
+	t.Setenv(threadKeyFileEnvVar, "")
+
+	post := func(text string) string {
+		t.Helper()
+		rootCmd := newRootCmd()
+		rootCmd.AddCommand(newPostCmd())
+		_, stderr, err := testExecuteCommandAndCapture(rootCmd, "--config", configPath, "post", "--thread-key", "build-42", text)
+		if err != nil {
+			t.Fatalf("post returned an unexpected error: %v\nStderr: %s", err, stderr)
+		}
+		return stderr
+	}
+
+	// The first post starts the thread.
+	stderr := post("build started")
+	if strings.Contains(stderr, "PostMessage thread:") {
+		t.Errorf("First post should not be a thread reply, got: %s", stderr)
+	}
+
+	// Later posts reply to the recorded parent in its channel.
+	stderr = post("build finished")
+	expected := "[TESTPROVIDER] PostMessage called with opts: {TargetChannel:" + testprovider.TestChannelID + " TargetUserID: Text:build finished"
+	if !strings.Contains(stderr, expected) {
+		t.Errorf("Expected stderr to contain '%s', got: %s", expected, stderr)
+	}
+	expected = "[TESTPROVIDER] PostMessage thread: {ThreadTS:" + testprovider.TestMessageTS + " ReplyBroadcast:false}"
+	if !strings.Contains(stderr, expected) {
+		t.Errorf("Expected stderr to contain '%s', got: %s", expected, stderr)
+	}
+
+	// The key is listed...
+	rootCmd := newRootCmd()
+	rootCmd.AddCommand(newThreadKeyCmd())
+	stdout, _, err := testExecuteCommandAndCapture(rootCmd, "--config", configPath, "thread-key", "list", "--json")
+	if err != nil {
+		t.Fatalf("thread-key list returned an unexpected error: %v", err)
+	}
+	var list []threadKeyListEntry
+	if err := json.Unmarshal([]byte(stdout), &list); err != nil {
+		t.Fatalf("Failed to parse JSON output: %v\nOutput: %s", err, stdout)
+	}
+	if len(list) != 1 || list[0].Key != "build-42" || list[0].Profile != "test" || list[0].Timestamp != testprovider.TestMessageTS {
+		t.Errorf("Unexpected thread keys: %+v", list)
+	}
+
+	// ...and once forgotten, the next post starts a new thread.
+	rootCmd = newRootCmd()
+	rootCmd.AddCommand(newThreadKeyCmd())
+	if _, stderr, err := testExecuteCommandAndCapture(rootCmd, "--config", configPath, "thread-key", "forget", "build-42"); err != nil {
+		t.Fatalf("thread-key forget returned an unexpected error: %v\nStderr: %s", err, stderr)
+	}
+	stderr = post("build restarted")
+	if strings.Contains(stderr, "PostMessage thread:") {
+		t.Errorf("Post after forget should not be a thread reply, got: %s", stderr)
+	}
+}
+
+func TestPostWithThreadKey_InvalidFlags(t *testing.T) {
+	configPath, cleanup := setupTest(t)
+	defer cleanup()
+
+	tests := []struct {
+		name    string
+		args    []string
+		wantErr string
+	}{
+		{
+			name:    "with thread-ts",
+			args:    []string{"post", "--thread-key", "k", "--thread-ts", "1700000000.000100", "hi"},
+			wantErr: "cannot use --thread-key and --thread-ts flags simultaneously",
+		},
+		{
+			name:    "with stream",
+			args:    []string{"post", "--thread-key", "k", "--stream"},
+			wantErr: "cannot use --thread-key with --stream",
+		},
+		{
+			name:    "non-positive ttl",
+			args:    []string{"post", "--thread-key", "k", "--thread-key-ttl", "0s", "hi"},
+			wantErr: "invalid value for --thread-key-ttl",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rootCmd := newRootCmd()
+			rootCmd.AddCommand(newPostCmd())
+			_, _, err := testExecuteCommandAndCapture(rootCmd, append([]string{"--config", configPath}, tt.args...)...)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Expected error containing '%s', got: %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestThreadKeyForget_UnknownKey(t *testing.T) {
+	configPath, cleanup := setupTest(t)
+	defer cleanup()
+	t.Setenv(threadKeyFileEnvVar, "")
+
+	rootCmd := newRootCmd()
+	rootCmd.AddCommand(newThreadKeyCmd())
+	_, _, err := testExecuteCommandAndCapture(rootCmd, "--config", configPath, "thread-key", "forget", "missing")
+	if err == nil || !strings.Contains(err.Error(), "thread key 'missing' not found") {
+		t.Errorf("Expected a not-found error, got: %v", err)
+	}
+}
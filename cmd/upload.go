@@ -1,12 +1,18 @@
 package cmd
 
 import (
+	"bytes"
 	"fmt"
 	"io"
 	"os"
+	"path/filepath"
+	"strings"
+	"time"
 
 	"github.com/nlink-jp/scat/internal/appcontext"
+	"github.com/nlink-jp/scat/internal/archive"
 	"github.com/nlink-jp/scat/internal/provider"
+	"github.com/nlink-jp/scat/internal/util"
 	"github.com/spf13/cobra"
 )
 
@@ -15,7 +21,13 @@ func newUploadCmd() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "upload",
 		Short: "Upload a file from a path or stdin",
-		Long:  `Uploads a file as a multipart/form-data request. The file content is sourced from the path specified in the --file flag, or from stdin if --file is set to "-".`,
+		Long: `Uploads a file as a multipart/form-data request. The file content is sourced from the path specified in the --file flag, or from stdin if --file is set to "-".
+
+--file can be repeated and accepts glob patterns (e.g. -f 'logs/*.txt') to upload several files together in a single message sharing one --comment. Each file can be given a title with a repeated --title flag, applied in order.
+
+--dir uploads a whole directory as a single zip or tar.gz archive (--archive), optionally filtered with --include and --exclude patterns. --archive can also bundle the files given with --file. The archive is built on the fly without a temporary file and is named after the directory and the current time unless --filename is given.
+
+--snippet uploads text as a syntax-highlighted snippet. The filetype is detected from the file name and content unless --filetype is given. Without --snippet, --filetype has no effect, and the file is uploaded as it is.`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			appCtx := cmd.Context().Value(appcontext.CtxKey).(appcontext.Context)
 
@@ -37,12 +49,49 @@ func newUploadCmd() *cobra.Command {
 			// Get optional flags
 			channel, _ := cmd.Flags().GetString("channel")
 			user, _ := cmd.Flags().GetString("user")
-			filePath, _ := cmd.Flags().GetString("file")
+			filePatterns, _ := cmd.Flags().GetStringArray("file")
+			titles, _ := cmd.Flags().GetStringArray("title")
+			altTexts, _ := cmd.Flags().GetStringArray("alt-text")
+			snippet, _ := cmd.Flags().GetBool("snippet")
+			jsonOutput, _ := cmd.Flags().GetBool("json")
+			threadTSFlag, _ := cmd.Flags().GetString("thread-ts")
+			broadcast, _ := cmd.Flags().GetBool("broadcast")
+			dir, _ := cmd.Flags().GetString("dir")
+			archiveFlag, _ := cmd.Flags().GetString("archive")
+			include, _ := cmd.Flags().GetStringArray("include")
+			exclude, _ := cmd.Flags().GetStringArray("exclude")
 
 			// --- Flag Validation and Exclusive Handling ---
 			if user != "" && channel != "" {
 				return fmt.Errorf("cannot use --user and --channel flags simultaneously")
 			}
+			threadTS, channel, err := resolveThreadTS(threadTSFlag, channel, user)
+			if err != nil {
+				return err
+			}
+			if broadcast {
+				// Unlike chat.postMessage, files.completeUploadExternal has
+				// no reply_broadcast option.
+				return fmt.Errorf("--broadcast is not supported for uploads: Slack cannot also send files shared in a thread to the channel; use 'scat post --thread-ts ... --broadcast' to announce the upload instead")
+			}
+			if dir != "" && len(filePatterns) > 0 {
+				return fmt.Errorf("cannot use --file and --dir flags simultaneously")
+			}
+			if dir == "" && (len(include) > 0 || len(exclude) > 0) {
+				return fmt.Errorf("--include and --exclude can only be used with --dir")
+			}
+			var archiveFormat archive.Format
+			if dir != "" || cmd.Flags().Changed("archive") {
+				if archiveFormat, err = archive.ParseFormat(archiveFlag); err != nil {
+					return err
+				}
+			}
+			var filePaths []string
+			if len(filePatterns) > 0 {
+				if filePaths, err = expandUploadPaths(filePatterns); err != nil {
+					return err
+				}
+			}
 
 			// Get provider instance
 			prov, err := GetProvider(appCtx, profile)
@@ -55,57 +104,115 @@ func newUploadCmd() *cobra.Command {
 			filetype, _ := cmd.Flags().GetString("filetype")
 			comment, _ := cmd.Flags().GetString("comment")
 
-			if filePath == "-" {
-				// Reading from stdin requires creating a temporary file
-				tmpFile, err := os.CreateTemp("", "scat-stdin-")
-				if err != nil {
-					return fmt.Errorf("failed to create temp file for stdin: %w", err)
+			var content io.ReadSeeker
+			var size int64
+			switch {
+			case archiveFormat != "":
+				// Bundle the directory or files into a single archive that is
+				// built on the fly while it is uploaded.
+				if len(filePaths) == 1 && filePaths[0] == "-" {
+					return fmt.Errorf("cannot use --archive with stdin")
 				}
-				defer os.Remove(tmpFile.Name())
-
-				limit := profile.Limits.MaxStdinSizeBytes
-				var limitedReader io.Reader = os.Stdin
-				if limit > 0 {
-					limitedReader = io.LimitReader(os.Stdin, limit+1)
+				if snippet {
+					return fmt.Errorf("cannot upload an archive as a snippet")
+				}
+				if len(titles) > 1 {
+					return fmt.Errorf("got %d --title values for 1 archive", len(titles))
+				}
+				if len(altTexts) > 0 {
+					return fmt.Errorf("cannot use --alt-text with --archive")
 				}
-				written, err := io.Copy(tmpFile, limitedReader)
+				a, name, err := buildUploadArchive(dir, filePaths, include, exclude, archiveFormat, profile.Limits.MaxFileSizeBytes, time.Now())
 				if err != nil {
-					return fmt.Errorf("failed to write stdin to temp file: %w", err)
+					return err
 				}
-				if limit > 0 && written > limit {
-					return fmt.Errorf("stdin size exceeds the configured limit (%d bytes)", limit)
+				defer a.Close()
+				if filename == "" {
+					filename = name
+				}
+				if !appCtx.Silent {
+					fmt.Fprintf(os.Stderr, "Archiving %d files into '%s' (%s).\n", a.Len(), filename, util.FormatBytes(a.Size()))
+				}
+				filePaths = []string{""}
+				content, size = a, a.Size()
+
+			case len(filePaths) > 1 && filename != "":
+				return fmt.Errorf("cannot use --filename when uploading more than one file")
+
+			case len(titles) > len(filePaths):
+				return fmt.Errorf("got %d --title values for %d files", len(titles), len(filePaths))
+
+			case len(altTexts) > len(filePaths):
+				return fmt.Errorf("got %d --alt-text values for %d files", len(altTexts), len(filePaths))
+
+			case filePaths[0] == "-":
+				// Stdin is streamed to the provider; only piped input is
+				// buffered, because the upload needs the length up front.
+				content, size, err = readUploadStdin(os.Stdin, profile.Limits.MaxStdinSizeBytes)
+				if err != nil {
+					return err
 				}
-				filePath = tmpFile.Name()
 				if filename == "" {
 					filename = "stdin-upload"
 				}
-			} else {
-				// Check file size before proceeding
-				fileInfo, err := os.Stat(filePath)
-					if err != nil {
-						return fmt.Errorf("failed to get file info: %w", err)
-					}
-					if profile.Limits.MaxFileSizeBytes > 0 && fileInfo.Size() > profile.Limits.MaxFileSizeBytes {
-						return fmt.Errorf("file size (%d bytes) exceeds the configured limit (%d bytes)", fileInfo.Size(), profile.Limits.MaxFileSizeBytes)
-					}
+
+			default:
+				// Check file sizes before proceeding
+				if err := checkUploadSizes(filePaths, profile.Limits.MaxFileSizeBytes, profile.Limits.MaxUploadTotalBytes); err != nil {
+					return err
+				}
 				if filename == "" {
-					filename = filePath
+					filename = filePaths[0]
 				}
 			}
 
 			opts := provider.PostFileOptions{
 				TargetChannel: channel,
 				TargetUserID:  user,
-				FilePath:      filePath,
+				FilePath:      filePaths[0],
 				Filename:      filename,
 				Filetype:      filetype,
 				Comment:       comment,
+				ThreadTS:      threadTS,
+				Snippet:       snippet,
 			}
-			if err := prov.PostFile(opts); err != nil {
+			if content != nil {
+				opts.FilePath = ""
+				opts.Content = content
+				opts.Size = size
+			}
+			if len(filePaths) > 1 {
+				opts.Files = make([]provider.UploadFile, len(filePaths))
+				for i, path := range filePaths {
+					opts.Files[i] = provider.UploadFile{Path: path, Filename: filepath.Base(path)}
+					if i < len(titles) {
+						opts.Files[i].Title = titles[i]
+					}
+					if i < len(altTexts) {
+						opts.Files[i].AltText = altTexts[i]
+					}
+				}
+			} else {
+				if len(titles) > 0 {
+					opts.Title = titles[0]
+				}
+				if len(altTexts) > 0 {
+					opts.AltText = altTexts[0]
+				}
+			}
+			result, err := prov.PostFile(cmd.Context(), opts)
+			if err != nil {
 				return fmt.Errorf("failed to post file: %w", err)
 			}
 			if !appCtx.Silent {
-				fmt.Fprintf(os.Stderr, "File '%s' uploaded successfully to profile '%s'.\n", filename, profileName)
+				if len(opts.Files) > 0 {
+					fmt.Fprintf(os.Stderr, "%d files uploaded successfully to profile '%s'.\n", len(opts.Files), profileName)
+				} else {
+					fmt.Fprintf(os.Stderr, "File '%s' uploaded successfully to profile '%s'.\n", filename, profileName)
+				}
+			}
+			if jsonOutput {
+				return printJSON(result)
 			}
 
 			return nil
@@ -115,12 +222,164 @@ func newUploadCmd() *cobra.Command {
 	cmd.Flags().StringP("profile", "p", "", "Profile to use for this upload")
 	cmd.Flags().StringP("channel", "c", "", "Override the destination channel for this upload")
 	cmd.Flags().String("user", "", "Send a direct message to a user by ID")
-	cmd.Flags().StringP("file", "f", "", "Path to the file to upload, or \"-\" for stdin")
-	_ = cmd.MarkFlagRequired("file")
+	cmd.Flags().StringArrayP("file", "f", nil, "Path or glob pattern of a file to upload, or \"-\" for stdin (repeatable)")
+	cmd.Flags().String("dir", "", "Upload a directory as a single archive")
+	cmd.Flags().String("archive", string(archive.Zip), "Archive format for --dir, or to bundle the --file files: zip or tar.gz")
+	cmd.Flags().StringArray("include", nil, "Only archive files of --dir matching this pattern (repeatable)")
+	cmd.Flags().StringArray("exclude", nil, "Skip files and directories of --dir matching this pattern (repeatable)")
+	cmd.MarkFlagsOneRequired("file", "dir")
 
 	cmd.Flags().StringP("comment", "m", "", "A comment to post with the file")
-	cmd.Flags().StringP("filename", "n", "", "Filename for the upload")
-	cmd.Flags().String("filetype", "", "Filetype for syntax highlighting")
+	cmd.Flags().StringP("filename", "n", "", "Filename for the upload (single file only)")
+	cmd.Flags().StringArray("title", nil, "Title of an uploaded file (repeatable, applied to the files in order)")
+	cmd.Flags().StringArray("alt-text", nil, "Description of an uploaded image for screen readers (repeatable, applied to the files in order)")
+	cmd.Flags().String("filetype", "", "Filetype for syntax highlighting of a --snippet (e.g. go), instead of the detected one")
+	cmd.Flags().Bool("snippet", false, "Upload text as a syntax-highlighted snippet, detecting the filetype unless --filetype is given")
+	cmd.Flags().String("thread-ts", "", "Share the file in a thread, given the parent message's timestamp or permalink")
+	cmd.Flags().Bool("broadcast", false, "Not supported for uploads: Slack cannot also send files shared in a thread to the channel")
+	cmd.Flags().Bool("json", false, "Print the channel ID, file ID, timestamp, and permalink of the upload as JSON")
 
 	return cmd
 }
+
+// expandUploadPaths expands the --file values into the list of files to upload.
+// Values containing glob meta characters that do not name an existing file are
+// treated as patterns; a pattern matching nothing is an error. Stdin ("-")
+// cannot be combined with other files.
+func expandUploadPaths(patterns []string) ([]string, error) {
+	var paths []string
+	for _, pattern := range patterns {
+		if pattern == "-" {
+			if len(patterns) > 1 {
+				return nil, fmt.Errorf("cannot combine stdin (\"-\") with other files")
+			}
+			return []string{pattern}, nil
+		}
+		if !strings.ContainsAny(pattern, "*?[") {
+			paths = append(paths, pattern)
+			continue
+		}
+		if _, err := os.Stat(pattern); err == nil {
+			paths = append(paths, pattern)
+			continue
+		}
+		matches, err := filepath.Glob(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid file pattern '%s': %w", pattern, err)
+		}
+		if len(matches) == 0 {
+			return nil, fmt.Errorf("no files match '%s'", pattern)
+		}
+		paths = append(paths, matches...)
+	}
+	if len(paths) == 0 {
+		return nil, fmt.Errorf("no file specified")
+	}
+	return paths, nil
+}
+
+// buildUploadArchive prepares an archive of dir (filtered by include and
+// exclude) or of the given files, limited to limit bytes, and returns it
+// together with a default file name such as "artifacts-20260102-150405.zip".
+func buildUploadArchive(dir string, files, include, exclude []string, format archive.Format, limit int64, now time.Time) (*archive.Archive, string, error) {
+	var entries []archive.Entry
+	base := "archive"
+	if dir != "" {
+		fi, err := os.Stat(dir)
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to get directory info: %w", err)
+		}
+		if !fi.IsDir() {
+			return nil, "", fmt.Errorf("'%s' is not a directory", dir)
+		}
+		if entries, err = archive.Collect(dir, include, exclude); err != nil {
+			return nil, "", err
+		}
+		if len(entries) == 0 {
+			return nil, "", fmt.Errorf("no files to archive in '%s'", dir)
+		}
+		if abs, err := filepath.Abs(dir); err == nil && filepath.Base(abs) != string(filepath.Separator) {
+			base = filepath.Base(abs)
+		}
+	} else {
+		seen := make(map[string]string)
+		for _, path := range files {
+			fi, err := os.Stat(path)
+			if err != nil {
+				return nil, "", fmt.Errorf("failed to get file info: %w", err)
+			}
+			if fi.IsDir() {
+				return nil, "", fmt.Errorf("'%s' is a directory; use --dir to archive directories", path)
+			}
+			name := filepath.Base(path)
+			if other, ok := seen[name]; ok {
+				return nil, "", fmt.Errorf("'%s' and '%s' would have the same name in the archive", other, path)
+			}
+			seen[name] = path
+			entries = append(entries, archive.Entry{Path: path, Name: name})
+		}
+	}
+
+	a, err := archive.New(entries, format, limit)
+	if err != nil {
+		return nil, "", err
+	}
+	return a, fmt.Sprintf("%s-%s.%s", base, now.Format("20060102-150405"), format.Ext()), nil
+}
+
+// readUploadStdin prepares stdin for upload without a temporary file. A
+// regular file redirected to stdin is streamed as is; piped input is read into
+// memory (at most limit bytes) because the upload needs its length up front.
+func readUploadStdin(stdin *os.File, limit int64) (io.ReadSeeker, int64, error) {
+	if fi, err := stdin.Stat(); err == nil && fi.Mode().IsRegular() {
+		offset, err := stdin.Seek(0, io.SeekCurrent)
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to read stdin: %w", err)
+		}
+		size := fi.Size() - offset
+		if limit > 0 && size > limit {
+			return nil, 0, fmt.Errorf("stdin size exceeds the configured limit (%d bytes)", limit)
+		}
+		return io.NewSectionReader(stdin, offset, size), size, nil
+	}
+
+	var r io.Reader = stdin
+	if limit > 0 {
+		r = io.LimitReader(stdin, limit+1)
+	}
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to read stdin: %w", err)
+	}
+	if limit > 0 && int64(len(data)) > limit {
+		return nil, 0, fmt.Errorf("stdin size exceeds the configured limit (%d bytes)", limit)
+	}
+	return bytes.NewReader(data), int64(len(data)), nil
+}
+
+// checkUploadSizes enforces the per-file size limit on every file and the
+// total limit on the size of a multi-file upload. A limit of 0 disables its
+// check.
+func checkUploadSizes(paths []string, limit, totalLimit int64) error {
+	var total int64
+	for _, path := range paths {
+		fileInfo, err := os.Stat(path)
+		if err != nil {
+			return fmt.Errorf("failed to get file info: %w", err)
+		}
+		if fileInfo.IsDir() {
+			return fmt.Errorf("'%s' is a directory", path)
+		}
+		if limit > 0 && fileInfo.Size() > limit {
+			if len(paths) > 1 {
+				return fmt.Errorf("size of '%s' (%d bytes) exceeds the configured limit (%d bytes)", path, fileInfo.Size(), limit)
+			}
+			return fmt.Errorf("file size (%d bytes) exceeds the configured limit (%d bytes)", fileInfo.Size(), limit)
+		}
+		total += fileInfo.Size()
+	}
+	if len(paths) > 1 && totalLimit > 0 && total > totalLimit {
+		return fmt.Errorf("total size of %d files (%d bytes) exceeds the configured total limit (%d bytes)", len(paths), total, totalLimit)
+	}
+	return nil
+}
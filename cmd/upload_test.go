@@ -1,11 +1,17 @@
 package cmd
 
 import (
+	"encoding/json"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
+	"regexp"
 	"strings"
 	"testing"
+
+	"github.com/nlink-jp/scat/internal/provider"
+	"github.com/nlink-jp/scat/internal/provider/testprovider"
 )
 
 func TestUpload_FromFile(t *testing.T) {
@@ -161,15 +167,529 @@ func TestUpload_NoFile(t *testing.T) {
 	rootCmd := newRootCmd()
 	rootCmd.AddCommand(newUploadCmd())
 
-	// Execute the command without the required --file flag
+	// Execute the command without --file or --dir
 	_, _, err := testExecuteCommandAndCapture(rootCmd, "--config", configPath, "upload")
 	if err == nil {
 		t.Fatal("Expected an error for missing --file flag, but got nil")
 	}
 
 	// Check the error message
-	expectedError := "required flag(s) \"file\" not set"
+	expectedError := "at least one of the flags in the group [file dir] is required"
 	if !strings.Contains(err.Error(), expectedError) {
 		t.Errorf("Expected error message to contain '%s', got: '%v'", expectedError, err)
 	}
 }
+
+func TestUpload_JSONOutput(t *testing.T) {
+	configPath, cleanup := setupTest(t)
+	defer cleanup()
+
+	filePath := filepath.Join(t.TempDir(), "upload-test.txt")
+	if err := os.WriteFile(filePath, []byte("hello upload"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	rootCmd := newRootCmd()
+	rootCmd.AddCommand(newUploadCmd())
+
+	stdout, stderr, err := testExecuteCommandAndCapture(rootCmd, "--config", configPath, "upload", "--file", filePath, "--json")
+	if err != nil {
+		t.Fatalf("testExecuteCommandAndCapture returned an error: %v\nStderr: %s", err, stderr)
+	}
+
+	var result provider.PostResult
+	if err := json.Unmarshal([]byte(stdout), &result); err != nil {
+		t.Fatalf("Failed to parse JSON output: %v\nOutput: %s", err, stdout)
+	}
+	if result.FileID != testprovider.TestFileID || result.Timestamp != testprovider.TestMessageTS || result.ChannelID != testprovider.TestChannelID {
+		t.Errorf("Unexpected result: %+v", result)
+	}
+}
+
+func TestUpload_ThreadReply(t *testing.T) {
+	configPath, cleanup := setupTest(t)
+	defer cleanup()
+
+	filePath := filepath.Join(t.TempDir(), "upload-test.txt")
+	if err := os.WriteFile(filePath, []byte("hello upload"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	rootCmd := newRootCmd()
+	rootCmd.AddCommand(newUploadCmd())
+
+	permalink := "https://example.slack.com/archives/C0123ABCD/p1700000000000100"
+	_, stderr, err := testExecuteCommandAndCapture(rootCmd, "--config", configPath, "upload", "--file", filePath, "--thread-ts", permalink)
+	if err != nil {
+		t.Fatalf("testExecuteCommandAndCapture returned an error: %v\nStderr: %s", err, stderr)
+	}
+
+	if !strings.Contains(stderr, "PostFile called with opts: {TargetChannel:C0123ABCD ") {
+		t.Errorf("Expected the channel to come from the permalink, got: '%s'", stderr)
+	}
+	if !strings.Contains(stderr, "PostFile thread: {ThreadTS:1700000000.000100}") {
+		t.Errorf("Expected stderr to contain the thread options, got: '%s'", stderr)
+	}
+
+	// Slack cannot broadcast uploads, so --broadcast is rejected before
+	// anything is uploaded.
+	rootCmd = newRootCmd()
+	rootCmd.AddCommand(newUploadCmd())
+	_, stderr, err = testExecuteCommandAndCapture(rootCmd, "--config", configPath, "upload", "--file", filePath, "--thread-ts", permalink, "--broadcast")
+	if err == nil || !strings.Contains(err.Error(), "--broadcast is not supported for uploads") {
+		t.Errorf("Expected --broadcast to be rejected, got: %v", err)
+	}
+	if strings.Contains(stderr, "PostFile called") {
+		t.Errorf("Expected nothing to be uploaded, got: '%s'", stderr)
+	}
+}
+
+func TestUpload_MultipleFiles(t *testing.T) {
+	configPath, cleanup := setupTest(t)
+	defer cleanup()
+
+	tempDir := t.TempDir()
+	for _, name := range []string{"a.png", "b.png", "one.txt", "two.txt"} {
+		if err := os.WriteFile(filepath.Join(tempDir, name), []byte("content"), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	rootCmd := newRootCmd()
+	rootCmd.AddCommand(newUploadCmd())
+
+	_, stderr, err := testExecuteCommandAndCapture(rootCmd, "--config", configPath, "upload",
+		"-f", filepath.Join(tempDir, "a.png"),
+		"-f", filepath.Join(tempDir, "b.png"),
+		"-f", filepath.Join(tempDir, "*.txt"),
+		"--title", "First", "--title", "Second",
+		"-m", "build results")
+	if err != nil {
+		t.Fatalf("testExecuteCommandAndCapture returned an error: %v\nStderr: %s", err, stderr)
+	}
+
+	expected := []string{
+		"Comment:build results",
+		fmt.Sprintf("PostFile file: {Path:%s Filename:a.png Title:First}", filepath.Join(tempDir, "a.png")),
+		fmt.Sprintf("PostFile file: {Path:%s Filename:b.png Title:Second}", filepath.Join(tempDir, "b.png")),
+		fmt.Sprintf("PostFile file: {Path:%s Filename:one.txt Title:}", filepath.Join(tempDir, "one.txt")),
+		fmt.Sprintf("PostFile file: {Path:%s Filename:two.txt Title:}", filepath.Join(tempDir, "two.txt")),
+		"4 files uploaded successfully to profile 'test'.",
+	}
+	for _, want := range expected {
+		if !strings.Contains(stderr, want) {
+			t.Errorf("Expected stderr to contain '%s', got: '%s'", want, stderr)
+		}
+	}
+	if n := strings.Count(stderr, "PostFile called with opts"); n != 1 {
+		t.Errorf("Expected a single PostFile call, got %d", n)
+	}
+}
+
+func TestUpload_SingleFileTitle(t *testing.T) {
+	configPath, cleanup := setupTest(t)
+	defer cleanup()
+
+	filePath := filepath.Join(t.TempDir(), "upload-test.txt")
+	if err := os.WriteFile(filePath, []byte("hello upload"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	rootCmd := newRootCmd()
+	rootCmd.AddCommand(newUploadCmd())
+
+	_, stderr, err := testExecuteCommandAndCapture(rootCmd, "--config", configPath, "upload", "--file", filePath, "--title", "Nightly report")
+	if err != nil {
+		t.Fatalf("testExecuteCommandAndCapture returned an error: %v\nStderr: %s", err, stderr)
+	}
+	if !strings.Contains(stderr, "PostFile title: {Title:Nightly report}") {
+		t.Errorf("Expected stderr to contain the title, got: '%s'", stderr)
+	}
+	if strings.Contains(stderr, "PostFile file:") {
+		t.Errorf("Expected a single-file upload, got: '%s'", stderr)
+	}
+}
+
+func TestUpload_MultipleFilesTotalSize(t *testing.T) {
+	tempDir := t.TempDir()
+	a := filepath.Join(tempDir, "a.txt")
+	b := filepath.Join(tempDir, "b.txt")
+	for _, path := range []string{a, b} {
+		if err := os.WriteFile(path, []byte("123456"), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Each file fits the per-file limit, and their total is checked against
+	// the separate total limit, not the per-file one.
+	configPath := filepath.Join(tempDir, "config.json")
+	configContent := `{
+		"current_profile": "test",
+		"profiles": {
+			"test": {
+				"provider": "test",
+				"channel": "#test-channel",
+				"limits": {"max_file_size_bytes": 10, "max_upload_total_bytes": 20}
+			}
+		}
+	}`
+	if err := os.WriteFile(configPath, []byte(configContent), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	rootCmd := newRootCmd()
+	rootCmd.AddCommand(newUploadCmd())
+	_, stderr, err := testExecuteCommandAndCapture(rootCmd, "--config", configPath, "upload", "-f", a, "-f", b)
+	if err != nil {
+		t.Fatalf("testExecuteCommandAndCapture returned an error: %v\nStderr: %s", err, stderr)
+	}
+	if !strings.Contains(stderr, "PostFile called") {
+		t.Errorf("Expected the files to be uploaded, got: '%s'", stderr)
+	}
+}
+
+func TestUpload_MultipleFilesErrors(t *testing.T) {
+	tempDir := t.TempDir()
+	a := filepath.Join(tempDir, "a.txt")
+	b := filepath.Join(tempDir, "b.txt")
+	for _, path := range []string{a, b} {
+		if err := os.WriteFile(path, []byte("123456"), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		name       string
+		limit      int64
+		totalLimit int64
+		args       []string
+		wantErr    string
+	}{
+		{"no match", 0, 0, []string{"-f", filepath.Join(tempDir, "*.png")}, "no files match"},
+		{"stdin with files", 0, 0, []string{"-f", "-", "-f", a}, "cannot combine stdin"},
+		{"filename with files", 0, 0, []string{"-f", a, "-f", b, "--filename", "x.txt"}, "cannot use --filename"},
+		{"too many titles", 0, 0, []string{"-f", a, "--title", "A", "--title", "B"}, "got 2 --title values for 1 files"},
+		{"per-file limit", 5, 0, []string{"-f", a, "-f", b}, "size of '" + a + "' (6 bytes) exceeds the configured limit (5 bytes)"},
+		{"total limit", 10, 10, []string{"-f", a, "-f", b}, "total size of 2 files (12 bytes) exceeds the configured total limit (10 bytes)"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			configPath := filepath.Join(t.TempDir(), "config.json")
+			configContent := fmt.Sprintf(`{
+				"current_profile": "test",
+				"profiles": {
+					"test": {
+						"provider": "test",
+						"channel": "#test-channel",
+						"limits": {"max_file_size_bytes": %d, "max_upload_total_bytes": %d}
+					}
+				}
+			}`, tt.limit, tt.totalLimit)
+			if err := os.WriteFile(configPath, []byte(configContent), 0600); err != nil {
+				t.Fatal(err)
+			}
+
+			rootCmd := newRootCmd()
+			rootCmd.AddCommand(newUploadCmd())
+
+			args := append([]string{"--config", configPath, "upload"}, tt.args...)
+			_, stderr, err := testExecuteCommandAndCapture(rootCmd, args...)
+			if err == nil {
+				t.Fatalf("Expected an error, got nil. Stderr: %s", stderr)
+			}
+			if !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Expected error containing '%s', got: %v", tt.wantErr, err)
+			}
+			if strings.Contains(stderr, "PostFile called") {
+				t.Errorf("Expected no upload, got: '%s'", stderr)
+			}
+		})
+	}
+}
+
+func TestUpload_FromStdinStreamsWithoutTempFile(t *testing.T) {
+	configPath, cleanup := setupTest(t)
+	defer cleanup()
+
+	// Redirect a regular file to stdin, as in `scat upload -f - < report.txt`.
+	stdinPath := filepath.Join(t.TempDir(), "report.txt")
+	if err := os.WriteFile(stdinPath, []byte("redirected content"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	stdinFile, err := os.Open(stdinPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer stdinFile.Close()
+	oldStdin := os.Stdin
+	defer func() { os.Stdin = oldStdin }()
+	os.Stdin = stdinFile
+
+	rootCmd := newRootCmd()
+	rootCmd.AddCommand(newUploadCmd())
+
+	_, stderr, err := testExecuteCommandAndCapture(rootCmd, "--config", configPath, "upload", "--file", "-", "--filename", "report.txt")
+	if err != nil {
+		t.Fatalf("testExecuteCommandAndCapture returned an error: %v\nStderr: %s", err, stderr)
+	}
+	if !strings.Contains(stderr, "PostFile called with opts: {TargetChannel: TargetUserID: FilePath: Filename:report.txt ") {
+		t.Errorf("Expected stdin to be passed as content without a file path, got: %s", stderr)
+	}
+	if !strings.Contains(stderr, "PostFile content: {Size:18 Content:redirected content}") {
+		t.Errorf("Expected stderr to contain the streamed content, got: %s", stderr)
+	}
+}
+
+func TestReadUploadStdin(t *testing.T) {
+	t.Run("pipe", func(t *testing.T) {
+		r, w, err := os.Pipe()
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer r.Close()
+		go func() {
+			defer w.Close()
+			_, _ = w.WriteString("piped data")
+		}()
+
+		content, size, err := readUploadStdin(r, 100)
+		if err != nil {
+			t.Fatalf("readUploadStdin() returned an unexpected error: %v", err)
+		}
+		data, _ := io.ReadAll(content)
+		if size != 10 || string(data) != "piped data" {
+			t.Errorf("readUploadStdin() = %q (%d bytes), want \"piped data\" (10 bytes)", data, size)
+		}
+	})
+
+	t.Run("pipe over limit", func(t *testing.T) {
+		r, w, err := os.Pipe()
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer r.Close()
+		go func() {
+			defer w.Close()
+			_, _ = w.WriteString("piped data")
+		}()
+
+		if _, _, err := readUploadStdin(r, 5); err == nil || !strings.Contains(err.Error(), "stdin size exceeds the configured limit (5 bytes)") {
+			t.Errorf("Expected a size limit error, got: %v", err)
+		}
+	})
+
+	t.Run("regular file over limit", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "big.txt")
+		if err := os.WriteFile(path, []byte("0123456789"), 0600); err != nil {
+			t.Fatal(err)
+		}
+		f, err := os.Open(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer f.Close()
+
+		if _, _, err := readUploadStdin(f, 5); err == nil || !strings.Contains(err.Error(), "stdin size exceeds the configured limit (5 bytes)") {
+			t.Errorf("Expected a size limit error, got: %v", err)
+		}
+	})
+}
+
+func TestUpload_Dir(t *testing.T) {
+	configPath, cleanup := setupTest(t)
+	defer cleanup()
+
+	dir := filepath.Join(t.TempDir(), "artifacts")
+	for _, name := range []string{"report.xml", "debug.log", "logs/run.log"} {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte("content of "+name), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	rootCmd := newRootCmd()
+	rootCmd.AddCommand(newUploadCmd())
+
+	_, stderr, err := testExecuteCommandAndCapture(rootCmd, "--config", configPath, "upload", "--dir", dir, "--archive", "tar.gz", "--exclude", "debug.log")
+	if err != nil {
+		t.Fatalf("testExecuteCommandAndCapture returned an error: %v\nStderr: %s", err, stderr)
+	}
+	if !regexp.MustCompile(`Archiving 2 files into 'artifacts-\d{8}-\d{6}\.tar\.gz'`).MatchString(stderr) {
+		t.Errorf("Expected stderr to report the archive, got: %s", stderr)
+	}
+	if !regexp.MustCompile(`PostFile called with opts: \{TargetChannel: TargetUserID: FilePath: Filename:artifacts-\d{8}-\d{6}\.tar\.gz `).MatchString(stderr) {
+		t.Errorf("Expected the archive to be uploaded as content, got: %s", stderr)
+	}
+}
+
+func TestUpload_ArchiveErrors(t *testing.T) {
+	tempDir := t.TempDir()
+	file := filepath.Join(tempDir, "a.txt")
+	if err := os.WriteFile(file, []byte("0123456789"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		limit   int64
+		args    []string
+		wantErr string
+	}{
+		{"file and dir", 0, []string{"--dir", tempDir, "-f", file}, "cannot use --file and --dir flags simultaneously"},
+		{"include without dir", 0, []string{"-f", file, "--include", "*.txt"}, "--include and --exclude can only be used with --dir"},
+		{"unknown format", 0, []string{"--dir", tempDir, "--archive", "rar"}, "unsupported archive format 'rar'"},
+		{"archive stdin", 0, []string{"-f", "-", "--archive", "zip"}, "cannot use --archive with stdin"},
+		{"not a directory", 0, []string{"--dir", file}, "is not a directory"},
+		{"no matching files", 0, []string{"--dir", tempDir, "--include", "*.png"}, "no files to archive"},
+		{"archive too large", 50, []string{"--dir", tempDir}, "archive size exceeds the configured limit (50 bytes)"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			configPath := filepath.Join(t.TempDir(), "config.json")
+			configContent := fmt.Sprintf(`{
+				"current_profile": "test",
+				"profiles": {
+					"test": {
+						"provider": "test",
+						"channel": "#test-channel",
+						"limits": {"max_file_size_bytes": %d}
+					}
+				}
+			}`, tt.limit)
+			if err := os.WriteFile(configPath, []byte(configContent), 0600); err != nil {
+				t.Fatal(err)
+			}
+
+			rootCmd := newRootCmd()
+			rootCmd.AddCommand(newUploadCmd())
+
+			args := append([]string{"--config", configPath, "upload"}, tt.args...)
+			_, stderr, err := testExecuteCommandAndCapture(rootCmd, args...)
+			if err == nil {
+				t.Fatalf("Expected an error, got nil. Stderr: %s", stderr)
+			}
+			if !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Expected error containing '%s', got: %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestUpload_SnippetAndAltText(t *testing.T) {
+	tempDir := t.TempDir()
+	script := filepath.Join(tempDir, "deploy.sh")
+	image := filepath.Join(tempDir, "chart.png")
+	for _, path := range []string{script, image} {
+		if err := os.WriteFile(path, []byte("content"), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		name string
+		args []string
+		want []string
+	}{
+		{
+			name: "snippet",
+			args: []string{"-f", script, "--snippet"},
+			want: []string{"PostFile snippet: {Snippet:true}"},
+		},
+		{
+			name: "snippet with filetype",
+			args: []string{"-f", script, "--snippet", "--filetype", "shell"},
+			want: []string{"Filetype:shell ", "PostFile snippet: {Snippet:true}"},
+		},
+		{
+			name: "alt text for a single file",
+			args: []string{"-f", image, "--alt-text", "Build times per day"},
+			want: []string{"PostFile alt text: {AltText:Build times per day}"},
+		},
+		{
+			name: "alt text per file",
+			args: []string{"-f", image, "-f", script, "--alt-text", "Build times per day"},
+			want: []string{"PostFile file alt text: {Filename:chart.png AltText:Build times per day}"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			configPath, cleanup := setupTest(t)
+			defer cleanup()
+
+			rootCmd := newRootCmd()
+			rootCmd.AddCommand(newUploadCmd())
+
+			args := append([]string{"--config", configPath, "upload"}, tt.args...)
+			_, stderr, err := testExecuteCommandAndCapture(rootCmd, args...)
+			if err != nil {
+				t.Fatalf("testExecuteCommandAndCapture returned an error: %v\nStderr: %s", err, stderr)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(stderr, want) {
+					t.Errorf("Expected stderr to contain '%s', got: '%s'", want, stderr)
+				}
+			}
+		})
+	}
+}
+
+// A binary file uploaded with --filetype, but without --snippet, is uploaded
+// as a regular file rather than rejected as a snippet.
+func TestUpload_FiletypeWithoutSnippet(t *testing.T) {
+	configPath, cleanup := setupTest(t)
+	defer cleanup()
+
+	report := filepath.Join(t.TempDir(), "report.pdf")
+	if err := os.WriteFile(report, []byte("%PDF-1.7\n\x00\x01\x02\xff binary"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	rootCmd := newRootCmd()
+	rootCmd.AddCommand(newUploadCmd())
+	_, stderr, err := testExecuteCommandAndCapture(rootCmd, "--config", configPath, "upload", "-f", report, "--filetype", "pdf")
+	if err != nil {
+		t.Fatalf("testExecuteCommandAndCapture returned an error: %v\nStderr: %s", err, stderr)
+	}
+	if strings.Contains(stderr, "PostFile snippet:") {
+		t.Errorf("Expected --filetype alone not to upload a snippet, got: %s", stderr)
+	}
+}
+
+func TestUpload_SnippetErrors(t *testing.T) {
+	configPath, cleanup := setupTest(t)
+	defer cleanup()
+
+	tempDir := t.TempDir()
+	file := filepath.Join(tempDir, "a.txt")
+	if err := os.WriteFile(file, []byte("text"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		args    []string
+		wantErr string
+	}{
+		{"snippet archive", []string{"--dir", tempDir, "--snippet"}, "cannot upload an archive as a snippet"},
+		{"alt text archive", []string{"--dir", tempDir, "--alt-text", "x"}, "cannot use --alt-text with --archive"},
+		{"too many alt texts", []string{"-f", file, "--alt-text", "x", "--alt-text", "y"}, "got 2 --alt-text values for 1 files"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rootCmd := newRootCmd()
+			rootCmd.AddCommand(newUploadCmd())
+
+			args := append([]string{"--config", configPath, "upload"}, tt.args...)
+			_, stderr, err := testExecuteCommandAndCapture(rootCmd, args...)
+			if err == nil {
+				t.Fatalf("Expected an error, got nil. Stderr: %s", stderr)
+			}
+			if !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Expected error containing '%s', got: %v", tt.wantErr, err)
+			}
+		})
+	}
+}
@@ -41,7 +41,7 @@ func newUserListCmd() *cobra.Command {
 					continue
 				}
 
-				users, err := prov.ListUsers()
+				users, err := prov.ListUsers(cmd.Context())
 				if err != nil {
 					fmt.Fprintf(os.Stderr, "Warning: could not list users for profile '%s': %v\n", profileName, err)
 					continue
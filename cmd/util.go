@@ -2,11 +2,13 @@
 package cmd
 
 import (
+	"encoding/json"
 	"fmt"
 	"os"
 	"syscall"
 	"time"
 
+	"github.com/nlink-jp/scat/internal/util"
 	"golang.org/x/term"
 )
 
@@ -28,3 +30,43 @@ func GetPasswordFromPrompt(prompt string) (string, error) {
 // CreateTicker is a variable that holds the function to create a new ticker.
 // It can be replaced in tests for mocking purposes.
 var CreateTicker = time.NewTicker
+
+// printJSON writes v to stdout as indented JSON.
+func printJSON(v any) error {
+	jsonBytes, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal results to json: %w", err)
+	}
+	fmt.Println(string(jsonBytes))
+	return nil
+}
+
+// resolveThreadTS parses the --thread-ts flag, which accepts a raw message
+// timestamp or a message permalink, and returns the thread parent's timestamp.
+// A permalink also supplies the channel when neither --channel nor --user is set.
+func resolveThreadTS(value, channel, user string) (threadTS string, resolvedChannel string, err error) {
+	if value == "" {
+		return "", channel, nil
+	}
+	ref, err := util.ParseMessageRef(value)
+	if err != nil {
+		return "", "", fmt.Errorf("invalid value for --thread-ts: %w", err)
+	}
+	if ref.ChannelID != "" && channel == "" && user == "" {
+		channel = ref.ChannelID
+	}
+	return ref.ThreadRoot(), channel, nil
+}
+
+// resolveMessageRef parses a message reference given as a raw timestamp or a
+// message permalink. A permalink supplies the channel when --channel is empty.
+func resolveMessageRef(value, channel string) (resolvedChannel string, ts string, err error) {
+	ref, err := util.ParseMessageRef(value)
+	if err != nil {
+		return "", "", err
+	}
+	if ref.ChannelID != "" && channel == "" {
+		channel = ref.ChannelID
+	}
+	return channel, ref.Timestamp, nil
+}
@@ -4,11 +4,11 @@ go 1.24.6
 
 require (
 	github.com/spf13/cobra v1.9.1
+	golang.org/x/sys v0.35.0
 	golang.org/x/term v0.34.0
 )
 
 require (
 	github.com/inconshreveable/mousetrap v1.1.0 // indirect
 	github.com/spf13/pflag v1.0.7 // indirect
-	golang.org/x/sys v0.35.0 // indirect
 )
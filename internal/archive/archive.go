@@ -0,0 +1,302 @@
+// Package archive builds zip and tar.gz archives of local files on the fly.
+//
+// Uploads need the archive size before any content is sent, but writing the
+// archive to a temporary file would double disk I/O. An Archive therefore
+// builds the archive twice: a dry run that only counts bytes determines the
+// size, and the archive is then regenerated through a pipe while it is being
+// read. Both runs produce identical bytes because the output depends only on
+// the file list, the file contents, and their modification times.
+package archive
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Format is an archive format.
+type Format string
+
+const (
+	Zip   Format = "zip"
+	TarGz Format = "tar.gz"
+)
+
+// ParseFormat parses an archive format name. "tgz" is accepted as an alias of "tar.gz".
+func ParseFormat(s string) (Format, error) {
+	switch strings.ToLower(s) {
+	case "zip":
+		return Zip, nil
+	case "tar.gz", "tgz":
+		return TarGz, nil
+	}
+	return "", fmt.Errorf("unsupported archive format '%s' (use zip or tar.gz)", s)
+}
+
+// Ext returns the file name extension for the format, without a leading dot.
+func (f Format) Ext() string {
+	return string(f)
+}
+
+// Entry is a file to add to an archive.
+type Entry struct {
+	Path string // Local path of the file.
+	Name string // Slash-separated name of the file inside the archive.
+}
+
+// Collect walks dir and returns its regular files in lexical order, named
+// relative to dir. A file is included if it matches any include pattern (all
+// files if there are none) and no exclude pattern. Patterns use path.Match
+// syntax and are matched against both the relative path and the base name;
+// a directory matching an exclude pattern is skipped entirely. Symbolic links
+// and other special files are not followed.
+func Collect(dir string, include, exclude []string) ([]Entry, error) {
+	for _, pattern := range append(append([]string{}, include...), exclude...) {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return nil, fmt.Errorf("invalid pattern '%s': %w", pattern, err)
+		}
+	}
+
+	var entries []Entry
+	err := filepath.WalkDir(dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if p == dir {
+			return nil
+		}
+		rel, err := filepath.Rel(dir, p)
+		if err != nil {
+			return err
+		}
+		name := filepath.ToSlash(rel)
+		if d.IsDir() {
+			if matchAny(exclude, name) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !d.Type().IsRegular() || matchAny(exclude, name) {
+			return nil
+		}
+		if len(include) > 0 && !matchAny(include, name) {
+			return nil
+		}
+		entries = append(entries, Entry{Path: p, Name: name})
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read directory '%s': %w", dir, err)
+	}
+	return entries, nil
+}
+
+// matchAny reports whether name or its base name matches one of patterns.
+func matchAny(patterns []string, name string) bool {
+	for _, pattern := range patterns {
+		if ok, _ := path.Match(pattern, name); ok {
+			return true
+		}
+		if ok, _ := path.Match(pattern, path.Base(name)); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// ErrTooLarge is returned by New when the archive would exceed the size limit.
+var ErrTooLarge = errors.New("archive too large")
+
+// Archive is an io.ReadSeeker producing an archive of a fixed list of files.
+// Seeking is only supported to the start, which regenerates the archive so
+// that an upload can be retried.
+type Archive struct {
+	entries []Entry
+	format  Format
+	size    int64
+
+	pr   *io.PipeReader
+	read int64
+}
+
+// New checks that the files can be archived and determines the archive size.
+// If limit is positive and the archive would be larger, it returns an error
+// wrapping ErrTooLarge as soon as the limit is exceeded.
+func New(entries []Entry, format Format, limit int64) (*Archive, error) {
+	if len(entries) == 0 {
+		return nil, fmt.Errorf("no files to archive")
+	}
+	entries = append([]Entry(nil), entries...)
+	sort.SliceStable(entries, func(i, j int) bool { return entries[i].Name < entries[j].Name })
+
+	a := &Archive{entries: entries, format: format}
+	cw := &countingWriter{limit: limit}
+	if err := a.write(cw); err != nil {
+		if errors.Is(err, ErrTooLarge) {
+			return nil, fmt.Errorf("%w: archive size exceeds the configured limit (%d bytes)", ErrTooLarge, limit)
+		}
+		return nil, err
+	}
+	a.size = cw.n
+	return a, nil
+}
+
+// Len returns the number of files in the archive.
+func (a *Archive) Len() int {
+	return len(a.entries)
+}
+
+// Size returns the size of the archive in bytes.
+func (a *Archive) Size() int64 {
+	return a.size
+}
+
+// Read reads the next chunk of the archive, starting to generate it on first use.
+func (a *Archive) Read(p []byte) (int, error) {
+	if a.pr == nil {
+		pr, pw := io.Pipe()
+		a.pr, a.read = pr, 0
+		go func() {
+			cw := &countingWriter{w: pw}
+			err := a.write(cw)
+			if err == nil && cw.n != a.size {
+				err = fmt.Errorf("files changed while they were being archived")
+			}
+			pw.CloseWithError(err)
+		}()
+	}
+	n, err := a.pr.Read(p)
+	a.read += int64(n)
+	return n, err
+}
+
+// Seek rewinds the archive. Only Seek(0, io.SeekStart) and queries of the
+// current offset are supported.
+func (a *Archive) Seek(offset int64, whence int) (int64, error) {
+	switch {
+	case offset == 0 && whence == io.SeekCurrent:
+		return a.read, nil
+	case offset == 0 && whence == io.SeekStart:
+		a.Close()
+		return 0, nil
+	}
+	return 0, fmt.Errorf("archive: unsupported seek")
+}
+
+// Close stops generating the archive. The next Read starts over.
+func (a *Archive) Close() error {
+	if a.pr != nil {
+		a.pr.Close()
+		a.pr, a.read = nil, 0
+	}
+	return nil
+}
+
+// write writes the complete archive to w.
+func (a *Archive) write(w io.Writer) error {
+	switch a.format {
+	case Zip:
+		return a.writeZip(w)
+	case TarGz:
+		return a.writeTarGz(w)
+	}
+	return fmt.Errorf("unsupported archive format '%s'", a.format)
+}
+
+func (a *Archive) writeZip(w io.Writer) error {
+	zw := zip.NewWriter(w)
+	for _, e := range a.entries {
+		err := addFile(e, func(fi os.FileInfo) (io.Writer, error) {
+			hdr, err := zip.FileInfoHeader(fi)
+			if err != nil {
+				return nil, err
+			}
+			hdr.Name = e.Name
+			hdr.Method = zip.Deflate
+			return zw.CreateHeader(hdr)
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return zw.Close()
+}
+
+func (a *Archive) writeTarGz(w io.Writer) error {
+	gw := gzip.NewWriter(w)
+	tw := tar.NewWriter(gw)
+	for _, e := range a.entries {
+		err := addFile(e, func(fi os.FileInfo) (io.Writer, error) {
+			hdr, err := tar.FileInfoHeader(fi, "")
+			if err != nil {
+				return nil, err
+			}
+			// Drop owner names and access times so that the output only
+			// depends on the file itself.
+			hdr.Name = e.Name
+			hdr.Uname, hdr.Gname = "", ""
+			hdr.AccessTime, hdr.ChangeTime = time.Time{}, time.Time{}
+			hdr.Format = tar.FormatPAX
+			return tw, tw.WriteHeader(hdr)
+		})
+		if err != nil {
+			return err
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	return gw.Close()
+}
+
+// addFile opens the file of e, creates its archive member with create, and
+// copies the content into it. The size is taken from the open file so that
+// the header always matches the content that is copied.
+func addFile(e Entry, create func(fi os.FileInfo) (io.Writer, error)) error {
+	f, err := os.Open(e.Path)
+	if err != nil {
+		return fmt.Errorf("failed to open '%s': %w", e.Path, err)
+	}
+	defer f.Close()
+	fi, err := f.Stat()
+	if err != nil {
+		return fmt.Errorf("failed to get file info of '%s': %w", e.Path, err)
+	}
+	w, err := create(fi)
+	if err != nil {
+		return fmt.Errorf("failed to add '%s' to the archive: %w", e.Path, err)
+	}
+	if _, err := io.CopyN(w, f, fi.Size()); err != nil {
+		return fmt.Errorf("failed to add '%s' to the archive: %w", e.Path, err)
+	}
+	return nil
+}
+
+// countingWriter counts the bytes written to an optional underlying writer and
+// fails with ErrTooLarge once a positive limit is exceeded.
+type countingWriter struct {
+	w     io.Writer
+	n     int64
+	limit int64
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	c.n += int64(len(p))
+	if c.limit > 0 && c.n > c.limit {
+		return 0, ErrTooLarge
+	}
+	if c.w == nil {
+		return len(p), nil
+	}
+	return c.w.Write(p)
+}
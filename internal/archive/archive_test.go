@@ -0,0 +1,202 @@
+package archive
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// writeTree creates files (relative slash paths mapped to contents) under a new temp dir.
+func writeTree(t *testing.T, files map[string]string) string {
+	t.Helper()
+	dir := t.TempDir()
+	for name, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func entryNames(entries []Entry) []string {
+	var names []string
+	for _, e := range entries {
+		names = append(names, e.Name)
+	}
+	return names
+}
+
+func TestCollect(t *testing.T) {
+	dir := writeTree(t, map[string]string{
+		"report.xml":          "<xml/>",
+		"summary.txt":         "ok",
+		"logs/run.log":        "log",
+		"logs/debug.log":      "debug",
+		"node_modules/x/a.js": "js",
+	})
+
+	tests := []struct {
+		name             string
+		include, exclude []string
+		want             []string
+	}{
+		{"all files", nil, nil, []string{"logs/debug.log", "logs/run.log", "node_modules/x/a.js", "report.xml", "summary.txt"}},
+		{"include by base name", []string{"*.log"}, nil, []string{"logs/debug.log", "logs/run.log"}},
+		{"include by path", []string{"logs/run.*"}, nil, []string{"logs/run.log"}},
+		{"exclude directory", nil, []string{"node_modules"}, []string{"logs/debug.log", "logs/run.log", "report.xml", "summary.txt"}},
+		{"include and exclude", []string{"*.log", "*.xml"}, []string{"debug.*"}, []string{"logs/run.log", "report.xml"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entries, err := Collect(dir, tt.include, tt.exclude)
+			if err != nil {
+				t.Fatalf("Collect() returned an unexpected error: %v", err)
+			}
+			if got := entryNames(entries); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Collect() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	if _, err := Collect(dir, []string{"["}, nil); err == nil {
+		t.Error("Collect() with an invalid pattern: expected an error, got nil")
+	}
+}
+
+func TestArchive_Zip(t *testing.T) {
+	dir := writeTree(t, map[string]string{"a.txt": "alpha", "sub/b.txt": "bravo"})
+	entries, err := Collect(dir, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	a, err := New(entries, Zip, 0)
+	if err != nil {
+		t.Fatalf("New() returned an unexpected error: %v", err)
+	}
+	defer a.Close()
+	data, err := io.ReadAll(a)
+	if err != nil {
+		t.Fatalf("Reading the archive failed: %v", err)
+	}
+	if int64(len(data)) != a.Size() {
+		t.Errorf("Read %d bytes, but Size() = %d", len(data), a.Size())
+	}
+
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		t.Fatalf("Invalid zip archive: %v", err)
+	}
+	got := map[string]string{}
+	for _, f := range zr.File {
+		rc, err := f.Open()
+		if err != nil {
+			t.Fatal(err)
+		}
+		content, _ := io.ReadAll(rc)
+		rc.Close()
+		got[f.Name] = string(content)
+	}
+	want := map[string]string{"a.txt": "alpha", "sub/b.txt": "bravo"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Archive contents = %v, want %v", got, want)
+	}
+
+	// Rewinding regenerates identical bytes, as needed to retry an upload.
+	partial := make([]byte, 10)
+	if _, err := a.Seek(0, io.SeekStart); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := io.ReadFull(a, partial); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := a.Seek(0, io.SeekStart); err != nil {
+		t.Fatal(err)
+	}
+	again, err := io.ReadAll(a)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(data, again) {
+		t.Error("Expected a rewound archive to produce identical bytes")
+	}
+}
+
+func TestArchive_TarGz(t *testing.T) {
+	dir := writeTree(t, map[string]string{"a.txt": "alpha", "sub/b.txt": "bravo"})
+	entries, err := Collect(dir, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	a, err := New(entries, TarGz, 0)
+	if err != nil {
+		t.Fatalf("New() returned an unexpected error: %v", err)
+	}
+	defer a.Close()
+	data, err := io.ReadAll(a)
+	if err != nil {
+		t.Fatalf("Reading the archive failed: %v", err)
+	}
+	if int64(len(data)) != a.Size() {
+		t.Errorf("Read %d bytes, but Size() = %d", len(data), a.Size())
+	}
+
+	gr, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("Invalid gzip stream: %v", err)
+	}
+	tr := tar.NewReader(gr)
+	got := map[string]string{}
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("Invalid tar archive: %v", err)
+		}
+		content, _ := io.ReadAll(tr)
+		got[hdr.Name] = string(content)
+	}
+	want := map[string]string{"a.txt": "alpha", "sub/b.txt": "bravo"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Archive contents = %v, want %v", got, want)
+	}
+}
+
+func TestArchive_Limit(t *testing.T) {
+	dir := writeTree(t, map[string]string{"a.txt": "alpha"})
+	entries, err := Collect(dir, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := New(entries, Zip, 10); !errors.Is(err, ErrTooLarge) {
+		t.Errorf("Expected ErrTooLarge, got: %v", err)
+	}
+	if _, err := New(nil, Zip, 0); err == nil {
+		t.Error("Expected an error for an empty archive, got nil")
+	}
+}
+
+func TestParseFormat(t *testing.T) {
+	for input, want := range map[string]Format{"zip": Zip, "tar.gz": TarGz, "TGZ": TarGz} {
+		if got, err := ParseFormat(input); err != nil || got != want {
+			t.Errorf("ParseFormat(%q) = %q, %v; want %q", input, got, err, want)
+		}
+	}
+	if _, err := ParseFormat("rar"); err == nil {
+		t.Error("ParseFormat(\"rar\"): expected an error, got nil")
+	}
+}
@@ -27,7 +27,8 @@ func DetectServerMode() (bool, error) {
 
 // BuildConfigFromEnv constructs a virtual Config from environment variables for server mode.
 // SCAT_PROVIDER and SCAT_TOKEN are required; SCAT_CHANNEL, SCAT_USERNAME,
-// SCAT_MAX_FILE_SIZE, and SCAT_MAX_STDIN_SIZE are optional.
+// SCAT_MAX_FILE_SIZE, SCAT_MAX_UPLOAD_TOTAL_SIZE, SCAT_MAX_STDIN_SIZE,
+// SCAT_RETRY_MAX_ATTEMPTS, and SCAT_RETRY_MAX_ELAPSED_SECONDS are optional.
 // The resulting Config has a single profile named "server".
 func BuildConfigFromEnv() (*Config, error) {
 	p := os.Getenv("SCAT_PROVIDER")
@@ -48,6 +49,10 @@ func BuildConfigFromEnv() (*Config, error) {
 	if err != nil {
 		return nil, err
 	}
+	retry, err := retryFromEnv()
+	if err != nil {
+		return nil, err
+	}
 
 	return &Config{
 		CurrentProfile: "server",
@@ -58,12 +63,13 @@ func BuildConfigFromEnv() (*Config, error) {
 				Channel:  os.Getenv("SCAT_CHANNEL"),
 				Username: os.Getenv("SCAT_USERNAME"),
 				Limits:   limits,
+				Retry:    retry,
 			},
 		},
 	}, nil
 }
 
-// limitsFromEnv reads SCAT_MAX_FILE_SIZE and SCAT_MAX_STDIN_SIZE, falling back to defaults.
+// limitsFromEnv reads SCAT_MAX_FILE_SIZE, SCAT_MAX_UPLOAD_TOTAL_SIZE, and SCAT_MAX_STDIN_SIZE, falling back to defaults.
 func limitsFromEnv() (Limits, error) {
 	defaults := NewDefaultLimits()
 	limits := defaults
@@ -75,6 +81,13 @@ func limitsFromEnv() (Limits, error) {
 		}
 		limits.MaxFileSizeBytes = n
 	}
+	if v := os.Getenv("SCAT_MAX_UPLOAD_TOTAL_SIZE"); v != "" {
+		n, err := strconv.ParseInt(v, 10, 64)
+		if err != nil || n < 0 {
+			return Limits{}, fmt.Errorf("invalid SCAT_MAX_UPLOAD_TOTAL_SIZE value %q: must be a non-negative integer (bytes)", v)
+		}
+		limits.MaxUploadTotalBytes = n
+	}
 	if v := os.Getenv("SCAT_MAX_STDIN_SIZE"); v != "" {
 		n, err := strconv.ParseInt(v, 10, 64)
 		if err != nil || n < 0 {
@@ -85,6 +98,27 @@ func limitsFromEnv() (Limits, error) {
 	return limits, nil
 }
 
+// retryFromEnv reads SCAT_RETRY_MAX_ATTEMPTS and SCAT_RETRY_MAX_ELAPSED_SECONDS, falling back to defaults.
+func retryFromEnv() (Retry, error) {
+	retry := NewDefaultRetry()
+
+	if v := os.Getenv("SCAT_RETRY_MAX_ATTEMPTS"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 {
+			return Retry{}, fmt.Errorf("invalid SCAT_RETRY_MAX_ATTEMPTS value %q: must be a positive integer", v)
+		}
+		retry.MaxAttempts = n
+	}
+	if v := os.Getenv("SCAT_RETRY_MAX_ELAPSED_SECONDS"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 0 {
+			return Retry{}, fmt.Errorf("invalid SCAT_RETRY_MAX_ELAPSED_SECONDS value %q: must be a non-negative integer (seconds)", v)
+		}
+		retry.MaxElapsedSeconds = n
+	}
+	return retry, nil
+}
+
 const (
 	configDir  = ".config"
 	configFile = "scat/config.json"
@@ -104,18 +138,38 @@ type Profile struct {
 	Token    string `json:"token,omitempty"`
 	Username string `json:"username,omitempty"`
 	Limits   Limits `json:"limits"`
+	Retry    Retry  `json:"retry"`
 }
 
 // Limits defines the size limits for inputs.
 type Limits struct {
 	MaxFileSizeBytes int64 `json:"max_file_size_bytes,omitempty"`
+	MaxUploadTotalBytes int64 `json:"max_upload_total_bytes,omitempty"`
 	MaxStdinSizeBytes int64 `json:"max_stdin_size_bytes,omitempty"`
 }
 
+// Retry defines how transient failures (network errors and 5xx responses) are
+// retried. MaxAttempts is the total number of attempts per call, including the
+// first, so 1 disables retries. MaxElapsedSeconds bounds the time spent
+// retrying a call; 0 means no bound.
+type Retry struct {
+	MaxAttempts       int `json:"max_attempts,omitempty"`
+	MaxElapsedSeconds int `json:"max_elapsed_seconds,omitempty"`
+}
+
+// NewDefaultRetry returns a Retry struct with default values.
+func NewDefaultRetry() Retry {
+	return Retry{
+		MaxAttempts:       3,
+		MaxElapsedSeconds: 60,
+	}
+}
+
 // NewDefaultLimits returns a Limits struct with default values.
 func NewDefaultLimits() Limits {
 	return Limits{
 		MaxFileSizeBytes: 1024 * 1024 * 1024, // 1 GB
+		MaxUploadTotalBytes: 10 * 1024 * 1024 * 1024, // 10 GB
 		MaxStdinSizeBytes: 10 * 1024 * 1024,  // 10 MB
 	}
 }
@@ -129,6 +183,7 @@ func NewDefaultConfig() *Config {
 				Provider: "mock",
 				Channel:  "#mock-channel",
 				Limits:   NewDefaultLimits(),
+				Retry:    NewDefaultRetry(),
 			},
 		},
 	}
@@ -146,12 +201,23 @@ func Load(configPath string) (*Config, error) {
 		return nil, err
 	}
 
-	// For backward compatibility, populate limits if they are not set.
+	// For backward compatibility, populate limits and retry settings if they are not set.
 	for name, profile := range cfg.Profiles {
 		if profile.Limits.MaxFileSizeBytes == 0 && profile.Limits.MaxStdinSizeBytes == 0 {
 			profile.Limits = NewDefaultLimits()
 			cfg.Profiles[name] = profile
 		}
+		if profile.Limits.MaxUploadTotalBytes == 0 {
+			profile.Limits.MaxUploadTotalBytes = NewDefaultLimits().MaxUploadTotalBytes
+			cfg.Profiles[name] = profile
+		}
+		if profile.Retry.MaxAttempts == 0 {
+			profile.Retry.MaxAttempts = NewDefaultRetry().MaxAttempts
+			if profile.Retry.MaxElapsedSeconds == 0 {
+				profile.Retry.MaxElapsedSeconds = NewDefaultRetry().MaxElapsedSeconds
+			}
+			cfg.Profiles[name] = profile
+		}
 	}
 
 	return &cfg, nil
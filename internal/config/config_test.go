@@ -109,9 +109,15 @@ func TestBuildConfigFromEnv(t *testing.T) {
 				if p.Limits.MaxFileSizeBytes != defaults.MaxFileSizeBytes {
 					t.Errorf("MaxFileSizeBytes = %d, want %d", p.Limits.MaxFileSizeBytes, defaults.MaxFileSizeBytes)
 				}
+				if p.Limits.MaxUploadTotalBytes != defaults.MaxUploadTotalBytes {
+					t.Errorf("MaxUploadTotalBytes = %d, want %d", p.Limits.MaxUploadTotalBytes, defaults.MaxUploadTotalBytes)
+				}
 				if p.Limits.MaxStdinSizeBytes != defaults.MaxStdinSizeBytes {
 					t.Errorf("MaxStdinSizeBytes = %d, want %d", p.Limits.MaxStdinSizeBytes, defaults.MaxStdinSizeBytes)
 				}
+				if p.Retry != NewDefaultRetry() {
+					t.Errorf("Retry = %+v, want %+v", p.Retry, NewDefaultRetry())
+				}
 			},
 		},
 		{
@@ -120,6 +126,7 @@ func TestBuildConfigFromEnv(t *testing.T) {
 				"SCAT_PROVIDER":      "slack",
 				"SCAT_TOKEN":         "xoxb-test",
 				"SCAT_MAX_FILE_SIZE": "5242880",
+				"SCAT_MAX_UPLOAD_TOTAL_SIZE": "20971520",
 				"SCAT_MAX_STDIN_SIZE": "1048576",
 			},
 			check: func(t *testing.T, cfg *Config) {
@@ -127,6 +134,9 @@ func TestBuildConfigFromEnv(t *testing.T) {
 				if p.Limits.MaxFileSizeBytes != 5242880 {
 					t.Errorf("MaxFileSizeBytes = %d, want 5242880", p.Limits.MaxFileSizeBytes)
 				}
+				if p.Limits.MaxUploadTotalBytes != 20971520 {
+					t.Errorf("MaxUploadTotalBytes = %d, want 20971520", p.Limits.MaxUploadTotalBytes)
+				}
 				if p.Limits.MaxStdinSizeBytes != 1048576 {
 					t.Errorf("MaxStdinSizeBytes = %d, want 1048576", p.Limits.MaxStdinSizeBytes)
 				}
@@ -137,11 +147,39 @@ func TestBuildConfigFromEnv(t *testing.T) {
 			env:     map[string]string{"SCAT_PROVIDER": "slack", "SCAT_TOKEN": "t", "SCAT_MAX_FILE_SIZE": "abc"},
 			wantErr: true,
 		},
+		{
+			name:    "negative SCAT_MAX_UPLOAD_TOTAL_SIZE",
+			env:     map[string]string{"SCAT_PROVIDER": "slack", "SCAT_TOKEN": "t", "SCAT_MAX_UPLOAD_TOTAL_SIZE": "-1"},
+			wantErr: true,
+		},
 		{
 			name:    "negative SCAT_MAX_STDIN_SIZE",
 			env:     map[string]string{"SCAT_PROVIDER": "slack", "SCAT_TOKEN": "t", "SCAT_MAX_STDIN_SIZE": "-1"},
 			wantErr: true,
 		},
+		{
+			name: "custom retry",
+			env: map[string]string{
+				"SCAT_PROVIDER":                  "slack",
+				"SCAT_TOKEN":                     "xoxb-test",
+				"SCAT_RETRY_MAX_ATTEMPTS":        "5",
+				"SCAT_RETRY_MAX_ELAPSED_SECONDS": "0",
+			},
+			check: func(t *testing.T, cfg *Config) {
+				p := cfg.Profiles["server"]
+				if p.Retry.MaxAttempts != 5 {
+					t.Errorf("Retry.MaxAttempts = %d, want 5", p.Retry.MaxAttempts)
+				}
+				if p.Retry.MaxElapsedSeconds != 0 {
+					t.Errorf("Retry.MaxElapsedSeconds = %d, want 0", p.Retry.MaxElapsedSeconds)
+				}
+			},
+		},
+		{
+			name:    "invalid SCAT_RETRY_MAX_ATTEMPTS",
+			env:     map[string]string{"SCAT_PROVIDER": "slack", "SCAT_TOKEN": "t", "SCAT_RETRY_MAX_ATTEMPTS": "0"},
+			wantErr: true,
+		},
 		{
 			name: "optional fields",
 			env: map[string]string{
@@ -165,7 +203,7 @@ func TestBuildConfigFromEnv(t *testing.T) {
 	for _, tc := range tests {
 		t.Run(tc.name, func(t *testing.T) {
 			// Clear relevant env vars, set test values
-			for _, k := range []string{"SCAT_PROVIDER", "SCAT_TOKEN", "SCAT_CHANNEL", "SCAT_USERNAME", "SCAT_MAX_FILE_SIZE", "SCAT_MAX_STDIN_SIZE"} {
+			for _, k := range []string{"SCAT_PROVIDER", "SCAT_TOKEN", "SCAT_CHANNEL", "SCAT_USERNAME", "SCAT_MAX_FILE_SIZE", "SCAT_MAX_UPLOAD_TOTAL_SIZE", "SCAT_MAX_STDIN_SIZE", "SCAT_RETRY_MAX_ATTEMPTS", "SCAT_RETRY_MAX_ELAPSED_SECONDS"} {
 				t.Setenv(k, "")
 			}
 			for k, v := range tc.env {
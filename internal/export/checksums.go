@@ -0,0 +1,107 @@
+package export
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ChecksumFileName is the file in a directory of downloaded attachments that
+// lists their SHA-256 checksums, in the format of sha256sum, so that
+// `sha256sum -c SHA256SUMS` verifies them.
+const ChecksumFileName = "SHA256SUMS"
+
+// FileIDsFileName is the file in a directory of downloaded attachments that
+// maps the IDs of the downloaded files to the checksums of their content, in
+// the same format. Files with identical content are stored once, under the
+// name of one of them, so later exports find the content of the others
+// through their IDs.
+const FileIDsFileName = ".file-ids"
+
+// Checksums maps the names of files in a directory to their hex-encoded
+// SHA-256 checksums.
+type Checksums map[string]string
+
+// LoadChecksums reads the checksum file in dir. A missing file yields no
+// checksums. If a file is listed more than once, the last entry wins.
+func LoadChecksums(dir string) (Checksums, error) {
+	return loadSums(filepath.Join(dir, ChecksumFileName))
+}
+
+// LoadFileIDs reads the file ID file in dir, returning the checksums keyed by
+// file ID. A missing file yields no checksums.
+func LoadFileIDs(dir string) (Checksums, error) {
+	return loadSums(filepath.Join(dir, FileIDsFileName))
+}
+
+// loadSums reads a file of checksums in the format of sha256sum.
+func loadSums(path string) (Checksums, error) {
+	sums := make(Checksums)
+	f, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return sums, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read checksums: %w", err)
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		sum, name, ok := strings.Cut(scanner.Text(), "  ")
+		if !ok || len(sum) != sha256.Size*2 {
+			continue
+		}
+		sums[name] = sum
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read checksums: %w", err)
+	}
+	return sums, nil
+}
+
+// AppendChecksum adds the checksum of the file name to the checksum file in
+// dir. Names must not contain line breaks.
+func AppendChecksum(dir, name, sum string) error {
+	return appendSum(filepath.Join(dir, ChecksumFileName), name, sum)
+}
+
+// AppendFileID adds the checksum of the content of the file with the ID id to
+// the file ID file in dir.
+func AppendFileID(dir, id, sum string) error {
+	return appendSum(filepath.Join(dir, FileIDsFileName), id, sum)
+}
+
+// appendSum adds a line to a file of checksums in the format of sha256sum.
+func appendSum(path, name, sum string) error {
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to write checksums: %w", err)
+	}
+	if _, err := fmt.Fprintf(f, "%s  %s\n", sum, name); err != nil {
+		f.Close()
+		return fmt.Errorf("failed to write checksums: %w", err)
+	}
+	return f.Close()
+}
+
+// FileChecksum returns the hex-encoded SHA-256 checksum and the size of the
+// file at path.
+func FileChecksum(path string) (string, int64, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", 0, err
+	}
+	defer f.Close()
+	h := sha256.New()
+	n, err := io.Copy(h, f)
+	if err != nil {
+		return "", 0, err
+	}
+	return hex.EncodeToString(h.Sum(nil)), n, nil
+}
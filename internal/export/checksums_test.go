@@ -0,0 +1,71 @@
+package export
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestChecksums(t *testing.T) {
+	dir := t.TempDir()
+	sums, err := LoadChecksums(dir)
+	if err != nil || len(sums) != 0 {
+		t.Fatalf("LoadChecksums() of an empty directory = %v, %v; want no checksums", sums, err)
+	}
+
+	path := filepath.Join(dir, "F01_report.txt")
+	if err := os.WriteFile(path, []byte("report"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	sum, size, err := FileChecksum(path)
+	if err != nil {
+		t.Fatalf("FileChecksum() returned an unexpected error: %v", err)
+	}
+	if want := "845e91831319e89c4d656bdb80c278ac09a7230d61e5dfd2e1b1fbb436ac8917"; sum != want || size != 6 {
+		t.Errorf("FileChecksum() = %s, %d; want %s, 6", sum, size, want)
+	}
+
+	// Later entries for the same file win.
+	stale := "0000000000000000000000000000000000000000000000000000000000000000"
+	for _, entry := range [][2]string{{"F01_report.txt", stale}, {"F02_a b.txt", stale}, {"F01_report.txt", sum}} {
+		if err := AppendChecksum(dir, entry[0], entry[1]); err != nil {
+			t.Fatalf("AppendChecksum() returned an unexpected error: %v", err)
+		}
+	}
+	sums, err = LoadChecksums(dir)
+	if err != nil {
+		t.Fatalf("LoadChecksums() returned an unexpected error: %v", err)
+	}
+	want := Checksums{"F01_report.txt": sum, "F02_a b.txt": stale}
+	if !reflect.DeepEqual(sums, want) {
+		t.Errorf("LoadChecksums() = %v, want %v", sums, want)
+	}
+
+	// The file is in the format of sha256sum.
+	data, _ := os.ReadFile(filepath.Join(dir, ChecksumFileName))
+	if wantLine := sum + "  F01_report.txt\n"; string(data[len(data)-len(wantLine):]) != wantLine {
+		t.Errorf("Expected the checksum file to end with %q, got:\n%s", wantLine, data)
+	}
+}
+
+func TestFileIDs(t *testing.T) {
+	dir := t.TempDir()
+	sum := "845e91831319e89c4d656bdb80c278ac09a7230d61e5dfd2e1b1fbb436ac8917"
+	for _, id := range []string{"F01", "F02"} {
+		if err := AppendFileID(dir, id, sum); err != nil {
+			t.Fatalf("AppendFileID() returned an unexpected error: %v", err)
+		}
+	}
+	ids, err := LoadFileIDs(dir)
+	if err != nil {
+		t.Fatalf("LoadFileIDs() returned an unexpected error: %v", err)
+	}
+	if want := (Checksums{"F01": sum, "F02": sum}); !reflect.DeepEqual(ids, want) {
+		t.Errorf("LoadFileIDs() = %v, want %v", ids, want)
+	}
+	// File IDs are kept apart from the checksums of the files.
+	if sums, err := LoadChecksums(dir); err != nil || len(sums) != 0 {
+		t.Errorf("LoadChecksums() = %v, %v; want no checksums", sums, err)
+	}
+}
@@ -0,0 +1,62 @@
+package export
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// csvHeader is the header row of the CSV format.
+var csvHeader = []string{"timestamp", "ts", "thread_ts", "is_reply", "user_id", "user_name", "post_type", "text", "files"}
+
+// WriteCSV writes log as CSV with a header row and one row per message, for
+// analysis in a spreadsheet. Timestamps are shown in loc (UTC if nil), the
+// Slack entities of the text are decoded, and the names of attached files are
+// joined by "; ". Cells that a spreadsheet would evaluate as a formula are
+// prefixed with a single quote.
+func WriteCSV(w io.Writer, log *ExportedLog, loc *time.Location) error {
+	if loc == nil {
+		loc = time.UTC
+	}
+	cw := csv.NewWriter(w)
+	if err := cw.Write(csvHeader); err != nil {
+		return fmt.Errorf("failed to write CSV: %w", err)
+	}
+	for _, msg := range log.Messages {
+		names := make([]string, len(msg.Files))
+		for i, f := range msg.Files {
+			names[i] = f.Name
+		}
+		row := []string{
+			formatLocalTime(msg.TimestampUnix, loc, time.RFC3339),
+			msg.TimestampUnix,
+			msg.ThreadTimestampUnix,
+			strconv.FormatBool(msg.IsReply),
+			csvCell(msg.UserID),
+			csvCell(msg.UserName),
+			csvCell(msg.PostType),
+			csvCell(slackEntities.Replace(msg.Text)),
+			csvCell(strings.Join(names, "; ")),
+		}
+		if err := cw.Write(row); err != nil {
+			return fmt.Errorf("failed to write CSV: %w", err)
+		}
+	}
+	cw.Flush()
+	if err := cw.Error(); err != nil {
+		return fmt.Errorf("failed to write CSV: %w", err)
+	}
+	return nil
+}
+
+// csvCell guards a cell against formula injection: spreadsheets evaluate
+// cells starting with '=', '+', '-', '@', a tab, or a carriage return.
+func csvCell(s string) string {
+	if s != "" && strings.ContainsRune("=+-@\t\r", rune(s[0])) {
+		return "'" + s
+	}
+	return s
+}
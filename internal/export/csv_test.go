@@ -0,0 +1,33 @@
+package export
+
+import (
+	"bytes"
+	"testing"
+	"time"
+)
+
+func TestWriteCSV(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteCSV(&buf, goldenLog(), time.FixedZone("JST", 9*60*60)); err != nil {
+		t.Fatalf("WriteCSV() returned an unexpected error: %v", err)
+	}
+	checkGolden(t, "export.csv.golden", buf.Bytes())
+}
+
+func TestCSVCell(t *testing.T) {
+	for in, want := range map[string]string{
+		"":            "",
+		"plain":       "plain",
+		"=1+1":        "'=1+1",
+		"+1":          "'+1",
+		"-1":          "'-1",
+		"@SUM(A1)":    "'@SUM(A1)",
+		"a=b":         "a=b",
+		"\tindented":  "'\tindented",
+		"U01-example": "U01-example",
+	} {
+		if got := csvCell(in); got != want {
+			t.Errorf("csvCell(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
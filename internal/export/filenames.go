@@ -0,0 +1,57 @@
+package export
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// ChannelFileName returns a file name for the log of a channel: its name
+// without '#', with characters other than letters, digits, '-', and '_'
+// replaced by '_'. Different channels can have the same file name; use
+// ChannelFileNames to name the logs of several channels.
+func ChannelFileName(channelName string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) || unicode.IsMark(r) || r == '-' || r == '_' {
+			return r
+		}
+		return '_'
+	}, strings.TrimPrefix(channelName, "#"))
+}
+
+// ChannelFileNames returns unique file names for the logs of channels, in the
+// order of channelNames. Channels whose ChannelFileName is shared with another
+// channel, ignoring case, get a short hash of their name appended, so that the
+// name of a channel only depends on the set of channels exported.
+func ChannelFileNames(channelNames []string) []string {
+	names := make([]string, len(channelNames))
+	count := make(map[string]int)
+	for i, channelName := range channelNames {
+		names[i] = ChannelFileName(channelName)
+		count[strings.ToLower(names[i])]++
+	}
+
+	taken := make(map[string]bool)
+	for _, name := range names {
+		if count[strings.ToLower(name)] == 1 {
+			taken[strings.ToLower(name)] = true
+		}
+	}
+	for i, channelName := range channelNames {
+		if count[strings.ToLower(names[i])] == 1 {
+			continue
+		}
+		sum := sha256.Sum256([]byte(channelName))
+		name := names[i] + "-" + hex.EncodeToString(sum[:4])
+		for n := 2; taken[strings.ToLower(name)]; n++ {
+			// The same channel selected twice, or an unlikely clash with the
+			// name of another channel.
+			name = fmt.Sprintf("%s-%s-%d", names[i], hex.EncodeToString(sum[:4]), n)
+		}
+		taken[strings.ToLower(name)] = true
+		names[i] = name
+	}
+	return names
+}
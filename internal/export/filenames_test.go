@@ -0,0 +1,50 @@
+package export
+
+import (
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestChannelFileName(t *testing.T) {
+	tests := map[string]string{
+		"#general":   "general",
+		"proj-x_2":   "proj-x_2",
+		"#dev.ops":   "dev_ops",
+		"#開発":        "開発",
+		"#équipe":    "équipe",
+		"#../../etc": "______etc",
+		"#a/b\\c":    "a_b_c",
+	}
+	for channelName, want := range tests {
+		if got := ChannelFileName(channelName); got != want {
+			t.Errorf("ChannelFileName(%q) = %q, want %q", channelName, got, want)
+		}
+	}
+}
+
+func TestChannelFileNames(t *testing.T) {
+	channels := []string{"#開発", "#営業", "#開発!", "#開発?", "#General", "#general", "#random"}
+	got := ChannelFileNames(channels)
+	want := []string{"開発", "営業", "開発_-", "開発_-", "General-", "general-", "random"}
+	seen := make(map[string]bool)
+	for i, name := range got {
+		if !strings.HasPrefix(name, want[i]) {
+			t.Errorf("ChannelFileNames()[%d] = %q, want a name starting with %q", i, name, want[i])
+		}
+		if seen[name] {
+			t.Errorf("ChannelFileNames() returned %q twice: %q", name, got)
+		}
+		seen[name] = true
+	}
+
+	// The names do not depend on the order of the channels.
+	reversed := slices.Clone(channels)
+	slices.Reverse(reversed)
+	gotReversed := ChannelFileNames(reversed)
+	slices.Reverse(gotReversed)
+	if !reflect.DeepEqual(gotReversed, got) {
+		t.Errorf("ChannelFileNames() depends on the order of the channels: %q and %q", got, gotReversed)
+	}
+}
@@ -0,0 +1,51 @@
+package export
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+var update = flag.Bool("update", false, "update the golden files in testdata")
+
+// checkGolden compares got with the golden file testdata/name, rewriting the
+// file instead when the tests run with -update.
+func checkGolden(t *testing.T, name string, got []byte) {
+	t.Helper()
+	path := filepath.Join("testdata", name)
+	if *update {
+		if err := os.WriteFile(path, got, 0644); err != nil {
+			t.Fatalf("Failed to update %s: %v", path, err)
+		}
+		return
+	}
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Failed to read %s (run the tests with -update to create it): %v", path, err)
+	}
+	if string(got) != string(want) {
+		t.Errorf("Output does not match %s (run the tests with -update to accept it)\ngot:\n%s\nwant:\n%s", path, got, want)
+	}
+}
+
+// goldenLog returns a log exercising the escaping and thread handling of the
+// text-based output formats.
+func goldenLog() *ExportedLog {
+	return &ExportedLog{
+		ExportTimestamp: "2023-11-20T00:00:00Z",
+		ChannelName:     "#support",
+		Messages: []ExportedMessage{
+			{UserID: "U00", UserName: "carol", PostType: "user", TimestampUnix: "1699990000.000000", Text: "late reply to an older thread", ThreadTimestampUnix: "1699900000.000000", IsReply: true},
+			{UserID: "U01", UserName: "alice_w", PostType: "user", TimestampUnix: "1700000000.000100", ThreadTimestampUnix: "1700000000.000100",
+				Text:  "*Outage*: see <https://status.example.com/a_(b)|status page> & ping <!here>\n&gt; quoted line\n# not a heading, a [link] or 2*3*4",
+				Files: []ExportedFile{{Name: "error log.txt", Mimetype: "text/plain", Size: 2048, LocalPath: filepath.Join("out", "files", "F01_error log.txt")}}},
+			{UserID: "U02", UserName: "bob", PostType: "user", TimestampUnix: "1700000100.000200", ThreadTimestampUnix: "1700000000.000100", IsReply: true,
+				Text: "Fixed with `sed -e 's/a/b/'`:\n```\nline 1, \"quoted\"\n```"},
+			{UserID: "U01", UserName: "alice_w", PostType: "user", TimestampUnix: "1700000150.000000", Text: "thanks!", ThreadTimestampUnix: "1700000000.000100", IsReply: true},
+			{UserID: "U03", UserName: "dave", PostType: "user", TimestampUnix: "1700000200.000300", Text: "=HYPERLINK(\"http://evil.example\")"},
+			{UserID: "B01", UserName: "deploy-bot", PostType: "bot", TimestampUnix: "1700100000.000000", Text: "deployed v1.2, see _release notes_",
+				Files: []ExportedFile{{Name: "notes.md", Permalink: "https://example.slack.com/files/F02"}, {Name: "diff.patch"}}},
+		},
+	}
+}
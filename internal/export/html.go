@@ -0,0 +1,227 @@
+package export
+
+import (
+	"fmt"
+	"html/template"
+	"io"
+	"net/url"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/nlink-jp/scat/internal/util"
+)
+
+// RenderOptions controls how WriteHTML and WriteMarkdown render a log.
+type RenderOptions struct {
+	// Location is the time zone of the displayed timestamps. Nil means UTC.
+	Location *time.Location
+
+	// BaseDir is the directory the output file is written to. Links to
+	// downloaded files are made relative to it, so that the output and
+	// the files can be moved together.
+	BaseDir string
+}
+
+// htmlDay is the messages of one day in a transcript.
+type htmlDay struct {
+	Date     string
+	Messages []*htmlMessage
+}
+
+// htmlMessage is a message of a transcript, with the replies to its thread.
+type htmlMessage struct {
+	ID       string
+	Time     string
+	DateTime string
+	UserName string
+	Bot      bool
+	Text     template.HTML
+	Files    []htmlFile
+	Replies  []*htmlMessage
+
+	// ThreadTime is set on replies whose thread parent is not in the log.
+	ThreadTime string
+}
+
+// htmlFile is an attachment of a message in a transcript.
+type htmlFile struct {
+	Name  string
+	Href  string
+	Thumb bool
+	Size  string
+}
+
+// WriteHTML writes log as a self-contained HTML transcript: messages are
+// grouped by day, replies are collapsed under their thread parent, mrkdwn is
+// rendered, and attachments link to their downloaded copies, with thumbnails
+// for images.
+func WriteHTML(w io.Writer, log *ExportedLog, opts RenderOptions) error {
+	loc := opts.Location
+	if loc == nil {
+		loc = time.UTC
+	}
+
+	parents := make(map[string]*htmlMessage)
+	var days []*htmlDay
+	for _, msg := range log.Messages {
+		m := newHTMLMessage(msg, loc, opts.BaseDir)
+		if msg.IsReply {
+			if parent, ok := parents[msg.ThreadTimestampUnix]; ok {
+				parent.Replies = append(parent.Replies, m)
+				continue
+			}
+			m.ThreadTime = formatLocalTime(msg.ThreadTimestampUnix, loc, "2006-01-02 15:04")
+		} else if msg.ThreadTimestampUnix != "" {
+			parents[msg.TimestampUnix] = m
+		}
+
+		date := formatLocalTime(msg.TimestampUnix, loc, "Monday, January 2, 2006")
+		if len(days) == 0 || days[len(days)-1].Date != date {
+			days = append(days, &htmlDay{Date: date})
+		}
+		day := days[len(days)-1]
+		day.Messages = append(day.Messages, m)
+	}
+
+	data := struct {
+		ChannelName     string
+		ExportTimestamp string
+		TimeZone        string
+		MessageCount    int
+		Days            []*htmlDay
+	}{
+		ChannelName:     log.ChannelName,
+		ExportTimestamp: log.ExportTimestamp,
+		TimeZone:        loc.String(),
+		MessageCount:    len(log.Messages),
+		Days:            days,
+	}
+	if err := htmlTemplate.Execute(w, data); err != nil {
+		return fmt.Errorf("failed to render HTML: %w", err)
+	}
+	return nil
+}
+
+// newHTMLMessage prepares a message for the transcript.
+func newHTMLMessage(msg ExportedMessage, loc *time.Location, baseDir string) *htmlMessage {
+	m := &htmlMessage{
+		ID:       "m" + strings.ReplaceAll(msg.TimestampUnix, ".", "-"),
+		Time:     formatLocalTime(msg.TimestampUnix, loc, "15:04:05"),
+		DateTime: formatLocalTime(msg.TimestampUnix, loc, time.RFC3339),
+		UserName: msg.UserName,
+		Bot:      msg.PostType == "bot",
+		Text:     template.HTML(RenderMrkdwn(msg.Text)),
+	}
+	if m.UserName == "" {
+		m.UserName = msg.UserID
+	}
+	for _, f := range msg.Files {
+		hf := htmlFile{Name: f.Name}
+		if f.Size > 0 {
+			hf.Size = util.FormatBytes(f.Size)
+		}
+		switch {
+		case f.LocalPath != "":
+			hf.Href = fileHref(f.LocalPath, baseDir)
+			hf.Thumb = strings.HasPrefix(f.Mimetype, "image/")
+		case f.Permalink != "":
+			hf.Href = f.Permalink
+		}
+		m.Files = append(m.Files, hf)
+	}
+	return m
+}
+
+// fileHref returns a URL reference to a local file, relative to baseDir if possible.
+func fileHref(localPath, baseDir string) string {
+	p := localPath
+	if abs, err := filepath.Abs(localPath); err == nil {
+		if absBase, err := filepath.Abs(baseDir); err == nil {
+			if rel, err := filepath.Rel(absBase, abs); err == nil {
+				p = rel
+			} else {
+				p = abs
+			}
+		}
+	}
+	u := url.URL{Path: filepath.ToSlash(p)}
+	return u.String()
+}
+
+// formatLocalTime formats a Slack timestamp in loc, or returns ts unchanged if
+// it cannot be parsed.
+func formatLocalTime(ts string, loc *time.Location, layout string) string {
+	t, ok := parseTS(ts)
+	if !ok {
+		return ts
+	}
+	return t.In(loc).Format(layout)
+}
+
+var htmlTemplate = template.Must(template.New("transcript").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>{{.ChannelName}} – Slack transcript</title>
+<style>
+body { font-family: -apple-system, "Segoe UI", Helvetica, Arial, sans-serif; margin: 0 auto; max-width: 56rem; padding: 1rem 2rem; color: #1d1c1d; line-height: 1.45; }
+header { border-bottom: 1px solid #ddd; margin-bottom: 1rem; }
+header p { color: #616061; margin: .25rem 0 1rem; }
+h2 { font-size: .9rem; text-align: center; color: #616061; border-bottom: 1px solid #eee; padding-bottom: .25rem; margin: 1.5rem 0 .5rem; }
+.msg { padding: .35rem 0; }
+.meta { font-size: .85rem; }
+.user { font-weight: bold; }
+.bot { font-size: .7rem; background: #eee; border-radius: 3px; padding: 0 .25rem; margin-left: .25rem; }
+time, .thread-ref { color: #616061; margin-left: .5rem; }
+.text { word-wrap: break-word; }
+pre { background: #f8f8f8; border: 1px solid #ddd; border-radius: 4px; padding: .5rem; white-space: pre-wrap; }
+code { background: #f8f8f8; border: 1px solid #eee; border-radius: 3px; padding: 0 .2rem; color: #c01343; }
+pre code { border: 0; padding: 0; color: inherit; }
+blockquote { border-left: 4px solid #ddd; margin: .25rem 0; padding-left: .75rem; }
+.files { list-style: none; padding: 0; margin: .25rem 0; }
+.files li { margin: .25rem 0; }
+.thumb { display: block; max-width: 360px; max-height: 240px; border: 1px solid #ddd; border-radius: 4px; }
+.size { color: #616061; font-size: .85rem; }
+details { margin: .25rem 0 .25rem 1rem; border-left: 2px solid #ddd; padding-left: .75rem; }
+summary { cursor: pointer; color: #1264a3; font-size: .85rem; }
+</style>
+</head>
+<body>
+<header>
+<h1>{{.ChannelName}}</h1>
+<p>{{.MessageCount}} messages · exported {{.ExportTimestamp}} · times in {{.TimeZone}}</p>
+</header>
+<main>
+{{- range .Days}}
+<section>
+<h2>{{.Date}}</h2>
+{{- range .Messages}}
+{{template "message" .}}
+{{- if .Replies}}
+<details>
+<summary>{{len .Replies}} {{if eq (len .Replies) 1}}reply{{else}}replies{{end}}</summary>
+{{- range .Replies}}
+{{template "message" .}}
+{{- end}}
+</details>
+{{- end}}
+{{- end}}
+</section>
+{{- end}}
+</main>
+</body>
+</html>
+{{define "message"}}<article class="msg" id="{{.ID}}">
+<div class="meta"><span class="user">{{.UserName}}</span>{{if .Bot}}<span class="bot">BOT</span>{{end}}<time datetime="{{.DateTime}}">{{.Time}}</time>{{if .ThreadTime}}<span class="thread-ref">replied to a thread from {{.ThreadTime}}</span>{{end}}</div>
+<div class="text">{{.Text}}</div>
+{{- if .Files}}
+<ul class="files">
+{{- range .Files}}
+<li>{{if .Href}}<a href="{{.Href}}">{{if .Thumb}}<img class="thumb" src="{{.Href}}" alt="{{.Name}}" loading="lazy">{{end}}{{.Name}}</a>{{else}}{{.Name}}{{end}}{{if .Size}} <span class="size">({{.Size}})</span>{{end}}</li>
+{{- end}}
+</ul>
+{{- end}}
+</article>{{end}}
+`))
@@ -0,0 +1,64 @@
+package export
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWriteHTML(t *testing.T) {
+	baseDir := t.TempDir()
+	log := &ExportedLog{
+		ExportTimestamp: "2023-11-20T00:00:00Z",
+		ChannelName:     "#general",
+		Messages: []ExportedMessage{
+			{UserID: "U00", UserName: "carol", PostType: "user", TimestampUnix: "1699990000.000000", Text: "late reply", ThreadTimestampUnix: "1699900000.000000", IsReply: true},
+			{UserID: "U01", UserName: "alice", PostType: "user", TimestampUnix: "1700000000.000100", Text: "*parent* <script>", ThreadTimestampUnix: "1700000000.000100",
+				Files: []ExportedFile{{Name: "chart.png", Mimetype: "image/png", Size: 2048, LocalPath: filepath.Join(baseDir, "files", "F01_chart.png")}}},
+			{UserID: "U02", UserName: "bob", PostType: "user", TimestampUnix: "1700000100.000200", Text: "first reply", ThreadTimestampUnix: "1700000000.000100", IsReply: true},
+			{UserID: "B01", UserName: "deploy-bot", PostType: "bot", TimestampUnix: "1700100000.000000", Text: "deployed",
+				Files: []ExportedFile{{Name: "log.txt", Mimetype: "text/plain", Permalink: "https://example.slack.com/files/F02"}}},
+		},
+	}
+
+	var buf bytes.Buffer
+	err := WriteHTML(&buf, log, RenderOptions{Location: time.FixedZone("JST", 9*60*60), BaseDir: baseDir})
+	if err != nil {
+		t.Fatalf("WriteHTML() returned an unexpected error: %v", err)
+	}
+	out := buf.String()
+
+	for _, want := range []string{
+		"<h1>#general</h1>",
+		"4 messages",
+		"times in JST",
+		// Days and times are shown in the given time zone.
+		"<h2>Wednesday, November 15, 2023</h2>",
+		`<time datetime="2023-11-15T07:13:20&#43;09:00">07:13:20</time>`,
+		// mrkdwn is rendered and markup escaped.
+		"<strong>parent</strong> &lt;script&gt;",
+		// Replies are collapsed under their parent.
+		"<summary>1 reply</summary>",
+		// A reply whose parent is not in the log refers to its thread.
+		"replied to a thread from 2023-11-14 03:26",
+		// Images link to their local copy, relative to the HTML file, with a thumbnail.
+		`<a href="files/F01_chart.png"><img class="thumb" src="files/F01_chart.png" alt="chart.png" loading="lazy">chart.png</a> <span class="size">(2.0 KiB)</span>`,
+		// Files that were not downloaded link to Slack.
+		`<a href="https://example.slack.com/files/F02">log.txt</a>`,
+		`<span class="bot">BOT</span>`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("Expected the transcript to contain %q, got:\n%s", want, out)
+		}
+	}
+
+	// The reply is rendered inside the collapsed thread, after its parent.
+	parent := strings.Index(out, "<strong>parent</strong>")
+	details := strings.Index(out, "<details>")
+	reply := strings.Index(out, "first reply")
+	if !(parent < details && details < reply) {
+		t.Errorf("Expected the reply inside <details> after its parent, got:\n%s", out)
+	}
+}
@@ -0,0 +1,128 @@
+package export
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/nlink-jp/scat/internal/util"
+)
+
+// WriteMarkdown writes log as a Markdown document for pasting into a wiki:
+// a heading per day, replies as block quotes under their thread parent,
+// mrkdwn converted to Markdown, and attachments as links to their downloaded
+// copies or, failing that, to Slack.
+func WriteMarkdown(w io.Writer, log *ExportedLog, opts RenderOptions) error {
+	loc := opts.Location
+	if loc == nil {
+		loc = time.UTC
+	}
+
+	// Replies follow their parent, whatever their position in the log.
+	replies := make(map[string][]ExportedMessage)
+	parents := make(map[string]bool)
+	for _, msg := range log.Messages {
+		if !msg.IsReply && msg.ThreadTimestampUnix != "" {
+			parents[msg.TimestampUnix] = true
+		}
+	}
+	for _, msg := range log.Messages {
+		if msg.IsReply && parents[msg.ThreadTimestampUnix] {
+			replies[msg.ThreadTimestampUnix] = append(replies[msg.ThreadTimestampUnix], msg)
+		}
+	}
+
+	bw := bufio.NewWriter(w)
+	fmt.Fprintf(bw, "# %s\n\n", escapeMarkdown(log.ChannelName))
+	fmt.Fprintf(bw, "%d messages · exported %s · times in %s\n", len(log.Messages), escapeMarkdown(log.ExportTimestamp), escapeMarkdown(loc.String()))
+
+	date := ""
+	for _, msg := range log.Messages {
+		if msg.IsReply && parents[msg.ThreadTimestampUnix] {
+			continue
+		}
+		if d := formatLocalTime(msg.TimestampUnix, loc, "Monday, January 2, 2006"); d != date {
+			date = d
+			fmt.Fprintf(bw, "\n## %s\n", escapeMarkdown(date))
+		}
+
+		block := markdownMessage(msg, loc, opts.BaseDir)
+		if msg.IsReply {
+			// The parent of the thread is not in the log.
+			ref := formatLocalTime(msg.ThreadTimestampUnix, loc, "2006-01-02 15:04")
+			block = fmt.Sprintf("_Replied to a thread from %s_\n\n%s", escapeMarkdown(ref), quoteMarkdown(block))
+		}
+		fmt.Fprintf(bw, "\n%s\n", block)
+
+		// The replies of a thread form one block quote.
+		for i, reply := range replies[msg.TimestampUnix] {
+			sep := ">"
+			if i == 0 {
+				sep = ""
+			}
+			fmt.Fprintf(bw, "%s\n%s\n", sep, quoteMarkdown(markdownMessage(reply, loc, opts.BaseDir)))
+		}
+	}
+
+	if err := bw.Flush(); err != nil {
+		return fmt.Errorf("failed to write Markdown: %w", err)
+	}
+	return nil
+}
+
+// markdownMessage renders a message: a line with the user and time, the text,
+// and a list of attachments.
+func markdownMessage(msg ExportedMessage, loc *time.Location, baseDir string) string {
+	name := msg.UserName
+	if name == "" {
+		name = msg.UserID
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "**%s**", escapeMarkdown(name))
+	if msg.PostType == "bot" {
+		b.WriteString(" (bot)")
+	}
+	fmt.Fprintf(&b, " · %s", formatLocalTime(msg.TimestampUnix, loc, "15:04:05"))
+	if text := strings.Trim(mrkdwnToMarkdown(msg.Text), "\n"); strings.TrimSpace(text) != "" {
+		b.WriteString("  \n")
+		b.WriteString(text)
+	}
+
+	if len(msg.Files) > 0 {
+		b.WriteString("\n")
+	}
+	for _, f := range msg.Files {
+		b.WriteString("\n- ")
+		href := ""
+		switch {
+		case f.LocalPath != "":
+			href = fileHref(f.LocalPath, baseDir)
+		case f.Permalink != "":
+			href = f.Permalink
+		}
+		if href != "" {
+			fmt.Fprintf(&b, "[%s](%s)", escapeMarkdown(f.Name), markdownURL(href))
+		} else {
+			b.WriteString(escapeMarkdown(f.Name))
+		}
+		if f.Size > 0 {
+			fmt.Fprintf(&b, " (%s)", util.FormatBytes(f.Size))
+		}
+	}
+	return b.String()
+}
+
+// quoteMarkdown turns a block of Markdown into a block quote.
+func quoteMarkdown(block string) string {
+	lines := strings.Split(block, "\n")
+	for i, line := range lines {
+		if line == "" {
+			lines[i] = ">"
+		} else {
+			lines[i] = "> " + line
+		}
+	}
+	return strings.Join(lines, "\n")
+}
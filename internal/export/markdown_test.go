@@ -0,0 +1,40 @@
+package export
+
+import (
+	"bytes"
+	"testing"
+	"time"
+)
+
+func TestWriteMarkdown(t *testing.T) {
+	var buf bytes.Buffer
+	err := WriteMarkdown(&buf, goldenLog(), RenderOptions{Location: time.FixedZone("JST", 9*60*60), BaseDir: "out"})
+	if err != nil {
+		t.Fatalf("WriteMarkdown() returned an unexpected error: %v", err)
+	}
+	checkGolden(t, "export.md.golden", buf.Bytes())
+}
+
+func TestMrkdwnToMarkdown(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{"styles", "*bold* _italic_ ~strike~", "**bold** _italic_ ~~strike~~"},
+		{"markdown characters are escaped", "a_b * [x](y) <b>", `a\_b \* \[x\](y) \<b\>`},
+		{"line start", "# title\n- item", "\\# title  \n\\- item"},
+		{"link", "<https://example.com/a b|a [label]>", "[a \\[label\\]](https://example.com/a%20b)"},
+		{"bare link", "<https://example.com>", "<https://example.com>"},
+		{"unsafe link", "<javascript:alert(1)|x>", `\<javascript:alert(1)\|x\>`},
+		{"quote", "&gt; quoted\nnext", "> quoted\n\nnext"},
+		{"code", "`a*b*` and\n```\n*x*\n```", "`a*b*` and  \n\n```\n*x*\n```\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := mrkdwnToMarkdown(tt.in); got != tt.want {
+				t.Errorf("mrkdwnToMarkdown(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,27 @@
+package export
+
+import "sort"
+
+// MergeMessages merges added into existing, replacing messages with the same
+// timestamp by their newer copy, and returns the result sorted by timestamp.
+// Messages without a timestamp are always kept.
+func MergeMessages(existing, added []ExportedMessage) []ExportedMessage {
+	index := make(map[string]int, len(existing)+len(added))
+	merged := make([]ExportedMessage, 0, len(existing)+len(added))
+	for _, msgs := range [][]ExportedMessage{existing, added} {
+		for _, msg := range msgs {
+			if i, ok := index[msg.TimestampUnix]; ok {
+				merged[i] = msg
+				continue
+			}
+			if msg.TimestampUnix != "" {
+				index[msg.TimestampUnix] = len(merged)
+			}
+			merged = append(merged, msg)
+		}
+	}
+	sort.SliceStable(merged, func(i, j int) bool {
+		return merged[i].TimestampUnix < merged[j].TimestampUnix
+	})
+	return merged
+}
@@ -0,0 +1,271 @@
+package export
+
+import (
+	"html"
+	"strings"
+)
+
+// slackEntities reverses the escaping of '&', '<', and '>' in Slack message text.
+var slackEntities = strings.NewReplacer("&amp;", "&", "&lt;", "<", "&gt;", ">")
+
+// Text styles are marked with private use characters while the text is
+// parsed, and replaced by the markup of the output format after escaping.
+const (
+	styleMarks = "\ue000\ue001\ue002\ue003\ue004\ue005"
+	boldOpen   = "\ue000"
+	boldClose  = "\ue001"
+	emOpen     = "\ue002"
+	emClose    = "\ue003"
+	delOpen    = "\ue004"
+	delClose   = "\ue005"
+)
+
+// mrkdwnRenderer describes how the elements of Slack mrkdwn are written in an
+// output format.
+type mrkdwnRenderer struct {
+	escape    func(s string) string                    // Escapes plain text.
+	code      func(s string) string                    // Writes an inline code span.
+	codeBlock func(s string) string                    // Writes a code block.
+	link      func(url, label string) string           // Writes a link; label is empty for a bare URL.
+	group     func(quoted bool, lines []string) string // Joins lines, quoted or not.
+	groupSep  string                                   // Separates groups of quoted and other lines.
+	styles    *strings.Replacer                        // Replaces style marks with markup.
+}
+
+// htmlMrkdwn renders mrkdwn as HTML.
+var htmlMrkdwn = &mrkdwnRenderer{
+	escape: html.EscapeString,
+	code:   func(s string) string { return "<code>" + html.EscapeString(s) + "</code>" },
+	codeBlock: func(s string) string {
+		return "<pre><code>" + html.EscapeString(s) + "</code></pre>"
+	},
+	link: func(url, label string) string {
+		if label == "" {
+			label = strings.TrimPrefix(url, "mailto:")
+		}
+		return `<a href="` + html.EscapeString(url) + `">` + html.EscapeString(label) + "</a>"
+	},
+	group: func(quoted bool, lines []string) string {
+		if quoted {
+			return "<blockquote>" + strings.Join(lines, "<br>") + "</blockquote>"
+		}
+		return strings.Join(lines, "<br>")
+	},
+	styles: strings.NewReplacer(boldOpen, "<strong>", boldClose, "</strong>", emOpen, "<em>", emClose, "</em>", delOpen, "<del>", delClose, "</del>"),
+}
+
+// markdownMrkdwn renders mrkdwn as Markdown.
+var markdownMrkdwn = &mrkdwnRenderer{
+	escape: escapeMarkdown,
+	code:   func(s string) string { return "`" + s + "`" },
+	codeBlock: func(s string) string {
+		return "\n```\n" + s + "\n```\n"
+	},
+	link: func(url, label string) string {
+		if label == "" {
+			return "<" + markdownURL(url) + ">"
+		}
+		return "[" + escapeMarkdown(label) + "](" + markdownURL(url) + ")"
+	},
+	group: func(quoted bool, lines []string) string {
+		if quoted {
+			return "> " + strings.Join(lines, "  \n> ")
+		}
+		return strings.Join(lines, "  \n")
+	},
+	groupSep: "\n\n",
+	styles:   strings.NewReplacer(boldOpen, "**", boldClose, "**", emOpen, "_", emClose, "_", delOpen, "~~", delClose, "~~"),
+}
+
+// RenderMrkdwn converts Slack mrkdwn message text to HTML. It handles code
+// blocks, inline code, bold, italic, strikethrough, block quotes, and links,
+// and escapes everything else, so its result is safe to embed in a page.
+func RenderMrkdwn(text string) string {
+	return htmlMrkdwn.render(text)
+}
+
+// mrkdwnToMarkdown converts Slack mrkdwn message text to Markdown, escaping
+// characters that Markdown would otherwise interpret.
+func mrkdwnToMarkdown(text string) string {
+	return markdownMrkdwn.render(text)
+}
+
+// render converts mrkdwn text to the output format.
+func (r *mrkdwnRenderer) render(text string) string {
+	text = strings.Map(func(c rune) rune {
+		if strings.ContainsRune(styleMarks, c) {
+			return -1
+		}
+		return c
+	}, text)
+
+	var b strings.Builder
+	// Every odd part of the text split at ``` is the content of a code block.
+	for i, part := range strings.Split(text, "```") {
+		if i%2 == 1 {
+			b.WriteString(r.codeBlock(slackEntities.Replace(strings.Trim(part, "\n"))))
+			continue
+		}
+		b.WriteString(r.renderLines(part))
+	}
+	return b.String()
+}
+
+// renderLines renders text outside code blocks, grouping consecutive lines
+// starting with '>' into a block quote.
+func (r *mrkdwnRenderer) renderLines(text string) string {
+	var groups []string
+	var lines []string
+	inQuote := false
+	for i, line := range strings.Split(text, "\n") {
+		quoted, ok := cutQuote(line)
+		if i > 0 && ok != inQuote {
+			groups = append(groups, r.group(inQuote, lines))
+			lines = nil
+		}
+		inQuote = ok
+		if ok {
+			line = quoted
+		}
+		lines = append(lines, r.renderInline(line))
+	}
+	groups = append(groups, r.group(inQuote, lines))
+	return strings.Join(groups, r.groupSep)
+}
+
+// cutQuote returns line without its block quote marker, if it has one.
+func cutQuote(line string) (string, bool) {
+	for _, marker := range []string{"&gt;", ">"} {
+		if rest, ok := strings.CutPrefix(line, marker); ok {
+			return strings.TrimPrefix(rest, " "), true
+		}
+	}
+	return line, false
+}
+
+// renderInline renders a line of text, which may contain inline code spans
+// and links.
+func (r *mrkdwnRenderer) renderInline(line string) string {
+	var b strings.Builder
+	spans := strings.Count(line, "`") / 2
+	for i, part := range strings.Split(line, "`") {
+		switch {
+		case i%2 == 0:
+			b.WriteString(r.renderText(part))
+		case (i+1)/2 <= spans:
+			b.WriteString(r.code(slackEntities.Replace(part)))
+		default:
+			// An unpaired backtick is kept as is.
+			b.WriteString(r.escape("`"))
+			b.WriteString(r.renderText(part))
+		}
+	}
+	return b.String()
+}
+
+// renderText renders text with links and text styles.
+func (r *mrkdwnRenderer) renderText(text string) string {
+	var b strings.Builder
+	for text != "" {
+		start := strings.IndexByte(text, '<')
+		end := -1
+		if start >= 0 {
+			end = strings.IndexByte(text[start:], '>')
+		}
+		if start < 0 || end < 0 {
+			b.WriteString(r.style(text))
+			break
+		}
+		b.WriteString(r.style(text[:start]))
+		b.WriteString(r.renderLink(text[start+1 : start+end]))
+		text = text[start+end+1:]
+	}
+	return b.String()
+}
+
+// renderLink renders the content of a <...> token of Slack message text: a
+// URL, a channel or user mention, or a special mention like !here.
+func (r *mrkdwnRenderer) renderLink(token string) string {
+	target, label, hasLabel := strings.Cut(token, "|")
+	label = slackEntities.Replace(label)
+	switch {
+	case strings.HasPrefix(target, "http://"), strings.HasPrefix(target, "https://"), strings.HasPrefix(target, "mailto:"):
+		return r.link(slackEntities.Replace(target), label)
+	case strings.HasPrefix(target, "#"):
+		if !hasLabel {
+			label = target[1:]
+		}
+		return r.escape("#" + label)
+	case strings.HasPrefix(target, "@"):
+		if !hasLabel {
+			label = target[1:]
+		}
+		return r.escape("@" + label)
+	case strings.HasPrefix(target, "!"):
+		if !hasLabel {
+			label, _, _ = strings.Cut(target[1:], "^")
+		}
+		return r.escape("@" + strings.TrimPrefix(label, "@"))
+	}
+	return r.escape(slackEntities.Replace("<" + token + ">"))
+}
+
+// style escapes plain text and renders *bold*, _italic_, and ~strike~.
+func (r *mrkdwnRenderer) style(text string) string {
+	s := slackEntities.Replace(text)
+	s = markDelimited(s, '*', boldOpen, boldClose)
+	s = markDelimited(s, '_', emOpen, emClose)
+	s = markDelimited(s, '~', delOpen, delClose)
+	return r.styles.Replace(r.escape(s))
+}
+
+// markDelimited replaces a pair of delim around text with the open and close
+// marks. As in Slack, the opening delimiter must not follow and the closing
+// delimiter must not precede a letter or digit, and the enclosed text must not
+// start or end with a space.
+func markDelimited(s string, delim byte, open, close string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); {
+		if s[i] == delim && (i == 0 || !isWordByte(s[i-1])) {
+			if j := strings.IndexByte(s[i+1:], delim); j > 0 {
+				end := i + 1 + j
+				inner := s[i+1 : end]
+				if inner[0] != ' ' && inner[len(inner)-1] != ' ' && (end+1 == len(s) || !isWordByte(s[end+1])) {
+					b.WriteString(open + inner + close)
+					i = end + 1
+					continue
+				}
+			}
+		}
+		b.WriteByte(s[i])
+		i++
+	}
+	return b.String()
+}
+
+// isWordByte reports whether c is an ASCII letter or digit.
+func isWordByte(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9'
+}
+
+// markdownSpecial escapes the characters that have a meaning anywhere in a
+// line of Markdown.
+var markdownSpecial = strings.NewReplacer(
+	`\`, `\\`, "`", "\\`", "*", `\*`, "_", `\_`, "~", `\~`,
+	"[", `\[`, "]", `\]`, "<", `\<`, ">", `\>`, "|", `\|`,
+)
+
+// escapeMarkdown escapes text so that Markdown shows it literally, including
+// characters that start headings, lists, or quotes at the beginning of a line.
+func escapeMarkdown(s string) string {
+	s = markdownSpecial.Replace(s)
+	if s != "" && strings.ContainsRune("#-+=", rune(s[0])) {
+		s = `\` + s
+	}
+	return s
+}
+
+// markdownURL encodes the characters of a URL that would end a Markdown link.
+func markdownURL(url string) string {
+	return strings.NewReplacer(" ", "%20", "(", "%28", ")", "%29", "<", "%3C", ">", "%3E").Replace(url)
+}
@@ -0,0 +1,35 @@
+package export
+
+import "testing"
+
+func TestRenderMrkdwn(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want string
+	}{
+		{"plain text is escaped", `if a < b && c > d`, `if a &lt; b &amp;&amp; c &gt; d`},
+		{"slack entities", `a &lt;b&gt; &amp; c`, `a &lt;b&gt; &amp; c`},
+		{"markup is escaped", `<script>alert(1)</script>`, `&lt;script&gt;alert(1)&lt;/script&gt;`},
+		{"styles", `*bold* _italic_ ~gone~`, `<strong>bold</strong> <em>italic</em> <del>gone</del>`},
+		{"no styles inside words", `snake_case_name and 2*3*4`, `snake_case_name and 2*3*4`},
+		{"nested styles", `*bold _and italic_*`, `<strong>bold <em>and italic</em></strong>`},
+		{"line breaks", "one\ntwo", `one<br>two`},
+		{"inline code", "run `rm -rf *tmp*` now", `run <code>rm -rf *tmp*</code> now`},
+		{"unpaired backtick", "it`s", "it`s"},
+		{"code block", "see\n```\nx := <-ch\n*y*\n```", "see<br><pre><code>x := &lt;-ch\n*y*</code></pre>"},
+		{"block quote", "&gt; quoted\n&gt; more\nreply", `<blockquote>quoted<br>more</blockquote>reply`},
+		{"link", `<https://example.com/?a=1&amp;b=2>`, `<a href="https://example.com/?a=1&amp;b=2">https://example.com/?a=1&amp;b=2</a>`},
+		{"labeled link", `<https://example.com|the *site*>`, `<a href="https://example.com">the *site*</a>`},
+		{"unsafe link", `<javascript:alert(1)|click>`, `&lt;javascript:alert(1)|click&gt;`},
+		{"channel mention", `<#C123|general>`, `#general`},
+		{"special mention", `<!here>`, `@here`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := RenderMrkdwn(tt.text); got != tt.want {
+				t.Errorf("RenderMrkdwn(%q) =\n  %s\nwant\n  %s", tt.text, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,329 @@
+package export
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/nlink-jp/scat/internal/util"
+)
+
+// SlackExportWriter writes logs in the layout of a Slack workspace export, as
+// understood by export viewers and importers: a directory per channel holding
+// one JSON file of messages per day (UTC), plus channels.json and users.json
+// at the top level. The export is written to a directory or, if its path ends
+// in ".zip", to a zip archive. It is safe for concurrent use.
+type SlackExportWriter struct {
+	mu       sync.Mutex
+	dir      string
+	file     *os.File
+	zw       *zip.Writer
+	channels []slackExportChannel
+	users    map[string]slackExportUser
+}
+
+// slackExportChannel is an entry of channels.json.
+type slackExportChannel struct {
+	ID      string `json:"id"`
+	Name    string `json:"name"`
+	Created int64  `json:"created,omitempty"`
+}
+
+// slackExportUser is an entry of users.json.
+type slackExportUser struct {
+	ID       string `json:"id"`
+	Name     string `json:"name"`
+	RealName string `json:"real_name,omitempty"`
+	Profile  struct {
+		RealName    string `json:"real_name,omitempty"`
+		DisplayName string `json:"display_name,omitempty"`
+	} `json:"profile"`
+}
+
+// slackExportMessage is a message in a per-day file.
+type slackExportMessage struct {
+	Type         string             `json:"type"`
+	Subtype      string             `json:"subtype,omitempty"`
+	ClientMsgID  string             `json:"client_msg_id,omitempty"`
+	User         string             `json:"user,omitempty"`
+	BotID        string             `json:"bot_id,omitempty"`
+	Username     string             `json:"username,omitempty"`
+	Text         string             `json:"text"`
+	TS           string             `json:"ts"`
+	ThreadTS     string             `json:"thread_ts,omitempty"`
+	ParentUserID string             `json:"parent_user_id,omitempty"`
+	ReplyCount   int                `json:"reply_count,omitempty"`
+	ReplyUsers   []string           `json:"reply_users,omitempty"`
+	Replies      []slackExportReply `json:"replies,omitempty"`
+	LatestReply  string             `json:"latest_reply,omitempty"`
+	Files        []slackExportFile  `json:"files,omitempty"`
+	Edited       *slackExportEdit   `json:"edited,omitempty"`
+	Reactions    []ExportedReaction `json:"reactions,omitempty"`
+	Blocks       json.RawMessage    `json:"blocks,omitempty"`
+	Attachments  json.RawMessage    `json:"attachments,omitempty"`
+}
+
+// slackExportFile is a file attached to a message, with Slack's own fields.
+// Fields that only scat adds, such as the path of a downloaded copy, are left
+// out.
+type slackExportFile struct {
+	ID                 string `json:"id"`
+	Created            int64  `json:"created,omitempty"`
+	Name               string `json:"name"`
+	Title              string `json:"title,omitempty"`
+	Mimetype           string `json:"mimetype"`
+	Filetype           string `json:"filetype,omitempty"`
+	PrettyType         string `json:"pretty_type,omitempty"`
+	User               string `json:"user,omitempty"`
+	Mode               string `json:"mode,omitempty"`
+	IsExternal         bool   `json:"is_external"`
+	Size               int64  `json:"size,omitempty"`
+	URLPrivate         string `json:"url_private,omitempty"`
+	URLPrivateDownload string `json:"url_private_download,omitempty"`
+	Permalink          string `json:"permalink,omitempty"`
+}
+
+// slackExportEdit records the last edit of a message.
+type slackExportEdit struct {
+	User string `json:"user"`
+	TS   string `json:"ts"`
+}
+
+// slackExportReply is an entry of the replies of a thread parent.
+type slackExportReply struct {
+	User string `json:"user"`
+	TS   string `json:"ts"`
+}
+
+// NewSlackExportWriter starts a Slack export at path, creating the directory
+// or zip archive. Close must be called to complete it.
+func NewSlackExportWriter(path string) (*SlackExportWriter, error) {
+	w := &SlackExportWriter{users: make(map[string]slackExportUser)}
+	if strings.EqualFold(filepath.Ext(path), ".zip") {
+		f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create export archive: %w", err)
+		}
+		w.file, w.zw = f, zip.NewWriter(f)
+		return w, nil
+	}
+	if err := os.MkdirAll(path, 0700); err != nil {
+		return nil, fmt.Errorf("failed to create export directory %s: %w", path, err)
+	}
+	w.dir = path
+	return w, nil
+}
+
+// AddLog adds the messages of a channel to the export. Each channel should be
+// added once.
+func (w *SlackExportWriter) AddLog(log *ExportedLog) error {
+	// Viewers find the messages of a channel in the directory named after it
+	// in channels.json, so the name is only stripped of path separators.
+	name := strings.NewReplacer("/", "", "\\", "").Replace(strings.TrimPrefix(log.ChannelName, "#"))
+	if name == "" || name == "." || name == ".." {
+		return fmt.Errorf("channel name %q cannot be used in a Slack export", log.ChannelName)
+	}
+	channel := slackExportChannel{ID: log.ChannelID, Name: name}
+	if channel.ID == "" {
+		channel.ID = channel.Name
+	}
+
+	days := make(map[string][]slackExportMessage)
+	for _, msg := range slackExportMessages(log.Messages) {
+		t, ok := parseTS(msg.TS)
+		if !ok {
+			return fmt.Errorf("message in %s has an invalid ts '%s'", log.ChannelName, msg.TS)
+		}
+		if channel.Created == 0 || t.Unix() < channel.Created {
+			channel.Created = t.Unix()
+		}
+		day := t.UTC().Format(time.DateOnly)
+		days[day] = append(days[day], msg)
+	}
+	dayNames := make([]string, 0, len(days))
+	for day := range days {
+		dayNames = append(dayNames, day)
+	}
+	sort.Strings(dayNames)
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	for _, day := range dayNames {
+		if err := w.writeJSON(path.Join(name, day+".json"), days[day]); err != nil {
+			return err
+		}
+	}
+	w.channels = append(w.channels, channel)
+	for _, msg := range log.Messages {
+		if msg.PostType == "user" && msg.UserID != "" {
+			u := slackExportUser{ID: msg.UserID, Name: msg.UserName, RealName: msg.UserName}
+			u.Profile.RealName = msg.UserName
+			u.Profile.DisplayName = msg.UserName
+			w.users[msg.UserID] = u
+		}
+	}
+	return nil
+}
+
+// Close writes channels.json and users.json and completes the export.
+func (w *SlackExportWriter) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	sort.Slice(w.channels, func(i, j int) bool { return w.channels[i].Name < w.channels[j].Name })
+	users := make([]slackExportUser, 0, len(w.users))
+	for _, u := range w.users {
+		users = append(users, u)
+	}
+	sort.Slice(users, func(i, j int) bool { return users[i].ID < users[j].ID })
+
+	err := w.writeJSON("channels.json", w.channels)
+	if err == nil {
+		err = w.writeJSON("users.json", users)
+	}
+	if w.zw != nil {
+		if closeErr := w.zw.Close(); err == nil {
+			err = closeErr
+		}
+		if closeErr := w.file.Close(); err == nil {
+			err = closeErr
+		}
+		w.zw = nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to write export: %w", err)
+	}
+	return nil
+}
+
+// writeJSON writes v as indented JSON to the slash-separated name inside the export.
+func (w *SlackExportWriter) writeJSON(name string, v any) error {
+	data, err := json.MarshalIndent(v, "", "    ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal %s: %w", name, err)
+	}
+	if w.zw != nil {
+		f, err := w.zw.CreateHeader(&zip.FileHeader{Name: name, Method: zip.Deflate, Modified: time.Now()})
+		if err == nil {
+			_, err = f.Write(data)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to add %s to the export archive: %w", name, err)
+		}
+		return nil
+	}
+	p := filepath.Join(w.dir, filepath.FromSlash(name))
+	if err := os.MkdirAll(filepath.Dir(p), 0700); err != nil {
+		return fmt.Errorf("failed to create export directory: %w", err)
+	}
+	if err := util.WriteFileAtomic(p, data); err != nil {
+		return fmt.Errorf("failed to write %s: %w", p, err)
+	}
+	return nil
+}
+
+// slackExportMessages converts exported messages to Slack's message format,
+// restoring the thread summary (reply_count, reply_users, replies) of thread
+// parents and the parent_user_id of replies from the messages of the log.
+// The reply_count and reply_users reported by the provider take precedence,
+// since the log may not hold every reply of a thread.
+func slackExportMessages(msgs []ExportedMessage) []slackExportMessage {
+	parents := make(map[string]ExportedMessage)
+	replies := make(map[string][]ExportedMessage)
+	for _, msg := range msgs {
+		if msg.IsReply {
+			replies[msg.ThreadTimestampUnix] = append(replies[msg.ThreadTimestampUnix], msg)
+		} else if msg.ThreadTimestampUnix != "" {
+			parents[msg.TimestampUnix] = msg
+		}
+	}
+
+	out := make([]slackExportMessage, 0, len(msgs))
+	for _, msg := range msgs {
+		m := slackExportMessage{
+			Type:        "message",
+			Subtype:     msg.Subtype,
+			ClientMsgID: msg.ClientMsgID,
+			Text:        msg.Text,
+			TS:          msg.TimestampUnix,
+			ThreadTS:    msg.ThreadTimestampUnix,
+			Files:       slackExportFiles(msg.Files),
+			Reactions:   msg.Reactions,
+			Blocks:      msg.Blocks,
+			Attachments: msg.Attachments,
+		}
+		if msg.Edited != nil {
+			m.Edited = &slackExportEdit{User: msg.Edited.UserID, TS: msg.Edited.TimestampUnix}
+		}
+		if msg.PostType == "bot" {
+			m.BotID, m.Username = msg.UserID, msg.UserName
+			if m.Subtype == "" {
+				m.Subtype = "bot_message"
+			}
+		} else {
+			m.User = msg.UserID
+		}
+
+		if msg.IsReply {
+			if parent, ok := parents[msg.ThreadTimestampUnix]; ok {
+				m.ParentUserID = parent.UserID
+			}
+		} else if threadReplies := replies[msg.TimestampUnix]; len(threadReplies) > 0 {
+			seen := make(map[string]bool)
+			for _, reply := range threadReplies {
+				m.Replies = append(m.Replies, slackExportReply{User: reply.UserID, TS: reply.TimestampUnix})
+				if !seen[reply.UserID] {
+					seen[reply.UserID] = true
+					m.ReplyUsers = append(m.ReplyUsers, reply.UserID)
+				}
+				if reply.TimestampUnix > m.LatestReply {
+					m.LatestReply = reply.TimestampUnix
+				}
+			}
+			m.ReplyCount = len(threadReplies)
+		}
+		if msg.ReplyCount > 0 {
+			m.ReplyCount = msg.ReplyCount
+		}
+		if len(msg.ReplyUsers) > 0 {
+			m.ReplyUsers = msg.ReplyUsers
+		}
+		out = append(out, m)
+	}
+	sort.SliceStable(out, func(i, j int) bool { return out[i].TS < out[j].TS })
+	return out
+}
+
+// slackExportFiles converts the files of a message to Slack's file format.
+func slackExportFiles(files []ExportedFile) []slackExportFile {
+	if len(files) == 0 {
+		return nil
+	}
+	out := make([]slackExportFile, len(files))
+	for i, f := range files {
+		out[i] = slackExportFile{
+			ID:                 f.ID,
+			Created:            f.Created,
+			Name:               f.Name,
+			Title:              f.Title,
+			Mimetype:           f.Mimetype,
+			Filetype:           f.Filetype,
+			PrettyType:         f.PrettyType,
+			User:               f.UserID,
+			Mode:               f.Mode,
+			IsExternal:         f.IsExternal,
+			Size:               f.Size,
+			URLPrivate:         f.URLPrivate,
+			URLPrivateDownload: f.URLPrivateDownload,
+			Permalink:          f.Permalink,
+		}
+	}
+	return out
+}
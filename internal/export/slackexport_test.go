@@ -0,0 +1,197 @@
+package export
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func testSlackExportLog() *ExportedLog {
+	return &ExportedLog{
+		ChannelName: "#general",
+		ChannelID:   "C01",
+		Messages: []ExportedMessage{
+			{UserID: "U01", UserName: "alice", PostType: "user", TimestampUnix: "1700000000.000100", Text: "parent", ThreadTimestampUnix: "1700000000.000100"},
+			{UserID: "U02", UserName: "bob", PostType: "user", TimestampUnix: "1700000100.000200", Text: "reply", ThreadTimestampUnix: "1700000000.000100", IsReply: true,
+				ClientMsgID: "c0ffee", Edited: &ExportedEdit{UserID: "U02", TimestampUnix: "1700000200.000000"},
+				Reactions: []ExportedReaction{{Name: "eyes", Count: 1, Users: []string{"U01"}}},
+				Blocks:    json.RawMessage(`[{"type":"rich_text","block_id":"b1"}]`)},
+			{UserID: "B01", UserName: "deploy-bot", PostType: "bot", Subtype: "bot_message", TimestampUnix: "1700100000.000000", Text: "deployed",
+				Attachments: json.RawMessage(`[{"fallback":"build #1 passed","color":"good"}]`),
+				Files: []ExportedFile{{ID: "F01", Name: "log.txt", Title: "Deploy log", Mimetype: "text/plain", Filetype: "text", Size: 42, URLPrivate: "https://files.slack.com/F01",
+					Created: 1700100000, UserID: "B01", PrettyType: "Plain Text", Mode: "hosted", URLPrivateDownload: "https://files.slack.com/F01/download",
+					LocalPath: "files/F01_log.txt", SHA256: "9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08"}}},
+		},
+	}
+}
+
+// readSlackExport returns the files of an export directory or zip archive.
+func readSlackExport(t *testing.T, path string) map[string][]byte {
+	t.Helper()
+	files := make(map[string][]byte)
+	if filepath.Ext(path) == ".zip" {
+		zr, err := zip.OpenReader(path)
+		if err != nil {
+			t.Fatalf("Invalid zip archive: %v", err)
+		}
+		defer zr.Close()
+		for _, f := range zr.File {
+			rc, err := f.Open()
+			if err != nil {
+				t.Fatal(err)
+			}
+			files[f.Name], _ = io.ReadAll(rc)
+			rc.Close()
+		}
+		return files
+	}
+	err := filepath.Walk(path, func(p string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() {
+			return err
+		}
+		rel, _ := filepath.Rel(path, p)
+		files[filepath.ToSlash(rel)], err = os.ReadFile(p)
+		return err
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return files
+}
+
+func TestSlackExportWriter(t *testing.T) {
+	for _, name := range []string{"export", "export.zip"} {
+		t.Run(name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), name)
+			w, err := NewSlackExportWriter(path)
+			if err != nil {
+				t.Fatalf("NewSlackExportWriter() returned an unexpected error: %v", err)
+			}
+			if err := w.AddLog(testSlackExportLog()); err != nil {
+				t.Fatalf("AddLog() returned an unexpected error: %v", err)
+			}
+			if err := w.Close(); err != nil {
+				t.Fatalf("Close() returned an unexpected error: %v", err)
+			}
+
+			files := readSlackExport(t, path)
+			var names []string
+			for name := range files {
+				names = append(names, name)
+			}
+			for _, want := range []string{"channels.json", "users.json", "general/2023-11-14.json", "general/2023-11-16.json"} {
+				if _, ok := files[want]; !ok {
+					t.Errorf("Expected %s in the export, got %v", want, names)
+				}
+			}
+
+			var channels []slackExportChannel
+			if err := json.Unmarshal(files["channels.json"], &channels); err != nil {
+				t.Fatal(err)
+			}
+			if want := []slackExportChannel{{ID: "C01", Name: "general", Created: 1700000000}}; !reflect.DeepEqual(channels, want) {
+				t.Errorf("channels.json = %+v, want %+v", channels, want)
+			}
+
+			var users []slackExportUser
+			if err := json.Unmarshal(files["users.json"], &users); err != nil {
+				t.Fatal(err)
+			}
+			if len(users) != 2 || users[0].ID != "U01" || users[0].Name != "alice" || users[1].ID != "U02" {
+				t.Errorf("Unexpected users.json: %+v", users)
+			}
+
+			var thread []map[string]any
+			if err := json.Unmarshal(files["general/2023-11-14.json"], &thread); err != nil {
+				t.Fatal(err)
+			}
+			if len(thread) != 2 {
+				t.Fatalf("Expected the parent and its reply on 2023-11-14, got %d messages", len(thread))
+			}
+			parent, reply := thread[0], thread[1]
+			if parent["ts"] != "1700000000.000100" || parent["thread_ts"] != "1700000000.000100" || parent["reply_count"] != 1.0 || parent["user"] != "U01" {
+				t.Errorf("Unexpected thread parent: %v", parent)
+			}
+			if reply["thread_ts"] != "1700000000.000100" || reply["parent_user_id"] != "U01" || reply["client_msg_id"] != "c0ffee" {
+				t.Errorf("Unexpected reply: %v", reply)
+			}
+			wantReply := map[string]any{
+				"edited":    map[string]any{"user": "U02", "ts": "1700000200.000000"},
+				"reactions": []any{map[string]any{"name": "eyes", "count": 1.0, "users": []any{"U01"}}},
+				"blocks":    []any{map[string]any{"type": "rich_text", "block_id": "b1"}},
+			}
+			for key, want := range wantReply {
+				if !reflect.DeepEqual(reply[key], want) {
+					t.Errorf("reply[%q] = %v, want %v", key, reply[key], want)
+				}
+			}
+
+			var day []slackExportMessage
+			if err := json.Unmarshal(files["general/2023-11-16.json"], &day); err != nil {
+				t.Fatal(err)
+			}
+			bot := day[0]
+			if bot.Subtype != "bot_message" || bot.BotID != "B01" || bot.Username != "deploy-bot" || bot.User != "" {
+				t.Errorf("Unexpected bot message: %+v", bot)
+			}
+			wantFiles := []slackExportFile{{ID: "F01", Created: 1700100000, Name: "log.txt", Title: "Deploy log", Mimetype: "text/plain", Filetype: "text",
+				PrettyType: "Plain Text", User: "B01", Mode: "hosted", Size: 42, URLPrivate: "https://files.slack.com/F01", URLPrivateDownload: "https://files.slack.com/F01/download"}}
+			if !reflect.DeepEqual(bot.Files, wantFiles) {
+				t.Errorf("Files = %+v, want %+v", bot.Files, wantFiles)
+			}
+			// Fields that only scat adds are not part of Slack's file objects.
+			for _, field := range []string{"local_path", "sha256", "download_error", "user_id"} {
+				if strings.Contains(string(files["general/2023-11-16.json"]), `"`+field+`"`) {
+					t.Errorf("Expected no %q in the files of the export", field)
+				}
+			}
+			var attachments bytes.Buffer
+			if err := json.Compact(&attachments, bot.Attachments); err != nil || attachments.String() != `[{"fallback":"build #1 passed","color":"good"}]` {
+				t.Errorf("Attachments = %s", bot.Attachments)
+			}
+		})
+	}
+}
+
+func TestSlackExportWriter_ChannelNames(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "export.zip")
+	w, err := NewSlackExportWriter(path)
+	if err != nil {
+		t.Fatalf("NewSlackExportWriter() returned an unexpected error: %v", err)
+	}
+	for _, channelName := range []string{"#日本語", "#営業", "#../dev"} {
+		log := &ExportedLog{ChannelName: channelName, Messages: []ExportedMessage{{UserID: "U01", PostType: "user", TimestampUnix: "1700000000.000100", Text: "hi"}}}
+		if err := w.AddLog(log); err != nil {
+			t.Fatalf("AddLog(%s) returned an unexpected error: %v", channelName, err)
+		}
+	}
+	if err := w.AddLog(&ExportedLog{ChannelName: "#.."}); err == nil {
+		t.Error("Expected an error for a channel named '..'")
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close() returned an unexpected error: %v", err)
+	}
+
+	files := readSlackExport(t, path)
+	var channels []slackExportChannel
+	if err := json.Unmarshal(files["channels.json"], &channels); err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, channel := range channels {
+		names = append(names, channel.Name)
+		// Viewers look up the messages of a channel by its name.
+		if _, ok := files[channel.Name+"/2023-11-14.json"]; !ok {
+			t.Errorf("Expected the messages of %s in %s/2023-11-14.json", channel.Name, channel.Name)
+		}
+	}
+	if want := []string{"..dev", "営業", "日本語"}; !reflect.DeepEqual(names, want) {
+		t.Errorf("Channel names = %q, want %q", names, want)
+	}
+}
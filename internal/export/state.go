@@ -0,0 +1,183 @@
+package export
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/nlink-jp/scat/internal/util"
+)
+
+// StateVersion is the version of the incremental export state file format.
+const StateVersion = 1
+
+// DefaultThreadWindow is how far back from the newest exported message an
+// incremental export rescans history for new thread replies.
+const DefaultThreadWindow = 30 * 24 * time.Hour
+
+// State records the progress of incremental exports, per channel.
+type State struct {
+	Version  int                      `json:"version"`
+	Channels map[string]*ChannelState `json:"channels"`
+}
+
+// ChannelState is the progress of incremental exports of one channel.
+type ChannelState struct {
+	// LatestTS is the ts of the newest top-level message exported. The next
+	// run fetches history newer than this.
+	LatestTS string `json:"latest_ts,omitempty"`
+
+	// Threads maps the parent ts of exported threads to the ts of their newest
+	// exported reply.
+	Threads map[string]string `json:"threads,omitempty"`
+
+	// Pending is set while a run is paging through history, so that an
+	// interrupted run can be resumed.
+	Pending *PendingExport `json:"pending,omitempty"`
+
+	UpdatedAt time.Time `json:"updated_at,omitzero"`
+}
+
+// PendingExport is the saved position of an interrupted export.
+type PendingExport struct {
+	Oldest        string `json:"oldest,omitempty"`         // StartTime of the interrupted run.
+	Latest        string `json:"latest,omitempty"`         // EndTime of the interrupted run.
+	ExportedUntil string `json:"exported_until,omitempty"` // ExportedUntil of the interrupted run.
+	Cursor        string `json:"cursor"`                   // Cursor of the next history page.
+	NewestTS      string `json:"newest_ts,omitempty"`      // Newest top-level message exported by the run so far.
+}
+
+// LoadState reads the state file at path. A missing file yields an empty state.
+func LoadState(path string) (*State, error) {
+	state := &State{Version: StateVersion, Channels: make(map[string]*ChannelState)}
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return state, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read export state: %w", err)
+	}
+	if err := json.Unmarshal(data, state); err != nil {
+		return nil, fmt.Errorf("failed to parse export state %s: %w", path, err)
+	}
+	if state.Version > StateVersion {
+		return nil, fmt.Errorf("export state %s has unsupported version %d", path, state.Version)
+	}
+	if state.Channels == nil {
+		state.Channels = make(map[string]*ChannelState)
+	}
+	state.Version = StateVersion
+	return state, nil
+}
+
+// Save atomically replaces the state file at path.
+func (s *State) Save(path string) error {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal export state: %w", err)
+	}
+	if err := util.WriteFileAtomic(path, data); err != nil {
+		return fmt.Errorf("failed to write export state: %w", err)
+	}
+	return nil
+}
+
+// Channel returns the state of a channel, creating it if necessary. Channel
+// names are keyed without a leading '#'.
+func (s *State) Channel(name string) *ChannelState {
+	key := strings.TrimPrefix(name, "#")
+	ch, ok := s.Channels[key]
+	if !ok {
+		ch = &ChannelState{}
+		s.Channels[key] = ch
+	}
+	if ch.Threads == nil {
+		ch.Threads = make(map[string]string)
+	}
+	return ch
+}
+
+// Resume returns the options for the next run of an incremental export,
+// starting from opts. An interrupted run is continued from its saved cursor.
+// Otherwise messages newer than LatestTS are exported, and history up to
+// window before LatestTS is rescanned for new replies to its threads.
+func (c *ChannelState) Resume(opts Options, window time.Duration) Options {
+	if c.Pending != nil {
+		opts.StartTime = c.Pending.Oldest
+		opts.EndTime = c.Pending.Latest
+		opts.ExportedUntil = c.Pending.ExportedUntil
+		opts.Cursor = c.Pending.Cursor
+	} else if c.LatestTS != "" {
+		opts.ExportedUntil = c.LatestTS
+		if latest, ok := parseTS(c.LatestTS); ok {
+			if start := formatTS(latest.Add(-window)); start > opts.StartTime {
+				opts.StartTime = start
+			}
+		}
+	}
+	opts.Threads = make(map[string]string, len(c.Threads))
+	for parent, latest := range c.Threads {
+		opts.Threads[parent] = latest
+	}
+	return opts
+}
+
+// Record updates the state with a page exported by a run using opts.
+func (c *ChannelState) Record(opts Options, page Page, now time.Time) {
+	newest := ""
+	if c.Pending != nil {
+		newest = c.Pending.NewestTS
+	}
+	for _, msg := range page.Messages {
+		if !msg.IsReply && msg.TimestampUnix > newest {
+			newest = msg.TimestampUnix
+		}
+		if msg.ThreadTimestampUnix != "" && msg.TimestampUnix > c.Threads[msg.ThreadTimestampUnix] {
+			c.Threads[msg.ThreadTimestampUnix] = msg.TimestampUnix
+		}
+	}
+
+	if page.NextCursor != "" {
+		c.Pending = &PendingExport{Oldest: opts.StartTime, Latest: opts.EndTime, ExportedUntil: opts.ExportedUntil, Cursor: page.NextCursor, NewestTS: newest}
+	} else {
+		// The history is complete.
+		c.Pending = nil
+		if newest > c.LatestTS {
+			c.LatestTS = newest
+		}
+	}
+	c.UpdatedAt = now
+}
+
+// Prune forgets threads whose parent is older than window before LatestTS,
+// as later runs no longer rescan them.
+func (c *ChannelState) Prune(window time.Duration) {
+	latest, ok := parseTS(c.LatestTS)
+	if !ok {
+		return
+	}
+	cutoff := formatTS(latest.Add(-window))
+	for parent := range c.Threads {
+		if parent < cutoff {
+			delete(c.Threads, parent)
+		}
+	}
+}
+
+// parseTS converts a Slack timestamp such as "1700000000.000100" to a time.
+func parseTS(ts string) (time.Time, bool) {
+	secs, _, _ := strings.Cut(ts, ".")
+	n, err := strconv.ParseInt(secs, 10, 64)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return time.Unix(n, 0), true
+}
+
+// formatTS converts a time to a Slack timestamp with second precision.
+func formatTS(t time.Time) string {
+	return fmt.Sprintf("%d.000000", t.Unix())
+}
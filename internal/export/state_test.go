@@ -0,0 +1,122 @@
+package export
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestChannelState_IncrementalRuns(t *testing.T) {
+	window := time.Hour
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	state := &State{Version: StateVersion, Channels: map[string]*ChannelState{}}
+	ch := state.Channel("#general")
+	if state.Channel("general") != ch {
+		t.Fatal("Expected channel names with and without '#' to share their state")
+	}
+
+	// A first run exports everything.
+	opts := ch.Resume(Options{ChannelName: "#general"}, window)
+	if opts.ExportedUntil != "" || opts.StartTime != "" || opts.Cursor != "" {
+		t.Fatalf("Expected a first run to export everything, got %+v", opts)
+	}
+
+	// It is interrupted after its first page ...
+	ch.Record(opts, Page{
+		Messages: []ExportedMessage{
+			{TimestampUnix: "1700007000.000000", ThreadTimestampUnix: "1700007000.000000"},
+			{TimestampUnix: "1700007100.000000", ThreadTimestampUnix: "1700007000.000000", IsReply: true},
+			{TimestampUnix: "1700009000.000000"},
+		},
+		NextCursor: "page2",
+	}, now)
+	if ch.LatestTS != "" {
+		t.Errorf("Expected LatestTS to stay unset until the history is complete, got %s", ch.LatestTS)
+	}
+
+	// ... so the next run resumes at its cursor and completes it.
+	opts = ch.Resume(Options{ChannelName: "#general"}, window)
+	if opts.Cursor != "page2" {
+		t.Fatalf("Expected the interrupted run to be resumed, got %+v", opts)
+	}
+	ch.Record(opts, Page{Messages: []ExportedMessage{{TimestampUnix: "1700001000.000000"}}}, now)
+	if ch.Pending != nil {
+		t.Errorf("Expected no pending export after the last page, got %+v", ch.Pending)
+	}
+	if ch.LatestTS != "1700009000.000000" {
+		t.Errorf("LatestTS = %s, want 1700009000.000000", ch.LatestTS)
+	}
+	if want := map[string]string{"1700007000.000000": "1700007100.000000"}; !reflect.DeepEqual(ch.Threads, want) {
+		t.Errorf("Threads = %v, want %v", ch.Threads, want)
+	}
+
+	// Later runs export newer messages and rescan the thread window.
+	opts = ch.Resume(Options{ChannelName: "#general"}, window)
+	if opts.ExportedUntil != "1700009000.000000" || opts.StartTime != "1700005400.000000" || opts.Cursor != "" {
+		t.Errorf("Unexpected options for an incremental run: %+v", opts)
+	}
+	if !reflect.DeepEqual(opts.Threads, ch.Threads) {
+		t.Errorf("Expected the known threads to be passed on, got %v", opts.Threads)
+	}
+	if opts = ch.Resume(Options{StartTime: "1700008000.000000"}, window); opts.StartTime != "1700008000.000000" {
+		t.Errorf("Expected a later --start-time to be kept, got %s", opts.StartTime)
+	}
+
+	// Threads that fall out of the window are forgotten.
+	ch.Record(opts, Page{Messages: []ExportedMessage{{TimestampUnix: "1700012000.000000"}}}, now)
+	ch.Prune(window)
+	if len(ch.Threads) != 0 {
+		t.Errorf("Expected old threads to be pruned, got %v", ch.Threads)
+	}
+}
+
+func TestState_LoadAndSave(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.json")
+
+	state, err := LoadState(path)
+	if err != nil {
+		t.Fatalf("LoadState() of a missing file returned an error: %v", err)
+	}
+	state.Channel("general").LatestTS = "1700000000.000000"
+	if err := state.Save(path); err != nil {
+		t.Fatalf("Save() returned an unexpected error: %v", err)
+	}
+	if info, err := os.Stat(path); err != nil || info.Mode().Perm() != 0600 {
+		t.Errorf("Expected the state file to be saved with mode 0600, got %v, %v", info, err)
+	}
+
+	loaded, err := LoadState(path)
+	if err != nil {
+		t.Fatalf("LoadState() returned an unexpected error: %v", err)
+	}
+	if got := loaded.Channel("general").LatestTS; got != "1700000000.000000" {
+		t.Errorf("LatestTS = %s after reloading, want 1700000000.000000", got)
+	}
+
+	if err := os.WriteFile(path, []byte(`{"version": 99}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadState(path); err == nil {
+		t.Error("Expected an error for an unsupported state version, got nil")
+	}
+}
+
+func TestMergeMessages(t *testing.T) {
+	existing := []ExportedMessage{
+		{TimestampUnix: "1700000002.000000", Text: "old copy"},
+		{TimestampUnix: "1700000001.000000", Text: "first"},
+	}
+	added := []ExportedMessage{
+		{TimestampUnix: "1700000003.000000", Text: "third"},
+		{TimestampUnix: "1700000002.000000", Text: "new copy"},
+	}
+	var got []string
+	for _, msg := range MergeMessages(existing, added) {
+		got = append(got, msg.Text)
+	}
+	if want := []string{"first", "new copy", "third"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("MergeMessages() = %v, want %v", got, want)
+	}
+}
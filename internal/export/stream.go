@@ -0,0 +1,49 @@
+package export
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+)
+
+// Collect runs a streaming export and gathers its pages into one log, with
+// the messages sorted by timestamp. It suits output formats that need the
+// whole log at once; memory use grows with the size of the log.
+func Collect(stream func(onPage PageFunc) (*LogInfo, error)) (*ExportedLog, error) {
+	var messages []ExportedMessage
+	info, err := stream(func(page Page) error {
+		messages = append(messages, page.Messages...)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	SortMessages(messages)
+	return &ExportedLog{
+		FormatVersion:   FormatVersion,
+		ExportTimestamp: info.ExportTimestamp,
+		ChannelName:     info.ChannelName,
+		ChannelID:       info.ChannelID,
+		Messages:        messages,
+	}, nil
+}
+
+// SortMessages sorts messages by timestamp ascending.
+func SortMessages(messages []ExportedMessage) {
+	sort.SliceStable(messages, func(i, j int) bool {
+		return messages[i].TimestampUnix < messages[j].TimestampUnix
+	})
+}
+
+// WriteNDJSON writes messages as newline-delimited JSON, one message per
+// line, in the order given.
+func WriteNDJSON(w io.Writer, messages []ExportedMessage) error {
+	encoder := json.NewEncoder(w)
+	for _, msg := range messages {
+		if err := encoder.Encode(msg); err != nil {
+			return fmt.Errorf("failed to write NDJSON: %w", err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,70 @@
+package export
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestCollect(t *testing.T) {
+	// Pages come newest first, and a reply travels with its thread parent.
+	pages := []Page{
+		{Messages: []ExportedMessage{{TimestampUnix: "1700000300.000000"}, {TimestampUnix: "1700000400.000000"}}, NextCursor: "next"},
+		{Messages: []ExportedMessage{{TimestampUnix: "1700000100.000000"}, {TimestampUnix: "1700000500.000000", IsReply: true}}},
+	}
+	log, err := Collect(func(onPage PageFunc) (*LogInfo, error) {
+		for _, page := range pages {
+			if err := onPage(page); err != nil {
+				return nil, err
+			}
+		}
+		return &LogInfo{ExportTimestamp: "2023-11-20T00:00:00Z", ChannelName: "#general", ChannelID: "C01"}, nil
+	})
+	if err != nil {
+		t.Fatalf("Collect() returned an unexpected error: %v", err)
+	}
+	if log.ChannelName != "#general" || log.ChannelID != "C01" || log.ExportTimestamp != "2023-11-20T00:00:00Z" {
+		t.Errorf("Collect() did not keep the log info: %+v", log)
+	}
+	var got []string
+	for _, msg := range log.Messages {
+		got = append(got, msg.TimestampUnix)
+	}
+	want := []string{"1700000100.000000", "1700000300.000000", "1700000400.000000", "1700000500.000000"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Collect() messages = %v, want %v", got, want)
+	}
+
+	wantErr := errors.New("boom")
+	if _, err := Collect(func(onPage PageFunc) (*LogInfo, error) { return nil, wantErr }); !errors.Is(err, wantErr) {
+		t.Errorf("Collect() error = %v, want %v", err, wantErr)
+	}
+}
+
+func TestWriteNDJSON(t *testing.T) {
+	msgs := []ExportedMessage{
+		{UserID: "U01", TimestampUnix: "1700000000.000100", Text: "line one\nline two"},
+		{UserID: "U02", TimestampUnix: "1700000100.000200", Text: "reply", ThreadTimestampUnix: "1700000000.000100", IsReply: true},
+	}
+	var buf bytes.Buffer
+	if err := WriteNDJSON(&buf, msgs); err != nil {
+		t.Fatalf("WriteNDJSON() returned an unexpected error: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	if len(lines) != len(msgs) {
+		t.Fatalf("Expected %d lines, got %d:\n%s", len(msgs), len(lines), buf.String())
+	}
+	for i, line := range lines {
+		var got ExportedMessage
+		if err := json.Unmarshal([]byte(line), &got); err != nil {
+			t.Fatalf("Line %d is not valid JSON: %v", i+1, err)
+		}
+		if !reflect.DeepEqual(got, msgs[i]) {
+			t.Errorf("Line %d = %+v, want %+v", i+1, got, msgs[i])
+		}
+	}
+}
@@ -1,9 +1,19 @@
 package export
 
+import "encoding/json"
+
+// FormatVersion is the version of the export format written by this version
+// of scat. Version 1, which had no version field, lacked the reactions, edits,
+// blocks, legacy attachments, client message IDs, and thread summaries of
+// messages, and the raw provider JSON.
+const FormatVersion = 2
+
 // ExportedLog is the top-level structure for the exported log file.
 type ExportedLog struct {
+	FormatVersion   int               `json:"format_version,omitempty"`
 	ExportTimestamp string            `json:"export_timestamp"`
 	ChannelName     string            `json:"channel_name"`
+	ChannelID       string            `json:"channel_id,omitempty"`
 	Messages        []ExportedMessage `json:"messages"`
 }
 
@@ -12,20 +22,68 @@ type ExportedMessage struct {
 	UserID              string         `json:"user_id"`
 	UserName            string         `json:"user_name,omitempty"`
 	PostType            string         `json:"post_type,omitempty"` // "user" or "bot"
+	Subtype             string         `json:"subtype,omitempty"`   // Slack message subtype, e.g. "bot_message"
 	Timestamp           string         `json:"timestamp"`
 	TimestampUnix       string         `json:"timestamp_unix"`
 	Text                string         `json:"text"`
 	Files               []ExportedFile `json:"files,omitempty"`
 	ThreadTimestampUnix string         `json:"thread_timestamp_unix,omitempty"`
 	IsReply             bool           `json:"is_reply"`
+
+	// The fields below were added in format version 2.
+	ClientMsgID string             `json:"client_msg_id,omitempty"`
+	Edited      *ExportedEdit      `json:"edited,omitempty"`
+	Reactions   []ExportedReaction `json:"reactions,omitempty"`
+	ReplyCount  int                `json:"reply_count,omitempty"` // Set on thread parents.
+	ReplyUsers  []string           `json:"reply_users,omitempty"` // Set on thread parents.
+	Blocks      json.RawMessage    `json:"blocks,omitempty"`      // Block Kit blocks, as returned by the provider.
+	Attachments json.RawMessage    `json:"attachments,omitempty"` // Legacy attachments, as returned by the provider.
+
+	// Raw is the message as returned by the provider, untouched. It is only
+	// set if Options.IncludeRaw is.
+	Raw json.RawMessage `json:"raw,omitempty"`
+}
+
+// ExportedEdit records the last edit of a message.
+type ExportedEdit struct {
+	UserID        string `json:"user_id"`
+	Timestamp     string `json:"timestamp"`
+	TimestampUnix string `json:"timestamp_unix"`
+}
+
+// ExportedReaction is an emoji reaction on a message.
+type ExportedReaction struct {
+	Name  string   `json:"name"`
+	Count int      `json:"count"`
+	Users []string `json:"users,omitempty"`
 }
 
 // ExportedFile represents a file attached to a message in the exported log.
 type ExportedFile struct {
-	ID        string `json:"id"`
-	Name      string `json:"name"`
-	Mimetype  string `json:"mimetype"`
-	LocalPath string `json:"local_path,omitempty"` // Path to the downloaded file
+	ID         string `json:"id"`
+	Name       string `json:"name"`
+	Title      string `json:"title,omitempty"`
+	Mimetype   string `json:"mimetype"`
+	Filetype   string `json:"filetype,omitempty"`
+	Size       int64  `json:"size,omitempty"`
+	URLPrivate string `json:"url_private,omitempty"`
+	Permalink  string `json:"permalink,omitempty"`
+	LocalPath  string `json:"local_path,omitempty"` // Path to the downloaded file
+
+	// Created, UserID, PrettyType, Mode, IsExternal, and URLPrivateDownload
+	// are Slack's own file fields, kept for the slack-export format.
+	Created            int64  `json:"created,omitempty"`
+	UserID             string `json:"user_id,omitempty"`
+	PrettyType         string `json:"pretty_type,omitempty"`
+	Mode               string `json:"mode,omitempty"`
+	IsExternal         bool   `json:"is_external,omitempty"`
+	URLPrivateDownload string `json:"url_private_download,omitempty"`
+
+	// SHA256 is the hex-encoded SHA-256 checksum of the downloaded file.
+	SHA256 string `json:"sha256,omitempty"`
+
+	// DownloadError is set if the file was to be downloaded but could not be.
+	DownloadError string `json:"download_error,omitempty"`
 }
 
 // Options defines the parameters for an export operation.
@@ -35,4 +93,73 @@ type Options struct {
 	EndTime      string
 	IncludeFiles bool
 	OutputDir    string
-}
\ No newline at end of file
+
+	// IncludeRaw sets the Raw field of exported messages.
+	IncludeRaw bool
+
+	// Concurrency is the number of messages, with the replies to their
+	// threads, that are processed in parallel. Values below 1 mean 1.
+	Concurrency int
+
+	// Cursor resumes an interrupted export at this pagination cursor, as
+	// reported by Page.NextCursor. StartTime and EndTime must be unchanged.
+	Cursor string
+
+	// ExportedUntil is the ts of the newest top-level message exported by an
+	// earlier run. Messages up to it are not exported again; for them, only
+	// thread replies newer than the ts recorded in Threads are exported.
+	ExportedUntil string
+
+	// Threads maps the parent ts of threads exported by earlier runs to the ts
+	// of their newest exported reply.
+	Threads map[string]string
+}
+
+// LogInfo describes a channel log exported by a provider. The messages
+// themselves are handed over page by page.
+type LogInfo struct {
+	ExportTimestamp string
+	ChannelName     string
+	ChannelID       string
+}
+
+// PageFunc receives the pages of a streaming export. An error returned by it
+// aborts the export.
+type PageFunc func(Page) error
+
+// Page is a batch of exported messages passed to a PageFunc.
+//
+// Pages follow the pages of the channel history, which Slack returns newest
+// first. Each page holds the top-level messages of its history page, together
+// with the replies to their threads, sorted by timestamp; a reply is always
+// part of the page of its thread parent, even if it is newer than messages of
+// earlier pages. Every message is thus in exactly one page, and sorting all
+// messages by timestamp restores the chronological order of the channel.
+type Page struct {
+	Messages []ExportedMessage
+
+	// NextCursor resumes the export after this page. It is empty once the
+	// channel history has been exported completely.
+	NextCursor string
+}
+
+// Manifest is the index written next to the per-channel logs of an export of
+// several channels.
+type Manifest struct {
+	FormatVersion   int             `json:"format_version,omitempty"`
+	ExportTimestamp string          `json:"export_timestamp"`
+	Format          string          `json:"format"`
+	Channels        []ManifestEntry `json:"channels"`
+}
+
+// ManifestEntry describes the export of one channel in a Manifest.
+type ManifestEntry struct {
+	ChannelName  string `json:"channel_name"`
+	File         string `json:"file"`                // Log file, relative to the manifest.
+	FilesDir     string `json:"files_dir,omitempty"` // Directory of downloaded files.
+	MessageCount int    `json:"message_count"`       // Messages written by this run.
+	Error        string `json:"error,omitempty"`     // Set if the export of the channel failed.
+
+	// FailedDownloads is the number of attachments that could not be downloaded.
+	FailedDownloads int `json:"failed_downloads,omitempty"`
+}
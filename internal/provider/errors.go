@@ -0,0 +1,80 @@
+package provider
+
+import (
+	"errors"
+	"fmt"
+)
+
+// This file defines provider-agnostic error values. Providers wrap or return
+// these so that callers can react to specific conditions with errors.Is and
+// errors.As instead of matching on error strings.
+
+var (
+	// ErrNotInChannel indicates that the authenticated identity is not a member of the channel.
+	ErrNotInChannel = errors.New("not in channel")
+
+	// ErrChannelNotFound indicates that the requested channel does not exist or is not visible.
+	ErrChannelNotFound = errors.New("channel not found")
+
+	// ErrUserNotFound indicates that the requested user does not exist.
+	ErrUserNotFound = errors.New("user not found")
+
+	// ErrMessageNotFound indicates that the referenced message does not exist.
+	ErrMessageNotFound = errors.New("message not found")
+
+	// ErrRateLimited indicates that the provider kept throttling the request.
+	ErrRateLimited = errors.New("rate limited")
+
+	// ErrAuth indicates that the token is missing, invalid, expired, or revoked.
+	ErrAuth = errors.New("authentication failed")
+
+	// ErrMissingScope indicates that the token lacks a permission required by the call.
+	// The concrete error is a *MissingScopeError carrying the needed scope.
+	ErrMissingScope = errors.New("missing scope")
+)
+
+// MissingScopeError reports the OAuth scope required for a call.
+// It matches ErrMissingScope with errors.Is.
+type MissingScopeError struct {
+	Needed   string // Scope(s) required by the call, if reported by the provider.
+	Provided string // Scope(s) granted to the token, if reported by the provider.
+}
+
+func (e *MissingScopeError) Error() string {
+	if e.Needed == "" {
+		return ErrMissingScope.Error()
+	}
+	return fmt.Sprintf("%s: %s", ErrMissingScope, e.Needed)
+}
+
+// Is reports whether target is ErrMissingScope.
+func (e *MissingScopeError) Is(target error) bool {
+	return target == ErrMissingScope
+}
+
+// APIError is returned when a provider's API rejects a call.
+// Err holds the classified error (one of the sentinels above or a
+// *MissingScopeError) and is nil when the error code is not recognized.
+type APIError struct {
+	Provider string // Provider name, e.g. "slack".
+	Method   string // API method that failed, e.g. "chat.postMessage".
+	Code     string // Raw error code returned by the API, e.g. "not_in_channel".
+	Err      error
+}
+
+func (e *APIError) Error() string {
+	msg := fmt.Sprintf("%s API error", e.Provider)
+	if e.Method != "" {
+		msg += " on " + e.Method
+	}
+	msg += ": " + e.Code
+	if scopeErr, ok := e.Err.(*MissingScopeError); ok && scopeErr.Needed != "" {
+		msg += fmt.Sprintf(" (needed: %s)", scopeErr.Needed)
+	}
+	return msg
+}
+
+// Unwrap returns the classified error.
+func (e *APIError) Unwrap() error {
+	return e.Err
+}
@@ -1,8 +1,11 @@
 package mock
 
 import (
+	"context"
 	"fmt"
 	"os"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/nlink-jp/scat/internal/appcontext"
@@ -15,6 +18,9 @@ import (
 type Provider struct {
 	Profile config.Profile
 	Context appcontext.Context // Use appcontext.Context
+
+	mu        sync.Mutex
+	scheduled []provider.ScheduledMessage // Messages scheduled through this instance.
 }
 
 // NewProvider creates a new mock Provider.
@@ -25,19 +31,24 @@ func NewProvider(p config.Profile, ctx appcontext.Context) (provider.Interface,
 // Capabilities returns the features supported by the mock provider.
 func (p *Provider) Capabilities() provider.Capabilities {
 	return provider.Capabilities{
-		CanListChannels:    false,
-		CanListUsers:       false,
-		CanPostFile:        true,
-		CanUseIconEmoji:    false,
-		CanExportLogs:      true,
-		CanPostBlocks:      true,
-		CanCreateChannel:   true,
-		CanInviteToChannel: false,
+		CanListChannels:     false,
+		CanListUsers:        false,
+		CanPostFile:         true,
+		CanUseIconEmoji:     false,
+		CanExportLogs:       true,
+		CanPostBlocks:       true,
+		CanCreateChannel:    true,
+		CanInviteToChannel:  false,
+		CanEditMessages:     true,
+		CanScheduleMessages: true,
+		CanReact:            true,
+		CanPostEphemeral:    true,
+		CanPin:              true,
 	}
 }
 
 // PostMessage prints a mock message.
-func (p *Provider) PostMessage(opts provider.PostMessageOptions) error {
+func (p *Provider) PostMessage(ctx context.Context, opts provider.PostMessageOptions) (*provider.PostResult, error) {
 	var destination string
 	switch {
 	case opts.TargetUserID != "":
@@ -46,7 +57,7 @@ func (p *Provider) PostMessage(opts provider.PostMessageOptions) error {
 		destination = fmt.Sprintf("Channel: %s", opts.TargetChannel)
 	default:
 		if p.Profile.Channel == "" {
-			return fmt.Errorf("no channel or user specified; please set a default channel in the profile or use the --channel or --user flag")
+			return nil, fmt.Errorf("no channel or user specified; please set a default channel in the profile or use the --channel or --user flag")
 		}
 		destination = fmt.Sprintf("Channel: %s (default)", p.Profile.Channel)
 	}
@@ -54,6 +65,15 @@ func (p *Provider) PostMessage(opts provider.PostMessageOptions) error {
 	if !p.Context.Silent {
 		fmt.Fprintln(os.Stderr, "--- [MOCK] PostMessage called ---")
 		fmt.Fprintln(os.Stderr, destination)
+		if opts.ThreadTS != "" {
+			fmt.Fprintf(os.Stderr, "Thread: %s (broadcast: %t)\n", opts.ThreadTS, opts.ReplyBroadcast)
+		}
+		if !opts.PostAt.IsZero() {
+			fmt.Fprintf(os.Stderr, "Scheduled for: %s\n", opts.PostAt.Format(time.RFC3339))
+		}
+		if opts.EphemeralUserID != "" {
+			fmt.Fprintf(os.Stderr, "Visible only to: %s\n", opts.EphemeralUserID)
+		}
 		if len(opts.Blocks) > 0 {
 			fmt.Fprintf(os.Stderr, "Blocks: %s\n", string(opts.Blocks))
 		} else {
@@ -63,11 +83,65 @@ func (p *Provider) PostMessage(opts provider.PostMessageOptions) error {
 	if p.Context.Debug {
 		fmt.Fprintf(os.Stderr, "[DEBUG] Mock PostMessage: Destination=\"%s\", Text=\"%s\", Username=\"%s\", IconEmoji=\"%s\", Blocks=\"%s\"\n", destination, opts.Text, opts.OverrideUsername, opts.IconEmoji, string(opts.Blocks))
 	}
+	if !opts.PostAt.IsZero() {
+		return p.schedule(opts), nil
+	}
+	if opts.EphemeralUserID != "" {
+		return &provider.PostResult{ChannelID: "C0MOCKCHANNEL"}, nil
+	}
+	return mockResult(""), nil
+}
+
+// schedule records a scheduled message so that it can be listed and deleted
+// through the same provider instance.
+func (p *Provider) schedule(opts provider.PostMessageOptions) *provider.PostResult {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	msg := provider.ScheduledMessage{
+		ID:        fmt.Sprintf("Q0MOCK%04d", len(p.scheduled)+1),
+		ChannelID: "C0MOCKCHANNEL",
+		PostAt:    opts.PostAt.Truncate(time.Second),
+		CreatedAt: time.Now().Truncate(time.Second),
+		Text:      opts.Text,
+	}
+	p.scheduled = append(p.scheduled, msg)
+	return &provider.PostResult{
+		ChannelID:          msg.ChannelID,
+		ScheduledMessageID: msg.ID,
+		PostAt:             msg.PostAt,
+	}
+}
+
+// ListScheduledMessages returns the messages scheduled through this instance.
+func (p *Provider) ListScheduledMessages(ctx context.Context, opts provider.ListScheduledMessagesOptions) ([]provider.ScheduledMessage, error) {
+	if !p.Context.Silent {
+		fmt.Fprintf(os.Stderr, "--- [MOCK] ListScheduledMessages called for channel '%s' ---\n", opts.Channel)
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return append([]provider.ScheduledMessage(nil), p.scheduled...), nil
+}
+
+// DeleteScheduledMessage prints a mock message and forgets the scheduled message if it is known.
+func (p *Provider) DeleteScheduledMessage(ctx context.Context, opts provider.DeleteScheduledMessageOptions) error {
+	if !p.Context.Silent {
+		fmt.Fprintln(os.Stderr, "--- [MOCK] DeleteScheduledMessage called ---")
+		fmt.Fprintf(os.Stderr, "Channel: %s, ID: %s\n", opts.Channel, opts.ID)
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for i, m := range p.scheduled {
+		if m.ID == opts.ID {
+			p.scheduled = append(p.scheduled[:i], p.scheduled[i+1:]...)
+			break
+		}
+	}
 	return nil
 }
 
 // PostFile prints a mock message.
-func (p *Provider) PostFile(opts provider.PostFileOptions) error {
+func (p *Provider) PostFile(ctx context.Context, opts provider.PostFileOptions) (*provider.PostResult, error) {
 	var destination string
 	switch {
 	case opts.TargetUserID != "":
@@ -76,7 +150,7 @@ func (p *Provider) PostFile(opts provider.PostFileOptions) error {
 		destination = fmt.Sprintf("Channel: %s", opts.TargetChannel)
 	default:
 		if p.Profile.Channel == "" {
-			return fmt.Errorf("no channel or user specified; please set a default channel in the profile or use the --channel or --user flag")
+			return nil, fmt.Errorf("no channel or user specified; please set a default channel in the profile or use the --channel or --user flag")
 		}
 		destination = fmt.Sprintf("Channel: %s (default)", p.Profile.Channel)
 	}
@@ -84,37 +158,132 @@ func (p *Provider) PostFile(opts provider.PostFileOptions) error {
 	if !p.Context.Silent {
 		fmt.Fprintln(os.Stderr, "--- [MOCK] PostFile called ---")
 		fmt.Fprintln(os.Stderr, destination)
-		fmt.Fprintf(os.Stderr, "File: %s\n", opts.FilePath)
+		if opts.ThreadTS != "" {
+			fmt.Fprintf(os.Stderr, "Thread: %s\n", opts.ThreadTS)
+		}
+		for _, f := range opts.UploadFiles() {
+			if f.Content != nil {
+				fmt.Fprintf(os.Stderr, "File: %s (%d bytes)\n", f.Filename, f.Size)
+			} else {
+				fmt.Fprintf(os.Stderr, "File: %s\n", f.Path)
+			}
+		}
 	}
 	if p.Context.Debug {
 		fmt.Fprintf(os.Stderr, "[DEBUG] Mock PostFile: Destination=\"%s\", FilePath=\"%s\", Filename=\"%s\"\n", destination, opts.FilePath, opts.Filename)
 	}
+	return mockResult("F0MOCKFILE"), nil
+}
+
+// mockResult returns a plausible PostResult for a message posted now.
+func mockResult(fileID string) *provider.PostResult {
+	ts := fmt.Sprintf("%d.000000", time.Now().Unix())
+	return &provider.PostResult{
+		ChannelID: "C0MOCKCHANNEL",
+		Timestamp: ts,
+		FileID:    fileID,
+		Permalink: "https://mock.example.com/archives/C0MOCKCHANNEL/p" + strings.Replace(ts, ".", "", 1),
+	}
+}
+
+// UpdateMessage prints a mock message.
+func (p *Provider) UpdateMessage(ctx context.Context, opts provider.UpdateMessageOptions) (*provider.PostResult, error) {
+	if !p.Context.Silent {
+		fmt.Fprintln(os.Stderr, "--- [MOCK] UpdateMessage called ---")
+		fmt.Fprintf(os.Stderr, "Channel: %s, TS: %s\n", opts.Channel, opts.Timestamp)
+		if len(opts.Blocks) > 0 {
+			fmt.Fprintf(os.Stderr, "Blocks: %s\n", string(opts.Blocks))
+		} else {
+			fmt.Fprintf(os.Stderr, "Text: %s\n", opts.Text)
+		}
+	}
+	return &provider.PostResult{ChannelID: "C0MOCKCHANNEL", Timestamp: opts.Timestamp}, nil
+}
+
+// DeleteMessage prints a mock message.
+func (p *Provider) DeleteMessage(ctx context.Context, opts provider.DeleteMessageOptions) error {
+	if !p.Context.Silent {
+		fmt.Fprintln(os.Stderr, "--- [MOCK] DeleteMessage called ---")
+		fmt.Fprintf(os.Stderr, "Channel: %s, TS: %s\n", opts.Channel, opts.Timestamp)
+	}
+	return nil
+}
+
+// AddReaction prints a mock message.
+func (p *Provider) AddReaction(ctx context.Context, opts provider.ReactionOptions) error {
+	if !p.Context.Silent {
+		fmt.Fprintln(os.Stderr, "--- [MOCK] AddReaction called ---")
+		fmt.Fprintf(os.Stderr, "Channel: %s, TS: %s, Reaction: :%s:\n", opts.Channel, opts.Timestamp, opts.Name)
+	}
+	return nil
+}
+
+// RemoveReaction prints a mock message.
+func (p *Provider) RemoveReaction(ctx context.Context, opts provider.ReactionOptions) error {
+	if !p.Context.Silent {
+		fmt.Fprintln(os.Stderr, "--- [MOCK] RemoveReaction called ---")
+		fmt.Fprintf(os.Stderr, "Channel: %s, TS: %s, Reaction: :%s:\n", opts.Channel, opts.Timestamp, opts.Name)
+	}
+	return nil
+}
+
+// ListReactions returns a dummy reaction.
+func (p *Provider) ListReactions(ctx context.Context, opts provider.ListReactionsOptions) ([]provider.Reaction, error) {
+	if !p.Context.Silent {
+		fmt.Fprintf(os.Stderr, "--- [MOCK] ListReactions called for message %s ---\n", opts.Timestamp)
+	}
+	return []provider.Reaction{{Name: "thumbsup", Count: 1, Users: []string{"U012AB3CDE"}}}, nil
+}
+
+// AddPin prints a mock message.
+func (p *Provider) AddPin(ctx context.Context, opts provider.PinOptions) error {
+	if !p.Context.Silent {
+		fmt.Fprintln(os.Stderr, "--- [MOCK] AddPin called ---")
+		fmt.Fprintf(os.Stderr, "Channel: %s, TS: %s\n", opts.Channel, opts.Timestamp)
+	}
 	return nil
 }
 
+// RemovePin prints a mock message.
+func (p *Provider) RemovePin(ctx context.Context, opts provider.PinOptions) error {
+	if !p.Context.Silent {
+		fmt.Fprintln(os.Stderr, "--- [MOCK] RemovePin called ---")
+		fmt.Fprintf(os.Stderr, "Channel: %s, TS: %s\n", opts.Channel, opts.Timestamp)
+	}
+	return nil
+}
+
+// ListPins returns a dummy pinned message.
+func (p *Provider) ListPins(ctx context.Context, opts provider.ListPinsOptions) ([]provider.PinnedMessage, error) {
+	if !p.Context.Silent {
+		fmt.Fprintf(os.Stderr, "--- [MOCK] ListPins called for channel '%s' ---\n", opts.Channel)
+	}
+	return []provider.PinnedMessage{
+		{ChannelID: "C0MOCKCHANNEL", Timestamp: fmt.Sprintf("%d.000000", time.Now().Unix()), UserID: "U012AB3CDE", Text: "Pinned from mock!"},
+	}, nil
+}
+
 // ListChannels returns an error as it's not supported.
-func (p *Provider) ListChannels() ([]provider.Channel, error) {
+func (p *Provider) ListChannels(ctx context.Context) ([]provider.Channel, error) {
 	return nil, fmt.Errorf("ListChannels is not supported by the mock provider")
 }
 
 // ListUsers returns an error as it's not supported.
-func (p *Provider) ListUsers() ([]provider.UserInfo, error) {
+func (p *Provider) ListUsers(ctx context.Context) ([]provider.UserInfo, error) {
 	return nil, fmt.Errorf("ListUsers is not supported by the mock provider")
 }
 
 // InviteToChannel returns an error as it's not supported.
-func (p *Provider) InviteToChannel(opts provider.InviteToChannelOptions) error {
+func (p *Provider) InviteToChannel(ctx context.Context, opts provider.InviteToChannelOptions) error {
 	return fmt.Errorf("InviteToChannel is not supported by the mock provider")
 }
 
-// ExportLog returns a dummy log for testing.
-func (p *Provider) ExportLog(opts export.Options) (*export.ExportedLog, error) {
+// ExportLog hands over a dummy log for testing.
+func (p *Provider) ExportLog(ctx context.Context, opts export.Options, onPage export.PageFunc) (*export.LogInfo, error) {
 	if !p.Context.Silent {
 		fmt.Fprintf(os.Stderr, "--- [MOCK] ExportLog called for channel %s ---", opts.ChannelName)
 	}
-	return &export.ExportedLog{
-		ExportTimestamp: time.Now().UTC().Format(time.RFC3339),
-		ChannelName:     opts.ChannelName,
+	page := export.Page{
 		Messages: []export.ExportedMessage{
 			{
 				UserID:        "U012AB3CDE",
@@ -124,12 +293,18 @@ func (p *Provider) ExportLog(opts export.Options) (*export.ExportedLog, error) {
 				Text:          "Hello from mock exporter!",
 			},
 		},
-	},
-nil
+	}
+	if err := onPage(page); err != nil {
+		return nil, err
+	}
+	return &export.LogInfo{
+		ExportTimestamp: time.Now().UTC().Format(time.RFC3339),
+		ChannelName:     opts.ChannelName,
+	}, nil
 }
 
 // CreateChannel simulates creating a channel.
-func (p *Provider) CreateChannel(opts provider.CreateChannelOptions) (string, error) {
+func (p *Provider) CreateChannel(ctx context.Context, opts provider.CreateChannelOptions) (string, error) {
 	if opts.Name == "error" {
 		return "", fmt.Errorf("mock error creating channel")
 	}
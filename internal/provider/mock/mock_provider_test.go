@@ -2,12 +2,14 @@ package mock
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"io"
 	"log"
 	"os"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/nlink-jp/scat/internal/appcontext"
 	"github.com/nlink-jp/scat/internal/config"
@@ -69,7 +71,7 @@ func TestPostMessage(t *testing.T) {
 	opts := provider.PostMessageOptions{Text: "hello world"}
 
 	output := captureStderr(func() {
-		err := p.PostMessage(opts)
+		_, err := p.PostMessage(context.Background(), opts)
 		if err != nil {
 			t.Errorf("PostMessage() error = %v", err)
 		}
@@ -94,7 +96,7 @@ func TestPostMessage_WithTargetChannel(t *testing.T) {
 	opts := provider.PostMessageOptions{TargetChannel: "#override-channel", Text: "hello world"}
 
 	output := captureStderr(func() {
-		err := p.PostMessage(opts)
+		_, err := p.PostMessage(context.Background(), opts)
 		if err != nil {
 			t.Errorf("PostMessage() error = %v", err)
 		}
@@ -113,7 +115,7 @@ func TestPostMessage_Silent(t *testing.T) {
 	opts := provider.PostMessageOptions{Text: "hello world"}
 
 	output := captureStderr(func() {
-		err := p.PostMessage(opts)
+		_, err := p.PostMessage(context.Background(), opts)
 		if err != nil {
 			t.Errorf("PostMessage() error = %v", err)
 		}
@@ -132,7 +134,7 @@ func TestPostMessage_Debug(t *testing.T) {
 	opts := provider.PostMessageOptions{Text: "debug message"}
 
 	output := captureStderr(func() {
-		err := p.PostMessage(opts)
+		_, err := p.PostMessage(context.Background(), opts)
 		if err != nil {
 			t.Errorf("PostMessage() error = %v", err)
 		}
@@ -152,7 +154,7 @@ func TestPostMessage_WithBlocks(t *testing.T) {
 	opts := provider.PostMessageOptions{Blocks: blocksJSON}
 
 	output := captureStderr(func() {
-		err := p.PostMessage(opts)
+		_, err := p.PostMessage(context.Background(), opts)
 		if err != nil {
 			t.Errorf("PostMessage() error = %v", err)
 		}
@@ -172,7 +174,7 @@ func TestPostMessage_WithBlocks(t *testing.T) {
 
 func TestListChannels(t *testing.T) {
 	p, _ := NewProvider(config.Profile{}, appcontext.Context{})
-	_, err := p.ListChannels()
+	_, err := p.ListChannels(context.Background())
 	if err == nil {
 		t.Fatal("Expected an error from ListChannels, got nil")
 	}
@@ -187,7 +189,9 @@ func TestExportLog(t *testing.T) {
 	var log *export.ExportedLog
 	output := captureStderr(func() {
 		var err error
-		log, err = p.ExportLog(opts)
+		log, err = export.Collect(func(onPage export.PageFunc) (*export.LogInfo, error) {
+			return p.ExportLog(context.Background(), opts, onPage)
+		})
 		if err != nil {
 			t.Errorf("ExportLog() error = %v", err)
 		}
@@ -205,4 +209,33 @@ func TestExportLog(t *testing.T) {
 	if len(log.Messages) == 0 {
 		t.Error("Expected non-empty messages in exported log")
 	}
-}
\ No newline at end of file
+}
+func TestScheduledMessages(t *testing.T) {
+	ctx := appcontext.NewContext(false, false, true, "", false, nil)
+	p, _ := NewProvider(config.Profile{Channel: "#mock-channel"}, ctx)
+
+	postAt := time.Now().Add(time.Hour).Truncate(time.Second)
+	result, err := p.PostMessage(context.Background(), provider.PostMessageOptions{Text: "later", PostAt: postAt})
+	if err != nil {
+		t.Fatalf("PostMessage() error = %v", err)
+	}
+	if result.ScheduledMessageID == "" || !result.PostAt.Equal(postAt) || result.Timestamp != "" {
+		t.Errorf("Unexpected result for a scheduled message: %+v", result)
+	}
+
+	messages, err := p.ListScheduledMessages(context.Background(), provider.ListScheduledMessagesOptions{})
+	if err != nil {
+		t.Fatalf("ListScheduledMessages() error = %v", err)
+	}
+	if len(messages) != 1 || messages[0].ID != result.ScheduledMessageID || messages[0].Text != "later" {
+		t.Fatalf("Unexpected scheduled messages: %+v", messages)
+	}
+
+	if err := p.DeleteScheduledMessage(context.Background(), provider.DeleteScheduledMessageOptions{ID: result.ScheduledMessageID}); err != nil {
+		t.Fatalf("DeleteScheduledMessage() error = %v", err)
+	}
+	messages, _ = p.ListScheduledMessages(context.Background(), provider.ListScheduledMessagesOptions{})
+	if len(messages) != 0 {
+		t.Errorf("Expected no scheduled messages after delete, got: %+v", messages)
+	}
+}
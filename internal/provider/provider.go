@@ -1,6 +1,10 @@
 package provider
 
-import "github.com/nlink-jp/scat/internal/export"
+import (
+	"context"
+
+	"github.com/nlink-jp/scat/internal/export"
+)
 
 // Capabilities defines what features a provider supports.
 type Capabilities struct {
@@ -12,36 +16,88 @@ type Capabilities struct {
 	CanPostBlocks    bool // Whether the provider can post Block Kit messages.
 	CanCreateChannel bool // Whether the provider can create channels.
 	CanInviteToChannel bool // Whether the provider can invite users to a channel.
+	CanEditMessages bool // Whether the provider can update and delete posted messages.
+	CanScheduleMessages bool // Whether the provider can schedule, list, and delete scheduled messages.
+	CanReact bool // Whether the provider can add, remove, and list emoji reactions.
+	CanPostEphemeral bool // Whether the provider can post messages visible to a single user.
+	CanPin bool // Whether the provider can pin, unpin, and list pinned messages.
 }
 
 // Interface defines the methods that a provider must implement.
+//
+// Every method that may perform I/O accepts a context.Context as its first
+// argument. Implementations must stop work and return promptly (typically with
+// ctx.Err()) once the context is cancelled or its deadline is exceeded.
 type Interface interface {
 	// Capabilities returns a struct indicating supported features.
 	Capabilities() Capabilities
 
-	// PostMessage sends a text-based message.
-	PostMessage(opts PostMessageOptions) error
+	// PostMessage sends a text-based message and describes where it was posted.
+	PostMessage(ctx context.Context, opts PostMessageOptions) (*PostResult, error)
 
-	// PostFile sends a file.
-	PostFile(opts PostFileOptions) error
+	// PostFile sends a file and describes where it was posted.
+	PostFile(ctx context.Context, opts PostFileOptions) (*PostResult, error)
 
 	// ListChannels lists available channels with their IDs.
 	// This should only be called if Capabilities().CanListChannels is true.
-	ListChannels() ([]Channel, error)
+	ListChannels(ctx context.Context) ([]Channel, error)
 
 	// ListUsers lists available users with their IDs.
 	// This should only be called if Capabilities().CanListUsers is true.
-	ListUsers() ([]UserInfo, error)
+	ListUsers(ctx context.Context) ([]UserInfo, error)
 
-	// ExportLog performs the entire export operation.
+	// ExportLog exports the log of a channel, handing the messages to onPage
+	// page by page as they are fetched (see export.Page for their order), and
+	// returns a description of the log. export.Collect gathers the pages into
+	// a single log.
 	// This should only be called if Capabilities().CanExportLogs is true.
-	ExportLog(opts export.Options) (*export.ExportedLog, error)
+	ExportLog(ctx context.Context, opts export.Options, onPage export.PageFunc) (*export.LogInfo, error)
 
 	// CreateChannel creates a new channel.
 	// This should only be called if Capabilities().CanCreateChannel is true.
-	CreateChannel(opts CreateChannelOptions) (string, error)
+	CreateChannel(ctx context.Context, opts CreateChannelOptions) (string, error)
 
 	// InviteToChannel invites users or user groups to an existing channel.
 	// This should only be called if Capabilities().CanInviteToChannel is true.
-	InviteToChannel(opts InviteToChannelOptions) error
+	InviteToChannel(ctx context.Context, opts InviteToChannelOptions) error
+
+	// UpdateMessage replaces the text or blocks of a posted message.
+	// This should only be called if Capabilities().CanEditMessages is true.
+	UpdateMessage(ctx context.Context, opts UpdateMessageOptions) (*PostResult, error)
+
+	// DeleteMessage deletes a posted message.
+	// This should only be called if Capabilities().CanEditMessages is true.
+	DeleteMessage(ctx context.Context, opts DeleteMessageOptions) error
+
+	// ListScheduledMessages lists messages that are scheduled but not yet posted.
+	// This should only be called if Capabilities().CanScheduleMessages is true.
+	ListScheduledMessages(ctx context.Context, opts ListScheduledMessagesOptions) ([]ScheduledMessage, error)
+
+	// DeleteScheduledMessage cancels a scheduled message.
+	// This should only be called if Capabilities().CanScheduleMessages is true.
+	DeleteScheduledMessage(ctx context.Context, opts DeleteScheduledMessageOptions) error
+
+	// AddReaction adds an emoji reaction to a message.
+	// This should only be called if Capabilities().CanReact is true.
+	AddReaction(ctx context.Context, opts ReactionOptions) error
+
+	// RemoveReaction removes an emoji reaction from a message.
+	// This should only be called if Capabilities().CanReact is true.
+	RemoveReaction(ctx context.Context, opts ReactionOptions) error
+
+	// ListReactions lists the emoji reactions on a message.
+	// This should only be called if Capabilities().CanReact is true.
+	ListReactions(ctx context.Context, opts ListReactionsOptions) ([]Reaction, error)
+
+	// AddPin pins a message to its channel.
+	// This should only be called if Capabilities().CanPin is true.
+	AddPin(ctx context.Context, opts PinOptions) error
+
+	// RemovePin unpins a message from its channel.
+	// This should only be called if Capabilities().CanPin is true.
+	RemovePin(ctx context.Context, opts PinOptions) error
+
+	// ListPins lists the messages pinned to a channel.
+	// This should only be called if Capabilities().CanPin is true.
+	ListPins(ctx context.Context, opts ListPinsOptions) ([]PinnedMessage, error)
 }
@@ -2,7 +2,9 @@ package slack
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
@@ -29,6 +31,20 @@ const (
 	usersInfoURL              = "https://slack.com/api/users.info"
 	usergroupsListURL         = "https://slack.com/api/usergroups.list"
 	usergroupsUsersListURL    = "https://slack.com/api/usergroups.users.list"
+	chatGetPermalinkURL       = "https://slack.com/api/chat.getPermalink"
+	filesInfoURL              = "https://slack.com/api/files.info"
+	chatUpdateURL             = "https://slack.com/api/chat.update"
+	chatDeleteURL             = "https://slack.com/api/chat.delete"
+	scheduleMessageURL        = "https://slack.com/api/chat.scheduleMessage"
+	scheduledMessagesListURL  = "https://slack.com/api/chat.scheduledMessages.list"
+	deleteScheduledMessageURL = "https://slack.com/api/chat.deleteScheduledMessage"
+	reactionsAddURL           = "https://slack.com/api/reactions.add"
+	reactionsRemoveURL        = "https://slack.com/api/reactions.remove"
+	reactionsGetURL           = "https://slack.com/api/reactions.get"
+	postEphemeralURL          = "https://slack.com/api/chat.postEphemeral"
+	pinsAddURL                = "https://slack.com/api/pins.add"
+	pinsRemoveURL             = "https://slack.com/api/pins.remove"
+	pinsListURL               = "https://slack.com/api/pins.list"
 )
 
 // conversationsOpenResponse defines the structure for the conversations.open API response.
@@ -90,15 +106,50 @@ type usergroupsUsersListResponse struct {
 	Users []string `json:"users"`
 }
 
+// postMessageResponse defines the structure for the chat.postMessage API response.
+type postMessageResponse struct {
+	Ok        bool   `json:"ok"`
+	Error     string `json:"error"`
+	Channel   string `json:"channel"`
+	Timestamp string `json:"ts"`
+}
+
+// getPermalinkResponse defines the structure for the chat.getPermalink API response.
+type getPermalinkResponse struct {
+	Ok        bool   `json:"ok"`
+	Error     string `json:"error"`
+	Permalink string `json:"permalink"`
+}
+
+// filesInfoResponse defines the structure for the files.info API response.
+// Only the fields needed to locate the message that shared the file are decoded.
+type filesInfoResponse struct {
+	Ok    bool   `json:"ok"`
+	Error string `json:"error"`
+	File  struct {
+		ID        string `json:"id"`
+		Permalink string `json:"permalink"`
+		Shares    struct {
+			Public  map[string][]fileShare `json:"public"`
+			Private map[string][]fileShare `json:"private"`
+		} `json:"shares"`
+	} `json:"file"`
+}
+
+// fileShare describes a message in which a file was shared.
+type fileShare struct {
+	Timestamp string `json:"ts"`
+}
+
 // openDMChannel opens a direct message channel with a user and returns the channel ID.
-func (p *Provider) openDMChannel(userID string) (string, error) {
+func (p *Provider) openDMChannel(ctx context.Context, userID string) (string, error) {
 	payload := map[string]string{"users": userID}
 	jsonPayload, err := json.Marshal(payload)
 	if err != nil {
 		return "", fmt.Errorf("failed to marshal conversations.open payload: %w", err)
 	}
 
-	respBody, err := p.sendRequest("POST", conversationsOpenURL, bytes.NewBuffer(jsonPayload), "application/json; charset=utf-8")
+	respBody, err := p.sendRequest(ctx, "POST", conversationsOpenURL, bytes.NewBuffer(jsonPayload), "application/json; charset=utf-8")
 	if err != nil {
 		return "", err
 	}
@@ -119,7 +170,7 @@ func (p *Provider) openDMChannel(userID string) (string, error) {
 }
 
 // createConversation creates a new channel and returns the channel ID.
-func (p *Provider) createConversation(opts provider.CreateChannelOptions) (string, error) {
+func (p *Provider) createConversation(ctx context.Context, opts provider.CreateChannelOptions) (string, error) {
 	payload := map[string]interface{}{
 		"name":       opts.Name,
 		"is_private": opts.IsPrivate,
@@ -136,7 +187,7 @@ func (p *Provider) createConversation(opts provider.CreateChannelOptions) (strin
 		return "", fmt.Errorf("failed to marshal conversations.create payload: %w", err)
 	}
 
-	respBody, err := p.sendRequest("POST", conversationsCreateURL, bytes.NewBuffer(jsonPayload), "application/json; charset=utf-8")
+	respBody, err := p.sendRequest(ctx, "POST", conversationsCreateURL, bytes.NewBuffer(jsonPayload), "application/json; charset=utf-8")
 	if err != nil {
 		return "", err
 	}
@@ -157,7 +208,7 @@ func (p *Provider) createConversation(opts provider.CreateChannelOptions) (strin
 }
 
 // inviteUsersToChannel invites users to a channel.
-func (p *Provider) inviteUsersToChannel(channelID string, userIDs []string) error {
+func (p *Provider) inviteUsersToChannel(ctx context.Context, channelID string, userIDs []string) error {
 	payload := map[string]interface{}{
 		"channel": channelID,
 		"users":   strings.Join(userIDs, ","),
@@ -168,7 +219,7 @@ func (p *Provider) inviteUsersToChannel(channelID string, userIDs []string) erro
 		return fmt.Errorf("failed to marshal conversations.invite payload: %w", err)
 	}
 
-	respBody, err := p.sendRequest("POST", conversationsInviteURL, bytes.NewBuffer(jsonPayload), "application/json; charset=utf-8")
+	respBody, err := p.sendRequest(ctx, "POST", conversationsInviteURL, bytes.NewBuffer(jsonPayload), "application/json; charset=utf-8")
 	if err != nil {
 		return err
 	}
@@ -186,13 +237,13 @@ func (p *Provider) inviteUsersToChannel(channelID string, userIDs []string) erro
 }
 
 // getUserGroups fetches all user groups from the workspace.
-func (p *Provider) getUserGroups() ([]struct{ ID, Handle string }, error) {
+func (p *Provider) getUserGroups(ctx context.Context) ([]struct{ ID, Handle string }, error) {
 	if p.Context.Debug {
 		fmt.Fprintf(os.Stderr, "[DEBUG] Fetching user groups by calling usergroups.list...\n")
 	}
 	var allUserGroups []struct{ ID, Handle string }
 
-	respBody, err := p.sendRequest("GET", usergroupsListURL, nil, "")
+	respBody, err := p.sendRequest(ctx, "GET", usergroupsListURL, nil, "")
 	if err != nil {
 		return nil, err
 	}
@@ -214,13 +265,13 @@ func (p *Provider) getUserGroups() ([]struct{ ID, Handle string }, error) {
 }
 
 // getUserGroupUsers fetches the users in a user group.
-func (p *Provider) getUserGroupUsers(userGroupID string) ([]string, error) {
+func (p *Provider) getUserGroupUsers(ctx context.Context, userGroupID string) ([]string, error) {
 	if p.Context.Debug {
 		fmt.Fprintf(os.Stderr, "[DEBUG] Fetching users in user group %s by calling usergroups.users.list...\n", userGroupID)
 	}
 
 	url := fmt.Sprintf("%s?usergroup=%s", usergroupsUsersListURL, userGroupID)
-	respBody, err := p.sendRequest("GET", url, nil, "")
+	respBody, err := p.sendRequest(ctx, "GET", url, nil, "")
 	if err != nil {
 		return nil, err
 	}
@@ -237,7 +288,7 @@ func (p *Provider) getUserGroupUsers(userGroupID string) ([]string, error) {
 	return listResp.Users, nil
 }
 
-func (p *Provider) getConversationHistory(channelID string, opts export.Options, cursor string) (*conversationsHistoryResponse, error) {
+func (p *Provider) getConversationHistory(ctx context.Context, channelID string, opts export.Options, cursor string) (*conversationsHistoryResponse, error) {
 	params := url.Values{}
 	params.Add("channel", channelID)
 	if opts.EndTime != "" {
@@ -251,41 +302,42 @@ func (p *Provider) getConversationHistory(channelID string, opts export.Options,
 	}
 	params.Add("limit", "200")
 
-	respBody, err := p.sendRequest("GET", conversationsHistoryURL+"?"+params.Encode(), nil, "")
-	if err != nil && strings.Contains(err.Error(), "not_in_channel") {
+	respBody, err := p.sendRequest(ctx, "GET", conversationsHistoryURL+"?"+params.Encode(), nil, "")
+	if errors.Is(err, provider.ErrNotInChannel) {
 		if !p.Context.Silent {
 			fmt.Fprintf(os.Stderr, "Bot not in channel '%s'. Attempting to join...\n", opts.ChannelName)
 		}
-		if joinErr := p.joinChannel(channelID); joinErr != nil {
+		if joinErr := p.joinChannel(ctx, channelID); joinErr != nil {
 			return nil, fmt.Errorf("failed to auto-join channel '%s': %w", opts.ChannelName, joinErr)
 		}
 		if !p.Context.Silent {
 			fmt.Fprintf(os.Stderr, "Successfully joined channel '%s'. Retrying...\n", opts.ChannelName)
 		}
-		respBody, err = p.sendRequest("GET", conversationsHistoryURL+"?"+params.Encode(), nil, "")
+		respBody, err = p.sendRequest(ctx, "GET", conversationsHistoryURL+"?"+params.Encode(), nil, "")
 	}
 
 	if err != nil {
 		return nil, fmt.Errorf("failed to call conversations.history: %w", err)
 	}
 
-	var slackResp conversationsHistoryResponse
-	if err := json.Unmarshal(respBody, &slackResp); err != nil {
+	slackResp, err := decodeMessages(respBody, opts.IncludeRaw)
+	if err != nil {
 		return nil, fmt.Errorf("failed to unmarshal conversations.history response: %w", err)
 	}
-	return &slackResp, nil
+	return slackResp, nil
 }
 
-func (p *Provider) populateChannelCache() error {
+func (p *Provider) populateChannelCache(ctx context.Context) error {
 	if p.Context.Debug {
 		fmt.Fprintf(os.Stderr, "[DEBUG] Populating channel cache by calling conversations.list...\n")
 	}
 	p.channelIDCache = make(map[string]string)
+	p.channels = nil
 	cursor := ""
 
 	for {
 		url := fmt.Sprintf("%s?cursor=%s&types=public_channel,private_channel&limit=200", conversationsListURL, cursor)
-		body, err := p.sendRequest("GET", url, nil, "")
+		body, err := p.sendRequest(ctx, "GET", url, nil, "")
 		if err != nil {
 			return err
 		}
@@ -301,6 +353,7 @@ func (p *Provider) populateChannelCache() error {
 
 		for _, ch := range listResp.Channels {
 			p.channelIDCache[ch.Name] = ch.ID
+			p.channels = append(p.channels, provider.Channel{ID: ch.ID, Name: ch.Name, IsPrivate: ch.IsPrivate, IsArchived: ch.IsArchived})
 		}
 
 		cursor = listResp.ResponseMetadata.NextCursor
@@ -311,14 +364,14 @@ func (p *Provider) populateChannelCache() error {
 	return nil
 }
 
-func (p *Provider) joinChannel(channelID string) error {
+func (p *Provider) joinChannel(ctx context.Context, channelID string) error {
 	joinPayload := map[string]string{"channel": channelID}
 	jsonPayload, err := json.Marshal(joinPayload)
 	if err != nil {
 		return fmt.Errorf("failed to marshal join payload: %w", err)
 	}
 
-	respBody, err := p.sendRequest("POST", conversationsJoinURL, bytes.NewBuffer(jsonPayload), "application/json; charset=utf-8")
+	respBody, err := p.sendRequest(ctx, "POST", conversationsJoinURL, bytes.NewBuffer(jsonPayload), "application/json; charset=utf-8")
 	if err != nil {
 		return err
 	}
@@ -335,22 +388,144 @@ func (p *Provider) joinChannel(channelID string) error {
 	return nil
 }
 
-func (p *Provider) sendRequest(method, url string, body io.Reader, contentType string) ([]byte, error) {
+// getPermalink returns the permalink of the message identified by channelID and ts.
+func (p *Provider) getPermalink(ctx context.Context, channelID, ts string) (string, error) {
+	params := url.Values{}
+	params.Add("channel", channelID)
+	params.Add("message_ts", ts)
+
+	respBody, err := p.sendRequest(ctx, "GET", chatGetPermalinkURL+"?"+params.Encode(), nil, "")
+	if err != nil {
+		return "", err
+	}
+
+	var permalinkResp getPermalinkResponse
+	if err := json.Unmarshal(respBody, &permalinkResp); err != nil {
+		return "", fmt.Errorf("failed to unmarshal chat.getPermalink response: %w", err)
+	}
+	return permalinkResp.Permalink, nil
+}
+
+// getFileShare looks up the message that shared fileID in channelID.
+// It returns the message timestamp (empty if Slack has not recorded the share
+// yet) and the file's own permalink.
+func (p *Provider) getFileShare(ctx context.Context, fileID, channelID string) (ts, filePermalink string, err error) {
+	params := url.Values{}
+	params.Add("file", fileID)
+
+	respBody, err := p.sendRequest(ctx, "GET", filesInfoURL+"?"+params.Encode(), nil, "")
+	if err != nil {
+		return "", "", err
+	}
+
+	var infoResp filesInfoResponse
+	if err := json.Unmarshal(respBody, &infoResp); err != nil {
+		return "", "", fmt.Errorf("failed to unmarshal files.info response: %w", err)
+	}
+	for _, shares := range []map[string][]fileShare{infoResp.File.Shares.Public, infoResp.File.Shares.Private} {
+		if s := shares[channelID]; len(s) > 0 {
+			return s[0].Timestamp, infoResp.File.Permalink, nil
+		}
+	}
+	return "", infoResp.File.Permalink, nil
+}
+
+// sendRequest performs a Slack API call and returns the response body.
+// Requests are paced according to the method's rate limit tier, and
+// HTTP 429 responses are retried after the duration given in Retry-After.
+// Idempotent calls are additionally retried on transient failures according
+// to the profile's retry policy.
+func (p *Provider) sendRequest(ctx context.Context, method, url string, body io.Reader, contentType string) ([]byte, error) {
+	// Buffer the request body so that it can be re-sent on retries.
+	var payload []byte
+	if body != nil {
+		var err error
+		payload, err = io.ReadAll(body)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read request body: %w", err)
+		}
+	}
+
 	if p.Context.NoOp {
 		fmt.Fprintf(os.Stderr, "[DEBUG] Request: %s %s\n", method, url)
 		if body != nil {
-			// Read body for logging, then reset for actual request
-			var buf bytes.Buffer
-			t_body := io.TeeReader(body, &buf)
-			requestBytes, _ := io.ReadAll(t_body)
-			fmt.Fprintf(os.Stderr, "[DEBUG] Request Body: %s\n", string(requestBytes))
-			body = &buf // Reset body for actual request
+			fmt.Fprintf(os.Stderr, "[DEBUG] Request Body: %s\n", string(payload))
 		}
 	}
 
-	req, err := http.NewRequest(method, url, body)
+	apiMethod := apiMethodFromURL(url)
+	var respBody []byte
+	attempt := func() error {
+		var err error
+		respBody, err = p.sendOnce(ctx, method, url, payload, body != nil, contentType, apiMethod)
+		return err
+	}
+
+	var err error
+	if isIdempotent(method, apiMethod) {
+		err = p.withRetry(ctx, displayMethod(apiMethod), attempt)
+	} else {
+		err = attempt()
+	}
 	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
+		return nil, err
+	}
+	return respBody, nil
+}
+
+// sendOnce performs a single logical attempt of a Slack API call, waiting out
+// any HTTP 429 responses along the way.
+func (p *Provider) sendOnce(ctx context.Context, method, url string, payload []byte, hasBody bool, contentType, apiMethod string) ([]byte, error) {
+	for rateLimited := 0; ; rateLimited++ {
+		if err := p.limiter.wait(ctx, apiMethod, p.Context.Debug); err != nil {
+			return nil, err
+		}
+
+		var reqBody io.Reader
+		if hasBody {
+			reqBody = bytes.NewReader(payload)
+		}
+		resp, bodyBytes, err := p.doRequest(ctx, method, url, reqBody, contentType)
+		if err != nil {
+			return nil, err
+		}
+
+		if resp.StatusCode == http.StatusTooManyRequests {
+			retryAfter := parseRetryAfter(resp.Header.Get("Retry-After"))
+			if rateLimited >= maxRateLimitRetries {
+				return nil, fmt.Errorf("%w on %s: giving up after %d retries", provider.ErrRateLimited, displayMethod(apiMethod), rateLimited)
+			}
+			if p.Context.Debug {
+				fmt.Fprintf(os.Stderr, "[DEBUG] Rate limited (HTTP 429) on %s. Retrying after %s (attempt %d of %d)...\n", displayMethod(apiMethod), retryAfter, rateLimited+1, maxRateLimitRetries)
+			}
+			p.limiter.block(apiMethod, retryAfter)
+			continue
+		}
+
+		if resp.StatusCode >= 500 {
+			return nil, &transientError{fmt.Errorf("request failed with status code %d: %s", resp.StatusCode, string(bodyBytes))}
+		}
+		if resp.StatusCode >= 400 {
+			return nil, fmt.Errorf("request failed with status code %d: %s", resp.StatusCode, string(bodyBytes))
+		}
+
+		// Check for `ok: false` in the response body itself.
+		var baseResp apiResponse
+		if err := json.Unmarshal(bodyBytes, &baseResp); err == nil {
+			if !baseResp.Ok {
+				return nil, newAPIError(apiMethod, baseResp)
+			}
+		}
+
+		return bodyBytes, nil
+	}
+}
+
+// doRequest sends a single authenticated HTTP request and reads the whole response body.
+func (p *Provider) doRequest(ctx context.Context, method, url string, body io.Reader, contentType string) (*http.Response, []byte, error) {
+	req, err := http.NewRequestWithContext(ctx, method, url, body)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create request: %w", err)
 	}
 
 	if contentType != "" {
@@ -361,13 +536,13 @@ func (p *Provider) sendRequest(method, url string, body io.Reader, contentType s
 	// Use the httpClient from the Provider struct
 	resp, err := p.httpClient.Do(req)
 	if err != nil {
-		return nil, fmt.Errorf("failed to send request: %w", err)
+		return nil, nil, networkError(ctx, fmt.Errorf("failed to send request: %w", err))
 	}
 	defer resp.Body.Close()
 
 	bodyBytes, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read response body: %w", err)
+		return nil, nil, networkError(ctx, fmt.Errorf("failed to read response body: %w", err))
 	}
 
 	if p.Context.Debug {
@@ -375,38 +550,29 @@ func (p *Provider) sendRequest(method, url string, body io.Reader, contentType s
 		fmt.Fprintf(os.Stderr, "[DEBUG] Response Body: %s\n", string(bodyBytes))
 	}
 
-	if resp.StatusCode >= 400 {
-		return nil, fmt.Errorf("request failed with status code %d: %s", resp.StatusCode, string(bodyBytes))
-	}
-
-	// Check for `ok: false` in the response body itself.
-	var baseResp apiResponse
-	if err := json.Unmarshal(bodyBytes, &baseResp); err == nil {
-		if !baseResp.Ok {
-			return nil, fmt.Errorf("slack API error: %s", baseResp.Error)
-		}
-	}
-
-	return bodyBytes, nil
+	return resp, bodyBytes, nil
 }
 
-func (p *Provider) getConversationReplies(channelID, ts, cursor string) (*conversationsHistoryResponse, error) {
+func (p *Provider) getConversationReplies(ctx context.Context, channelID, ts, oldest, cursor string, raw bool) (*conversationsHistoryResponse, error) {
 	params := url.Values{}
 	params.Add("channel", channelID)
 	params.Add("ts", ts)
+	if oldest != "" {
+		params.Add("oldest", oldest)
+	}
 	if cursor != "" {
 		params.Add("cursor", cursor)
 	}
 	params.Add("limit", "200")
 
-	respBody, err := p.sendRequest("GET", conversationsRepliesURL+"?"+params.Encode(), nil, "")
+	respBody, err := p.sendRequest(ctx, "GET", conversationsRepliesURL+"?"+params.Encode(), nil, "")
 	if err != nil {
 		return nil, fmt.Errorf("failed to call conversations.replies: %w", err)
 	}
 
-	var slackResp conversationsHistoryResponse
-	if err := json.Unmarshal(respBody, &slackResp); err != nil {
+	slackResp, err := decodeMessages(respBody, raw)
+	if err != nil {
 		return nil, fmt.Errorf("failed to unmarshal conversations.replies response: %w", err)
 	}
-	return &slackResp, nil
+	return slackResp, nil
 }
@@ -1,6 +1,7 @@
 package slack
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"strings"
@@ -10,8 +11,11 @@ import (
 
 // ResolveChannelID ensures a channel ID is returned for a given name.
 // It first checks the local cache. If the name is not found, it refreshes
-// the cache from the API and checks again.
-func (p *Provider) ResolveChannelID(name string) (string, error) {
+// the cache from the API and checks again. It is safe for concurrent use.
+func (p *Provider) ResolveChannelID(ctx context.Context, name string) (string, error) {
+	p.channelMu.Lock()
+	defer p.channelMu.Unlock()
+
 	// First, try to get the ID from the existing cache.
 	id, err := p.getCachedChannelID(name)
 	if err == nil {
@@ -22,7 +26,7 @@ func (p *Provider) ResolveChannelID(name string) (string, error) {
 	if p.Context.Debug {
 		fmt.Fprintf(os.Stderr, "[DEBUG] Channel \"%s\" not in cache. Refreshing...\n", name)
 	}
-	if refreshErr := p.populateChannelCache(); refreshErr != nil {
+	if refreshErr := p.populateChannelCache(ctx); refreshErr != nil {
 		return "", fmt.Errorf("failed to refresh channel list: %w", refreshErr)
 	}
 
@@ -33,7 +37,7 @@ func (p *Provider) ResolveChannelID(name string) (string, error) {
 	}
 
 	// If it's still not found, the channel likely doesn't exist.
-	return "", fmt.Errorf("channel \"%s\" not found after refreshing cache", name)
+	return "", fmt.Errorf("%w: \"%s\"", provider.ErrChannelNotFound, name)
 }
 
 // getCachedChannelID is a helper that only checks the local cache.
@@ -54,38 +58,39 @@ func (p *Provider) getCachedChannelID(name string) (string, error) {
 	return "", fmt.Errorf("not found in cache")
 }
 
-func (p *Provider) ListChannels() ([]provider.Channel, error) {
+// ListChannels lists the public and private channels visible to the token,
+// including archived ones.
+func (p *Provider) ListChannels(ctx context.Context) ([]provider.Channel, error) {
+	p.channelMu.Lock()
+	defer p.channelMu.Unlock()
+
 	// Ensure the cache is populated before listing.
-	if p.channelIDCache == nil {
-		if err := p.populateChannelCache(); err != nil {
+	if p.channels == nil {
+		if err := p.populateChannelCache(ctx); err != nil {
 			return nil, err
 		}
 	}
-	var channels []provider.Channel
-	for name, id := range p.channelIDCache {
-		channels = append(channels, provider.Channel{ID: id, Name: name})
-	}
-	return channels, nil
+	return append([]provider.Channel(nil), p.channels...), nil
 }
 
 // InviteToChannel invites users or user groups to an existing channel.
-func (p *Provider) InviteToChannel(opts provider.InviteToChannelOptions) error {
-	channelID, err := p.ResolveChannelID(opts.Channel)
+func (p *Provider) InviteToChannel(ctx context.Context, opts provider.InviteToChannelOptions) error {
+	channelID, err := p.ResolveChannelID(ctx, opts.Channel)
 	if err != nil {
 		return fmt.Errorf("failed to resolve channel: %w", err)
 	}
 
 	userIDsToInvite := make(map[string]struct{})
 	for _, invitee := range opts.Invitees {
-		userID, err := p.ResolveUserID(invitee)
+		userID, err := p.ResolveUserID(ctx, invitee)
 		if err == nil {
 			userIDsToInvite[userID] = struct{}{}
 			continue
 		}
 
-		userGroupID, err := p.ResolveUserGroupID(invitee)
+		userGroupID, err := p.ResolveUserGroupID(ctx, invitee)
 		if err == nil {
-			userGroupUserIDs, err := p.getUserGroupUsers(userGroupID)
+			userGroupUserIDs, err := p.getUserGroupUsers(ctx, userGroupID)
 			if err != nil {
 				return fmt.Errorf("failed to get users for user group '%s': %w", invitee, err)
 			}
@@ -107,12 +112,12 @@ func (p *Provider) InviteToChannel(opts provider.InviteToChannelOptions) error {
 		return nil
 	}
 
-	return p.inviteUsersToChannel(channelID, finalUserIDs)
+	return p.inviteUsersToChannel(ctx, channelID, finalUserIDs)
 }
 
 // CreateChannel creates a new channel.
-func (p *Provider) CreateChannel(opts provider.CreateChannelOptions) (string, error) {
-	channelID, err := p.createConversation(opts)
+func (p *Provider) CreateChannel(ctx context.Context, opts provider.CreateChannelOptions) (string, error) {
+	channelID, err := p.createConversation(ctx, opts)
 	if err != nil {
 		return "", err
 	}
@@ -123,16 +128,16 @@ func (p *Provider) CreateChannel(opts provider.CreateChannelOptions) (string, er
 
 		for _, invitee := range opts.Invitees {
 			// Try to resolve as a user first.
-			userID, err := p.ResolveUserID(invitee)
+			userID, err := p.ResolveUserID(ctx, invitee)
 			if err == nil {
 				userIDsToInvite[userID] = struct{}{}
 				continue
 			}
 
 			// If not a user, try to resolve as a user group.
-			userGroupID, err := p.ResolveUserGroupID(invitee)
+			userGroupID, err := p.ResolveUserGroupID(ctx, invitee)
 			if err == nil {
-				userGroupUserIDs, err := p.getUserGroupUsers(userGroupID)
+				userGroupUserIDs, err := p.getUserGroupUsers(ctx, userGroupID)
 				if err != nil {
 					return "", fmt.Errorf("failed to get users for user group '%s': %w", invitee, err)
 				}
@@ -152,14 +157,17 @@ func (p *Provider) CreateChannel(opts provider.CreateChannelOptions) (string, er
 		}
 
 		if len(finalUserIDs) > 0 {
-			if err := p.inviteUsersToChannel(channelID, finalUserIDs); err != nil {
+			if err := p.inviteUsersToChannel(ctx, channelID, finalUserIDs); err != nil {
 				return "", fmt.Errorf("failed to invite users: %w", err)
 			}
 		}
 	}
 
 	// Repopulate the channel cache since we've made a change.
-	if err := p.populateChannelCache(); err != nil {
+	p.channelMu.Lock()
+	err = p.populateChannelCache(ctx)
+	p.channelMu.Unlock()
+	if err != nil {
 			if p.Context.Debug {
 				fmt.Fprintf(os.Stderr, "[DEBUG] Failed to repopulate channel cache after creation: %v\n", err)
 			}
@@ -0,0 +1,268 @@
+package slack
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/nlink-jp/scat/internal/export"
+	"github.com/nlink-jp/scat/internal/provider"
+)
+
+// downloadStore tracks the attachments downloaded during a run, so that a
+// file shared in several messages, or files with identical content, are
+// stored once. It is safe for concurrent use.
+type downloadStore struct {
+	mu     sync.Mutex
+	byID   map[string]*download // File ID to its download, done or in progress.
+	byHash map[string]string    // SHA-256 checksum to the local path of the content.
+	dirs   map[string]*filesDir // Files directory to what is recorded in it.
+}
+
+// filesDir holds what the checksum files of a files directory record.
+type filesDir struct {
+	sums  export.Checksums  // File name to checksum.
+	ids   export.Checksums  // File ID to the checksum of its content.
+	names map[string]string // Checksum to the name of a file with the content.
+}
+
+// download is the outcome of downloading a file.
+type download struct {
+	done   chan struct{}
+	path   string
+	sha256 string
+	size   int64
+	err    error
+}
+
+// downloadFile downloads the content of f into dir, unless this run has
+// already stored the file or identical content, or dir holds a copy whose
+// checksum matches the one recorded for it. The content is streamed to a
+// temporary file, which is renamed once it is complete.
+func (p *Provider) downloadFile(ctx context.Context, f file, dir string) *download {
+	s := &p.downloads
+	s.mu.Lock()
+	if d, ok := s.byID[f.ID]; ok {
+		s.mu.Unlock()
+		select {
+		case <-d.done:
+		case <-ctx.Done():
+			return &download{err: ctx.Err()}
+		}
+		return d
+	}
+	if s.byID == nil {
+		s.byID = make(map[string]*download)
+		s.byHash = make(map[string]string)
+		s.dirs = make(map[string]*filesDir)
+	}
+	d := &download{done: make(chan struct{})}
+	s.byID[f.ID] = d
+	s.mu.Unlock()
+
+	d.path, d.sha256, d.size, d.err = p.storeFile(ctx, f, dir)
+	if d.err != nil {
+		// A later message sharing the file may try again.
+		s.mu.Lock()
+		delete(s.byID, f.ID)
+		s.mu.Unlock()
+	}
+	close(d.done)
+	return d
+}
+
+// storeFile downloads f into dir, or finds its content there. Content found
+// in dir is used if its checksum matches the one recorded for it.
+func (p *Provider) storeFile(ctx context.Context, f file, dir string) (string, string, int64, error) {
+	s := &p.downloads
+	name := localFileName(f)
+
+	fd, err := s.filesDir(dir)
+	if err != nil {
+		return "", "", 0, err
+	}
+	s.mu.Lock()
+	want, existingName := fd.sums[name], name
+	if want == "" {
+		// The file may have been stored under the name of a file with
+		// identical content.
+		want = fd.ids[f.ID]
+		existingName = fd.names[want]
+	}
+	existing, verified := s.byHash[want]
+	s.mu.Unlock()
+	if want != "" && verified {
+		if info, err := os.Stat(existing); err == nil {
+			return existing, want, info.Size(), nil
+		}
+	}
+	if want != "" && existingName != "" {
+		existing = filepath.Join(dir, existingName)
+		if sum, size, err := export.FileChecksum(existing); err == nil && sum == want {
+			s.mu.Lock()
+			s.byHash[sum] = existing
+			s.mu.Unlock()
+			return existing, sum, size, nil
+		}
+	}
+
+	tmpPath, sum, size, err := p.fetchToTemp(ctx, f.URLPrivateDownload, dir)
+	if err != nil {
+		return "", "", 0, err
+	}
+	defer os.Remove(tmpPath)
+	if f.Size > 0 && size != f.Size {
+		// Slack answers with a login page instead of the file if the token
+		// lacks access to it.
+		return "", "", 0, fmt.Errorf("downloaded %d bytes, but the file has %d", size, f.Size)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	localPath, ok := s.byHash[sum]
+	if !ok {
+		localPath = filepath.Join(dir, name)
+		if err := os.Rename(tmpPath, localPath); err != nil {
+			return "", "", 0, fmt.Errorf("failed to save file: %w", err)
+		}
+		s.byHash[sum] = localPath
+		fd.sums[name] = sum
+		fd.names[sum] = name
+		if err := export.AppendChecksum(dir, name, sum); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: could not record the checksum of %s: %v\n", localPath, err)
+		}
+	}
+	if fd.ids[f.ID] != sum {
+		fd.ids[f.ID] = sum
+		if err := export.AppendFileID(dir, f.ID, sum); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: could not record the checksum of file %s: %v\n", f.ID, err)
+		}
+	}
+	return localPath, sum, size, nil
+}
+
+// filesDir returns what the checksum files in dir record, loading them on
+// first use.
+func (s *downloadStore) filesDir(dir string) (*filesDir, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if fd, ok := s.dirs[dir]; ok {
+		return fd, nil
+	}
+	sums, err := export.LoadChecksums(dir)
+	if err != nil {
+		return nil, err
+	}
+	ids, err := export.LoadFileIDs(dir)
+	if err != nil {
+		return nil, err
+	}
+	fd := &filesDir{sums: sums, ids: ids, names: make(map[string]string, len(sums))}
+	for name, sum := range sums {
+		fd.names[sum] = name
+	}
+	s.dirs[dir] = fd
+	return fd, nil
+}
+
+// localFileName returns the name a downloaded file is saved under: its ID
+// and its base name, without characters that cannot be listed in a checksum
+// file.
+func localFileName(f file) string {
+	name := filepath.Base(f.Name)
+	if name == "." || name == string(filepath.Separator) {
+		name = "file"
+	}
+	name = strings.Map(func(c rune) rune {
+		if c == '\n' || c == '\r' {
+			return '_'
+		}
+		return c
+	}, name)
+	return f.ID + "_" + name
+}
+
+// fetchToTemp downloads url into a new temporary file in dir and returns its
+// path, SHA-256 checksum, and size. Like API calls, downloads honor
+// Retry-After and are retried after transient failures.
+func (p *Provider) fetchToTemp(ctx context.Context, url, dir string) (string, string, int64, error) {
+	var tmpPath, sum string
+	var size int64
+	err := p.withRetry(ctx, "file download", func() error {
+		var err error
+		tmpPath, sum, size, err = p.fetchOnce(ctx, url, dir)
+		return err
+	})
+	return tmpPath, sum, size, err
+}
+
+// fetchOnce performs a single attempt of a download, waiting out any HTTP 429
+// responses along the way.
+func (p *Provider) fetchOnce(ctx context.Context, url, dir string) (string, string, int64, error) {
+	for rateLimited := 0; ; rateLimited++ {
+		if err := p.limiter.wait(ctx, "", p.Context.Debug); err != nil {
+			return "", "", 0, err
+		}
+		req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+		if err != nil {
+			return "", "", 0, fmt.Errorf("failed to create request: %w", err)
+		}
+		req.Header.Set("Authorization", "Bearer "+p.Profile.Token)
+		resp, err := p.httpClient.Do(req)
+		if err != nil {
+			return "", "", 0, networkError(ctx, fmt.Errorf("failed to send request: %w", err))
+		}
+
+		if resp.StatusCode == http.StatusTooManyRequests {
+			resp.Body.Close()
+			retryAfter := parseRetryAfter(resp.Header.Get("Retry-After"))
+			if rateLimited >= maxRateLimitRetries {
+				return "", "", 0, fmt.Errorf("%w on file download: giving up after %d retries", provider.ErrRateLimited, rateLimited)
+			}
+			p.limiter.block("", retryAfter)
+			continue
+		}
+		if resp.StatusCode >= 400 {
+			resp.Body.Close()
+			err := fmt.Errorf("download failed with status code %d", resp.StatusCode)
+			if resp.StatusCode >= 500 {
+				return "", "", 0, &transientError{err}
+			}
+			return "", "", 0, err
+		}
+
+		tmp, err := os.CreateTemp(dir, ".download-*")
+		if err != nil {
+			resp.Body.Close()
+			return "", "", 0, fmt.Errorf("failed to create file: %w", err)
+		}
+		sum, size, err := copyWithChecksum(tmp, resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			os.Remove(tmp.Name())
+			return "", "", 0, networkError(ctx, fmt.Errorf("failed to download file: %w", err))
+		}
+		return tmp.Name(), sum, size, nil
+	}
+}
+
+// copyWithChecksum copies r into f, computing the SHA-256 checksum of the
+// content on the way, and closes f.
+func copyWithChecksum(f *os.File, r io.Reader) (string, int64, error) {
+	h := sha256.New()
+	size, err := io.Copy(io.MultiWriter(f, h), r)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return "", 0, err
+	}
+	return hex.EncodeToString(h.Sum(nil)), size, nil
+}
@@ -0,0 +1,46 @@
+package slack
+
+import (
+	"errors"
+
+	"github.com/nlink-jp/scat/internal/provider"
+)
+
+// errorCodes maps Slack Web API error codes to the provider's typed errors.
+// Codes not listed here are reported as a *provider.APIError with a nil Err.
+var errorCodes = map[string]error{
+	"not_in_channel":               provider.ErrNotInChannel,
+	"channel_not_found":            provider.ErrChannelNotFound,
+	"user_not_found":               provider.ErrUserNotFound,
+	"users_not_found":              provider.ErrUserNotFound,
+	"message_not_found":            provider.ErrMessageNotFound,
+	"invalid_scheduled_message_id": provider.ErrMessageNotFound,
+	"ratelimited":                  provider.ErrRateLimited,
+	"not_authed":                   provider.ErrAuth,
+	"invalid_auth":                 provider.ErrAuth,
+	"account_inactive":             provider.ErrAuth,
+	"token_revoked":                provider.ErrAuth,
+	"token_expired":                provider.ErrAuth,
+}
+
+// isAPIErrorCode reports whether err is a Slack API error with the given code.
+func isAPIErrorCode(err error, code string) bool {
+	var apiErr *provider.APIError
+	return errors.As(err, &apiErr) && apiErr.Code == code
+}
+
+// newAPIError converts an `ok: false` response from method into a typed error.
+func newAPIError(method string, resp apiResponse) error {
+	var classified error
+	if resp.Error == "missing_scope" {
+		classified = &provider.MissingScopeError{Needed: resp.Needed, Provided: resp.Provided}
+	} else {
+		classified = errorCodes[resp.Error]
+	}
+	return &provider.APIError{
+		Provider: "slack",
+		Method:   method,
+		Code:     resp.Error,
+		Err:      classified,
+	}
+}
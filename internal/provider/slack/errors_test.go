@@ -0,0 +1,119 @@
+package slack
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/nlink-jp/scat/internal/provider"
+)
+
+func TestSendRequest_TypedErrors(t *testing.T) {
+	tests := []struct {
+		code   string
+		target error
+	}{
+		{"not_in_channel", provider.ErrNotInChannel},
+		{"channel_not_found", provider.ErrChannelNotFound},
+		{"users_not_found", provider.ErrUserNotFound},
+		{"invalid_auth", provider.ErrAuth},
+		{"token_revoked", provider.ErrAuth},
+		{"ratelimited", provider.ErrRateLimited},
+		{"missing_scope", provider.ErrMissingScope},
+	}
+	for _, tt := range tests {
+		t.Run(tt.code, func(t *testing.T) {
+			mux := http.NewServeMux()
+			mux.HandleFunc("/api/chat.postMessage", func(w http.ResponseWriter, r *http.Request) {
+				fmt.Fprintf(w, `{"ok": false, "error": %q, "needed": "chat:write", "provided": "channels:read"}`, tt.code)
+			})
+			server := httptest.NewServer(mux)
+			defer server.Close()
+
+			p := newTestProvider(server, "general")
+			_, err := p.sendRequest(context.Background(), "POST", postMessageURL, strings.NewReader(`{}`), "application/json; charset=utf-8")
+			if !errors.Is(err, tt.target) {
+				t.Fatalf("errors.Is(%v, %v) = false, want true", err, tt.target)
+			}
+			var apiErr *provider.APIError
+			if !errors.As(err, &apiErr) {
+				t.Fatalf("errors.As(%v, *provider.APIError) = false, want true", err)
+			}
+			if apiErr.Method != "chat.postMessage" || apiErr.Code != tt.code {
+				t.Errorf("got method %q, code %q; want chat.postMessage, %q", apiErr.Method, apiErr.Code, tt.code)
+			}
+		})
+	}
+}
+
+func TestSendRequest_MissingScopeCarriesNeededScope(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/conversations.history", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"ok": false, "error": "missing_scope", "needed": "channels:history", "provided": "chat:write"}`))
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	p := newTestProvider(server, "general")
+	_, err := p.sendRequest(context.Background(), "GET", conversationsHistoryURL, nil, "")
+	var scopeErr *provider.MissingScopeError
+	if !errors.As(err, &scopeErr) {
+		t.Fatalf("errors.As(%v, *provider.MissingScopeError) = false, want true", err)
+	}
+	if scopeErr.Needed != "channels:history" || scopeErr.Provided != "chat:write" {
+		t.Errorf("got needed %q, provided %q", scopeErr.Needed, scopeErr.Provided)
+	}
+	if !strings.Contains(err.Error(), "needed: channels:history") {
+		t.Errorf("error message should mention the needed scope, got: %v", err)
+	}
+}
+
+func TestSendRequest_UnknownErrorCode(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/chat.postMessage", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"ok": false, "error": "msg_too_long"}`))
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	p := newTestProvider(server, "general")
+	_, err := p.sendRequest(context.Background(), "POST", postMessageURL, strings.NewReader(`{}`), "application/json; charset=utf-8")
+	if err == nil || err.Error() != "slack API error on chat.postMessage: msg_too_long" {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	if errors.Is(err, provider.ErrNotInChannel) || errors.Is(err, provider.ErrAuth) {
+		t.Errorf("unknown error code should not match a typed error: %v", err)
+	}
+}
+
+func TestPostMessage_JoinsWhenNotInChannel(t *testing.T) {
+	posts, joins := 0, 0
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/chat.postMessage", func(w http.ResponseWriter, r *http.Request) {
+		posts++
+		if joins == 0 {
+			_, _ = w.Write([]byte(`{"ok": false, "error": "not_in_channel"}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"ok": true}`))
+	})
+	mux.HandleFunc("/api/conversations.join", func(w http.ResponseWriter, r *http.Request) {
+		joins++
+		_, _ = w.Write([]byte(`{"ok": true}`))
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	p := newTestProvider(server, "general")
+	p.Context.Silent = true
+	if _, err := p.PostMessage(context.Background(), provider.PostMessageOptions{TargetChannel: "general", Text: "hello"}); err != nil {
+		t.Fatalf("PostMessage() returned an unexpected error: %v", err)
+	}
+	if posts != 2 || joins != 1 {
+		t.Errorf("got %d posts and %d joins, want 2 and 1", posts, joins)
+	}
+}
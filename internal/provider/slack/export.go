@@ -1,10 +1,9 @@
 package slack
 
 import (
+	"context"
 	"fmt"
 	"os"
-	"path/filepath"
-	"sort"
 	"sync"
 	"time"
 
@@ -12,79 +11,154 @@ import (
 	"github.com/nlink-jp/scat/internal/util"
 )
 
-// ExportLog performs the entire export operation for Slack.
-func (p *Provider) ExportLog(opts export.Options) (*export.ExportedLog, error) {
-	var exportedMessages []export.ExportedMessage
-	userCache := make(map[string]string)
-	var userCacheMux sync.Mutex
+// ExportLog performs the entire export operation for Slack. Each page of
+// history is handed to onPage as soon as it has been processed, so that only
+// one page is held in memory.
+func (p *Provider) ExportLog(ctx context.Context, opts export.Options, onPage export.PageFunc) (*export.LogInfo, error) {
+	exportTimestamp := time.Now().UTC().Format(time.RFC3339)
 
-	channelID, err := p.ResolveChannelID(opts.ChannelName)
+	channelID, err := p.ResolveChannelID(ctx, opts.ChannelName)
 	if err != nil {
 		return nil, fmt.Errorf("failed to resolve channel ID for \"%s\": %w", opts.ChannelName, err)
 	}
 
 	// Fetch main channel messages and process threads
-	historyCursor := ""
+	historyCursor := opts.Cursor
 	for {
-		historyResp, err := p.getConversationHistory(channelID, opts, historyCursor)
+		historyResp, err := p.getConversationHistory(ctx, channelID, opts, historyCursor)
 		if err != nil {
 			return nil, err
 		}
 
-		for _, msg := range historyResp.Messages {
-			// If the message has replies, fetch the entire thread.
-			// We process threads first to avoid adding the parent message twice.
-			if msg.ReplyCount > 0 {
-				threadMessages, err := p.fetchAllReplies(channelID, msg.Timestamp, userCache, &userCacheMux, opts)
-				if err != nil {
-					fmt.Fprintf(os.Stderr, "Warning: could not fetch replies for thread %s: %v\n", msg.Timestamp, err)
-					continue // Skip this thread on error
-				}
-				exportedMessages = append(exportedMessages, threadMessages...)
-			} else if msg.ThreadTimestamp == "" {
-				// This is a regular message (not a reply, not a thread parent).
-				exportedMsg, err := p.buildExportedMessage(msg, userCache, &userCacheMux, opts)
-				if err != nil {
-					fmt.Fprintf(os.Stderr, "Warning: could not process message %s: %v\n", msg.Timestamp, err)
-					continue
-				}
-				exportedMessages = append(exportedMessages, *exportedMsg)
-			}
-			// Note: Replies that are also broadcast to the channel are handled
-			// when their parent thread is processed. They are not processed here.
+		pageMessages, err := p.exportHistoryPage(ctx, channelID, historyResp.Messages, opts)
+		if err != nil {
+			return nil, err
 		}
 
-		if !historyResp.HasMore {
+		nextCursor := ""
+		if historyResp.HasMore {
+			nextCursor = historyResp.ResponseMetadata.NextCursor
+		}
+		export.SortMessages(pageMessages)
+		if err := onPage(export.Page{Messages: pageMessages, NextCursor: nextCursor}); err != nil {
+			return nil, err
+		}
+
+		if nextCursor == "" {
 			break
 		}
-		historyCursor = historyResp.ResponseMetadata.NextCursor
+		historyCursor = nextCursor
 	}
 
-	// Sort messages by timestamp ascending
-	sort.Slice(exportedMessages, func(i, j int) bool {
-		return exportedMessages[i].TimestampUnix < exportedMessages[j].TimestampUnix
-	})
-
-	return &export.ExportedLog{
-		ExportTimestamp: time.Now().UTC().Format(time.RFC3339),
+	return &export.LogInfo{
+		ExportTimestamp: exportTimestamp,
 		ChannelName:     opts.ChannelName,
-		Messages:        exportedMessages,
+		ChannelID:       channelID,
 	},
 	nil
 }
 
+// exportHistoryPage exports the messages of a page of channel history,
+// processing up to opts.Concurrency messages, and the replies to their
+// threads, in parallel. All workers share the provider's rate limiter and user
+// name cache. The result is in the order of the page, whatever order the
+// workers finish in.
+func (p *Provider) exportHistoryPage(ctx context.Context, channelID string, msgs []message, opts export.Options) ([]export.ExportedMessage, error) {
+	results := make([][]export.ExportedMessage, len(msgs))
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < min(max(opts.Concurrency, 1), len(msgs)); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				results[i] = p.exportHistoryMessage(ctx, channelID, msgs[i], opts)
+			}
+		}()
+	}
+	for i := range msgs {
+		// Stop promptly on cancellation instead of emitting a warning for
+		// every remaining message in the page.
+		if ctx.Err() != nil {
+			break
+		}
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	var pageMessages []export.ExportedMessage
+	for _, exported := range results {
+		pageMessages = append(pageMessages, exported...)
+	}
+	return pageMessages, nil
+}
+
+// exportHistoryMessage exports a top-level message of the channel history,
+// together with the replies to its thread. Failures are reported as warnings
+// and leave the message out.
+func (p *Provider) exportHistoryMessage(ctx context.Context, channelID string, msg message, opts export.Options) []export.ExportedMessage {
+	// Messages exported by an earlier run are only checked for new replies.
+	if opts.ExportedUntil != "" && msg.Timestamp <= opts.ExportedUntil {
+		known := opts.Threads[msg.Timestamp]
+		if msg.ReplyCount == 0 || (known != "" && msg.LatestReply <= known) {
+			return nil
+		}
+		if known == "" {
+			known = msg.Timestamp
+		}
+		newReplies, err := p.fetchAllReplies(ctx, channelID, msg.Timestamp, known, opts)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: could not fetch replies for thread %s: %v\n", msg.Timestamp, err)
+			return nil
+		}
+		return newReplies
+	}
+
+	// If the message has replies, fetch the entire thread.
+	// We process threads first to avoid adding the parent message twice.
+	if msg.ReplyCount > 0 {
+		threadMessages, err := p.fetchAllReplies(ctx, channelID, msg.Timestamp, "", opts)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: could not fetch replies for thread %s: %v\n", msg.Timestamp, err)
+			return nil // Skip this thread on error
+		}
+		return threadMessages
+	}
+	if msg.ThreadTimestamp == "" {
+		// This is a regular message (not a reply, not a thread parent).
+		exportedMsg, err := p.buildExportedMessage(ctx, msg, opts)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: could not process message %s: %v\n", msg.Timestamp, err)
+			return nil
+		}
+		return []export.ExportedMessage{*exportedMsg}
+	}
+	// Note: Replies that are also broadcast to the channel are handled
+	// when their parent thread is processed. They are not processed here.
+	return nil
+}
+
 // fetchAllReplies fetches all messages in a specific thread using pagination.
-func (p *Provider) fetchAllReplies(channelID, threadTS string, userCache map[string]string, userCacheMux *sync.Mutex, opts export.Options) ([]export.ExportedMessage, error) {
+// If oldest is set, only replies newer than oldest are returned.
+func (p *Provider) fetchAllReplies(ctx context.Context, channelID, threadTS, oldest string, opts export.Options) ([]export.ExportedMessage, error) {
 	var allReplies []export.ExportedMessage
 	repliesCursor := ""
 	for {
-		repliesResp, err := p.getConversationReplies(channelID, threadTS, repliesCursor)
+		repliesResp, err := p.getConversationReplies(ctx, channelID, threadTS, oldest, repliesCursor, opts.IncludeRaw)
 		if err != nil {
 			return nil, err
 		}
 
 		for _, msg := range repliesResp.Messages {
-			exportedMsg, err := p.buildExportedMessage(msg, userCache, userCacheMux, opts)
+			// Slack always includes the parent message.
+			if oldest != "" && (msg.Timestamp == threadTS || msg.Timestamp <= oldest) {
+				continue
+			}
+			exportedMsg, err := p.buildExportedMessage(ctx, msg, opts)
 			if err != nil {
 				fmt.Fprintf(os.Stderr, "Warning: could not process reply message %s: %v\n", msg.Timestamp, err)
 				continue
@@ -101,7 +175,7 @@ func (p *Provider) fetchAllReplies(channelID, threadTS string, userCache map[str
 }
 
 // buildExportedMessage constructs an ExportedMessage from a Slack message.
-func (p *Provider) buildExportedMessage(msg message, userCache map[string]string, userCacheMux *sync.Mutex, opts export.Options) (*export.ExportedMessage, error) {
+func (p *Provider) buildExportedMessage(ctx context.Context, msg message, opts export.Options) (*export.ExportedMessage, error) {
 	var userID, postType, userName string
 
 	if msg.SubType == "bot_message" {
@@ -113,7 +187,7 @@ func (p *Provider) buildExportedMessage(msg message, userCache map[string]string
 		postType = "bot"
 	} else {
 		var err error
-		userName, err = p.resolveUserName(msg.UserID, userCache, userCacheMux)
+		userName, err = p.resolveUserName(ctx, msg.UserID, &p.userNames)
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "Warning: could not resolve user %s: %v\n", msg.UserID, err)
 		}
@@ -121,12 +195,12 @@ func (p *Provider) buildExportedMessage(msg message, userCache map[string]string
 		postType = "user"
 	}
 
-	files, err := p.handleAttachedFiles(msg.Files, opts.OutputDir, opts.IncludeFiles)
+	files, err := p.handleAttachedFiles(ctx, msg.Files, opts.OutputDir, opts.IncludeFiles)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Warning: could not process files for message %s: %v\n", msg.Timestamp, err)
 	}
 
-	resolvedText, err := p.resolveMentions(msg.Text, userCache, userCacheMux)
+	resolvedText, err := p.resolveMentions(ctx, msg.Text, &p.userNames)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Warning: could not resolve mentions in message %s: %v\n", msg.Timestamp, err)
 		resolvedText = msg.Text
@@ -143,37 +217,71 @@ func (p *Provider) buildExportedMessage(msg message, userCache map[string]string
 		UserID:              userID,
 		UserName:            userName,
 		PostType:            postType,
+		Subtype:             msg.SubType,
 		Timestamp:           rfc3339Time,
 		TimestampUnix:       msg.Timestamp,
 		Text:                resolvedText,
 		Files:               files,
 		ThreadTimestampUnix: msg.ThreadTimestamp,
 		IsReply:             isReply,
+		ClientMsgID:         msg.ClientMsgID,
+		ReplyCount:          msg.ReplyCount,
+		ReplyUsers:          msg.ReplyUsers,
+		Blocks:              msg.Blocks,
+		Attachments:         msg.Attachments,
+	}
+	if msg.Edited != nil {
+		editedTime, err := util.ToRFC3339(msg.Edited.Timestamp)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: could not parse edit timestamp %s: %v\n", msg.Edited.Timestamp, err)
+		}
+		exportedMsg.Edited = &export.ExportedEdit{
+			UserID:        msg.Edited.User,
+			Timestamp:     editedTime,
+			TimestampUnix: msg.Edited.Timestamp,
+		}
+	}
+	for _, r := range msg.Reactions {
+		exportedMsg.Reactions = append(exportedMsg.Reactions, export.ExportedReaction{Name: r.Name, Count: r.Count, Users: r.Users})
+	}
+	if opts.IncludeRaw {
+		exportedMsg.Raw = msg.raw
 	}
 	return exportedMsg, nil
 }
 
-func (p *Provider) handleAttachedFiles(files []file, outputDir string, download bool) ([]export.ExportedFile, error) {
+// handleAttachedFiles converts the files of a message and, if download is
+// set, downloads them into outputDir. A failed download is reported as a
+// warning and recorded in the file's DownloadError.
+func (p *Provider) handleAttachedFiles(ctx context.Context, files []file, outputDir string, download bool) ([]export.ExportedFile, error) {
 	var exportedFiles []export.ExportedFile
 	for _, f := range files {
 		exportedFile := export.ExportedFile{
-			ID:       f.ID,
-			Name:     f.Name,
-			Mimetype: f.Mimetype,
+			ID:                 f.ID,
+			Name:               f.Name,
+			Title:              f.Title,
+			Mimetype:           f.Mimetype,
+			Filetype:           f.Filetype,
+			Size:               f.Size,
+			URLPrivate:         f.URLPrivate,
+			Permalink:          f.Permalink,
+			Created:            f.Created,
+			UserID:             f.User,
+			PrettyType:         f.PrettyType,
+			Mode:               f.Mode,
+			IsExternal:         f.IsExternal,
+			URLPrivateDownload: f.URLPrivateDownload,
 		}
 		if download && f.URLPrivateDownload != "" {
-			safeFilename := filepath.Base(f.Name)
-			localPath := filepath.Join(outputDir, f.ID+"_"+safeFilename)
-			fileData, err := p.sendRequest("GET", f.URLPrivateDownload, nil, "")
-			if err != nil {
-				fmt.Fprintf(os.Stderr, "Warning: could not download file %s: %v\n", f.Name, err)
-				continue
-			}
-			if err := os.WriteFile(localPath, fileData, 0600); err != nil {
-				fmt.Fprintf(os.Stderr, "Warning: could not save file %s: %v\n", f.Name, err)
-				continue
+			d := p.downloadFile(ctx, f, outputDir)
+			if d.err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: could not download file %s: %v\n", f.Name, d.err)
+				exportedFile.DownloadError = d.err.Error()
+			} else {
+				exportedFile.LocalPath = d.path
+				exportedFile.SHA256 = d.sha256
+				exportedFile.Size = d.size
 			}
-			exportedFile.LocalPath = localPath
 		}
 		exportedFiles = append(exportedFiles, exportedFile)
 	}
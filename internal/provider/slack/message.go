@@ -0,0 +1,89 @@
+package slack
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/nlink-jp/scat/internal/provider"
+)
+
+// updateMessagePayload is the payload for the chat.update API.
+type updateMessagePayload struct {
+	Channel   string          `json:"channel"`
+	Timestamp string          `json:"ts"`
+	Text      string          `json:"text,omitempty"`
+	Blocks    json.RawMessage `json:"blocks,omitempty"`
+}
+
+// deleteMessagePayload is the payload for the chat.delete API.
+type deleteMessagePayload struct {
+	Channel   string `json:"channel"`
+	Timestamp string `json:"ts"`
+}
+
+// messageChannelID resolves the channel of an existing message, falling back
+// to the profile's default channel when none is given.
+func (p *Provider) messageChannelID(ctx context.Context, channel string) (string, error) {
+	if channel == "" {
+		channel = p.Profile.Channel
+	}
+	if channel == "" {
+		return "", fmt.Errorf("no channel specified; please set a default channel in the profile, use the --channel flag, or pass a message permalink")
+	}
+	return p.ResolveChannelID(ctx, channel)
+}
+
+// UpdateMessage replaces the text or blocks of a posted message using chat.update.
+func (p *Provider) UpdateMessage(ctx context.Context, opts provider.UpdateMessageOptions) (*provider.PostResult, error) {
+	channelID, err := p.messageChannelID(ctx, opts.Channel)
+	if err != nil {
+		return nil, err
+	}
+
+	payload := updateMessagePayload{
+		Channel:   channelID,
+		Timestamp: opts.Timestamp,
+		Text:      opts.Text,
+		Blocks:    opts.Blocks,
+	}
+	jsonPayload, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal chat.update payload: %w", err)
+	}
+
+	respBody, err := p.sendRequest(ctx, "POST", chatUpdateURL, bytes.NewBuffer(jsonPayload), "application/json; charset=utf-8")
+	if err != nil {
+		return nil, err
+	}
+
+	var updateResp postMessageResponse
+	if err := json.Unmarshal(respBody, &updateResp); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal chat.update response: %w", err)
+	}
+
+	result := &provider.PostResult{ChannelID: updateResp.Channel, Timestamp: updateResp.Timestamp}
+	result.Permalink, err = p.getPermalink(ctx, result.ChannelID, result.Timestamp)
+	if err != nil && p.Context.Debug {
+		fmt.Fprintf(os.Stderr, "[DEBUG] Could not get permalink for message %s: %v\n", result.Timestamp, err)
+	}
+	return result, nil
+}
+
+// DeleteMessage deletes a posted message using chat.delete.
+func (p *Provider) DeleteMessage(ctx context.Context, opts provider.DeleteMessageOptions) error {
+	channelID, err := p.messageChannelID(ctx, opts.Channel)
+	if err != nil {
+		return err
+	}
+
+	jsonPayload, err := json.Marshal(deleteMessagePayload{Channel: channelID, Timestamp: opts.Timestamp})
+	if err != nil {
+		return fmt.Errorf("failed to marshal chat.delete payload: %w", err)
+	}
+
+	_, err = p.sendRequest(ctx, "POST", chatDeleteURL, bytes.NewBuffer(jsonPayload), "application/json; charset=utf-8")
+	return err
+}
@@ -0,0 +1,77 @@
+package slack
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/nlink-jp/scat/internal/provider"
+)
+
+func TestUpdateMessage(t *testing.T) {
+	var payload updateMessagePayload
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/chat.update", func(w http.ResponseWriter, r *http.Request) {
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			t.Errorf("Failed to decode request body: %v", err)
+		}
+		_, _ = w.Write([]byte(`{"ok": true, "channel": "C01TEST", "ts": "1700000000.000100", "text": "deployed"}`))
+	})
+	mux.HandleFunc("/api/chat.getPermalink", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"ok": true, "permalink": "https://example.slack.com/archives/C01TEST/p1700000000000100"}`))
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	p := newTestProvider(server, "general")
+	result, err := p.UpdateMessage(context.Background(), provider.UpdateMessageOptions{Timestamp: "1700000000.000100", Text: "deployed"})
+	if err != nil {
+		t.Fatalf("UpdateMessage() returned an unexpected error: %v", err)
+	}
+	if payload.Channel != "C01TEST" || payload.Timestamp != "1700000000.000100" || payload.Text != "deployed" {
+		t.Errorf("Unexpected chat.update payload: %+v", payload)
+	}
+	want := provider.PostResult{ChannelID: "C01TEST", Timestamp: "1700000000.000100", Permalink: "https://example.slack.com/archives/C01TEST/p1700000000000100"}
+	if result.ChannelID != want.ChannelID || result.Timestamp != want.Timestamp || result.Permalink != want.Permalink {
+		t.Errorf("UpdateMessage() = %+v, want %+v", result, want)
+	}
+}
+
+func TestDeleteMessage(t *testing.T) {
+	var payload deleteMessagePayload
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/chat.delete", func(w http.ResponseWriter, r *http.Request) {
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			t.Errorf("Failed to decode request body: %v", err)
+		}
+		_, _ = w.Write([]byte(`{"ok": true, "channel": "C01TEST", "ts": "1700000000.000100"}`))
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	p := newTestProvider(server, "general")
+	if err := p.DeleteMessage(context.Background(), provider.DeleteMessageOptions{Channel: "#general", Timestamp: "1700000000.000100"}); err != nil {
+		t.Fatalf("DeleteMessage() returned an unexpected error: %v", err)
+	}
+	if payload.Channel != "C01TEST" || payload.Timestamp != "1700000000.000100" {
+		t.Errorf("Unexpected chat.delete payload: %+v", payload)
+	}
+}
+
+func TestDeleteMessage_NotFound(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/chat.delete", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"ok": false, "error": "message_not_found"}`))
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	p := newTestProvider(server, "general")
+	err := p.DeleteMessage(context.Background(), provider.DeleteMessageOptions{Timestamp: "1700000000.000100"})
+	if !errors.Is(err, provider.ErrMessageNotFound) {
+		t.Errorf("DeleteMessage() error = %v, want provider.ErrMessageNotFound", err)
+	}
+}
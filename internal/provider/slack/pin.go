@@ -0,0 +1,117 @@
+package slack
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"time"
+
+	"github.com/nlink-jp/scat/internal/provider"
+)
+
+// pinPayload is the payload for the pins.add and pins.remove APIs.
+type pinPayload struct {
+	Channel   string `json:"channel"`
+	Timestamp string `json:"timestamp"`
+}
+
+// pinsListResponse defines the structure for the pins.list API response.
+type pinsListResponse struct {
+	Ok    bool   `json:"ok"`
+	Error string `json:"error"`
+	Items []struct {
+		Type      string `json:"type"`
+		Created   int64  `json:"created"`
+		CreatedBy string `json:"created_by"`
+		Channel   string `json:"channel"`
+		Message   struct {
+			User      string `json:"user"`
+			Text      string `json:"text"`
+			Timestamp string `json:"ts"`
+			Permalink string `json:"permalink"`
+		} `json:"message"`
+	} `json:"items"`
+}
+
+// AddPin pins a message using pins.add.
+// Pinning a message that is already pinned is not an error.
+func (p *Provider) AddPin(ctx context.Context, opts provider.PinOptions) error {
+	return p.sendPin(ctx, pinsAddURL, "already_pinned", opts)
+}
+
+// RemovePin unpins a message using pins.remove.
+// Unpinning a message that is not pinned is not an error.
+func (p *Provider) RemovePin(ctx context.Context, opts provider.PinOptions) error {
+	return p.sendPin(ctx, pinsRemoveURL, "no_pin", opts)
+}
+
+// sendPin posts a pin change to apiURL, treating the error code noopCode as
+// success because the message is already in the desired state.
+func (p *Provider) sendPin(ctx context.Context, apiURL, noopCode string, opts provider.PinOptions) error {
+	channelID, err := p.messageChannelID(ctx, opts.Channel)
+	if err != nil {
+		return err
+	}
+
+	jsonPayload, err := json.Marshal(pinPayload{Channel: channelID, Timestamp: opts.Timestamp})
+	if err != nil {
+		return fmt.Errorf("failed to marshal pin payload: %w", err)
+	}
+
+	_, err = p.sendRequest(ctx, "POST", apiURL, bytes.NewBuffer(jsonPayload), "application/json; charset=utf-8")
+	if isAPIErrorCode(err, noopCode) {
+		if p.Context.Debug {
+			fmt.Fprintf(os.Stderr, "[DEBUG] Ignoring %s for message %s.\n", noopCode, opts.Timestamp)
+		}
+		return nil
+	}
+	return err
+}
+
+// ListPins lists the messages pinned to a channel using pins.list.
+// Pinned files from Slack's legacy file pinning are skipped.
+func (p *Provider) ListPins(ctx context.Context, opts provider.ListPinsOptions) ([]provider.PinnedMessage, error) {
+	channelID, err := p.messageChannelID(ctx, opts.Channel)
+	if err != nil {
+		return nil, err
+	}
+
+	params := url.Values{}
+	params.Add("channel", channelID)
+
+	respBody, err := p.sendRequest(ctx, "GET", pinsListURL+"?"+params.Encode(), nil, "")
+	if err != nil {
+		return nil, err
+	}
+
+	var listResp pinsListResponse
+	if err := json.Unmarshal(respBody, &listResp); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal pins.list response: %w", err)
+	}
+
+	pins := make([]provider.PinnedMessage, 0, len(listResp.Items))
+	for _, item := range listResp.Items {
+		if item.Type != "message" {
+			continue
+		}
+		pin := provider.PinnedMessage{
+			ChannelID: item.Channel,
+			Timestamp: item.Message.Timestamp,
+			UserID:    item.Message.User,
+			Text:      item.Message.Text,
+			Permalink: item.Message.Permalink,
+			PinnedBy:  item.CreatedBy,
+		}
+		if pin.ChannelID == "" {
+			pin.ChannelID = channelID
+		}
+		if item.Created > 0 {
+			pin.PinnedAt = time.Unix(item.Created, 0)
+		}
+		pins = append(pins, pin)
+	}
+	return pins, nil
+}
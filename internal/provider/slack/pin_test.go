@@ -0,0 +1,79 @@
+package slack
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/nlink-jp/scat/internal/provider"
+)
+
+func TestAddPin(t *testing.T) {
+	var payloads []pinPayload
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/pins.add", func(w http.ResponseWriter, r *http.Request) {
+		var payload pinPayload
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			t.Errorf("Failed to decode request body: %v", err)
+		}
+		payloads = append(payloads, payload)
+		if len(payloads) > 1 {
+			_, _ = w.Write([]byte(`{"ok": false, "error": "already_pinned"}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"ok": true}`))
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	p := newTestProvider(server, "general")
+	opts := provider.PinOptions{Timestamp: "1700000000.000100"}
+	if err := p.AddPin(context.Background(), opts); err != nil {
+		t.Fatalf("AddPin() returned an unexpected error: %v", err)
+	}
+	if payloads[0] != (pinPayload{Channel: "C01TEST", Timestamp: "1700000000.000100"}) {
+		t.Errorf("Unexpected pins.add payload: %+v", payloads[0])
+	}
+
+	// Pinning the same message again is not an error.
+	if err := p.AddPin(context.Background(), opts); err != nil {
+		t.Errorf("AddPin() on a pinned message returned an error: %v", err)
+	}
+}
+
+func TestListPins(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/pins.list", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("channel") != "C01TEST" {
+			t.Errorf("Unexpected pins.list query: %s", r.URL.RawQuery)
+		}
+		_, _ = w.Write([]byte(`{"ok": true, "items": [
+			{"type": "message", "created": 1700000100, "created_by": "U02", "channel": "C01TEST",
+			 "message": {"user": "U01", "text": "release notes", "ts": "1700000000.000100", "permalink": "https://example.slack.com/archives/C01TEST/p1700000000000100"}},
+			{"type": "file", "created": 1700000200, "created_by": "U02"}
+		]}`))
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	p := newTestProvider(server, "general")
+	pins, err := p.ListPins(context.Background(), provider.ListPinsOptions{})
+	if err != nil {
+		t.Fatalf("ListPins() returned an unexpected error: %v", err)
+	}
+	want := provider.PinnedMessage{
+		ChannelID: "C01TEST",
+		Timestamp: "1700000000.000100",
+		UserID:    "U01",
+		Text:      "release notes",
+		Permalink: "https://example.slack.com/archives/C01TEST/p1700000000000100",
+		PinnedBy:  "U02",
+		PinnedAt:  time.Unix(1700000100, 0),
+	}
+	if len(pins) != 1 || pins[0] != want {
+		t.Errorf("ListPins() = %+v, want [%+v]", pins, want)
+	}
+}
@@ -2,15 +2,16 @@ package slack
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
-	"strings"
 
 	"github.com/nlink-jp/scat/internal/provider"
 )
 
-func (p *Provider) PostMessage(opts provider.PostMessageOptions) error {
+func (p *Provider) PostMessage(ctx context.Context, opts provider.PostMessageOptions) (*provider.PostResult, error) {
 	if p.Context.Debug {
 		fmt.Fprintln(os.Stderr, "[DEBUG] PostMessage called with Debug mode ON.")
 	}
@@ -21,36 +22,30 @@ func (p *Provider) PostMessage(opts provider.PostMessageOptions) error {
 	switch {
 	case opts.TargetUserID != "":
 		destinationName = opts.TargetUserID
-		var userID string
-		// Check if the provided user string is a user ID or a mention name
-		if strings.HasPrefix(opts.TargetUserID, "U") || strings.HasPrefix(opts.TargetUserID, "W") {
-			userID = opts.TargetUserID
-		} else {
-			userID, err = p.ResolveUserID(opts.TargetUserID)
-			if err != nil {
-				return err
-			}
+		userID, err := p.userIDFor(ctx, opts.TargetUserID)
+		if err != nil {
+			return nil, err
 		}
-		channelID, err = p.openDMChannel(userID)
+		channelID, err = p.openDMChannel(ctx, userID)
 		if err != nil {
-			return fmt.Errorf("failed to open DM channel with user %s: %w", opts.TargetUserID, err)
+			return nil, fmt.Errorf("failed to open DM channel with user %s: %w", opts.TargetUserID, err)
 		}
 
 	case opts.TargetChannel != "":
 		destinationName = opts.TargetChannel
-		channelID, err = p.ResolveChannelID(opts.TargetChannel)
+		channelID, err = p.ResolveChannelID(ctx, opts.TargetChannel)
 		if err != nil {
-			return err
+			return nil, err
 		}
 
 	default:
 		destinationName = p.Profile.Channel
 		if destinationName == "" {
-			return fmt.Errorf("no channel or user specified; please set a default channel in the profile or use the --channel or --user flag")
+			return nil, fmt.Errorf("no channel or user specified; please set a default channel in the profile or use the --channel or --user flag")
 		}
-		channelID, err = p.ResolveChannelID(p.Profile.Channel)
+		channelID, err = p.ResolveChannelID(ctx, p.Profile.Channel)
 		if err != nil {
-			return err
+			return nil, err
 		}
 	}
 
@@ -60,38 +55,86 @@ func (p *Provider) PostMessage(opts provider.PostMessageOptions) error {
 	}
 
 	payload := messagePayload{
-		Channel:   channelID,
-		Text:      opts.Text,
-		Username:  username,
-		IconEmoji: opts.IconEmoji,
-		Blocks:    opts.Blocks,
+		Channel:        channelID,
+		Text:           opts.Text,
+		Username:       username,
+		IconEmoji:      opts.IconEmoji,
+		Blocks:         opts.Blocks,
+		ThreadTS:       opts.ThreadTS,
+		ReplyBroadcast: opts.ReplyBroadcast,
+	}
+
+	// A message with PostAt is handed to chat.scheduleMessage instead, which
+	// accepts the same payload plus the posting time.
+	apiURL := postMessageURL
+	if !opts.PostAt.IsZero() {
+		apiURL = scheduleMessageURL
+		payload.PostAt = opts.PostAt.Unix()
+	}
+
+	// An ephemeral message is sent through chat.postEphemeral, which takes the
+	// recipient in addition to the channel.
+	if opts.EphemeralUserID != "" {
+		if opts.TargetUserID != "" || !opts.PostAt.IsZero() {
+			return nil, fmt.Errorf("ephemeral messages cannot be sent as direct messages or scheduled")
+		}
+		payload.User, err = p.userIDFor(ctx, opts.EphemeralUserID)
+		if err != nil {
+			return nil, err
+		}
+		apiURL = postEphemeralURL
 	}
 
 	jsonPayload, err := json.Marshal(payload)
 	if err != nil {
-		return fmt.Errorf("failed to marshal slack payload: %w", err)
+		return nil, fmt.Errorf("failed to marshal slack payload: %w", err)
 	}
 
 	// Attempt to post message
-	_, err = p.sendRequest("POST", postMessageURL, bytes.NewBuffer(jsonPayload), "application/json; charset=utf-8")
+	respBody, err := p.sendRequest(ctx, "POST", apiURL, bytes.NewBuffer(jsonPayload), "application/json; charset=utf-8")
 	if err != nil {
 		// Check if the error is 'not_in_channel' (only applicable to channels, not DMs)
-		if opts.TargetUserID == "" && strings.Contains(err.Error(), "not_in_channel") {
+		if opts.TargetUserID == "" && errors.Is(err, provider.ErrNotInChannel) {
 			if !p.Context.Silent {
 				fmt.Fprintf(os.Stderr, "Bot not in channel \"%s\". Attempting to join...\n", destinationName)
 			}
-			if joinErr := p.joinChannel(channelID); joinErr != nil {
-				return fmt.Errorf("failed to join channel \"%s\": %w", destinationName, joinErr)
+			if joinErr := p.joinChannel(ctx, channelID); joinErr != nil {
+				return nil, fmt.Errorf("failed to join channel \"%s\": %w", destinationName, joinErr)
 			}
 			if !p.Context.Silent {
 				fmt.Fprintf(os.Stderr, "Successfully joined channel \"%s\". Retrying post...\n", destinationName)
 			}
 			// Retry post after joining
-			_, retryErr := p.sendRequest("POST", postMessageURL, bytes.NewBuffer(jsonPayload), "application/json; charset=utf-8")
-			return retryErr
+			respBody, err = p.sendRequest(ctx, "POST", apiURL, bytes.NewBuffer(jsonPayload), "application/json; charset=utf-8")
+		}
+		if err != nil {
+			return nil, err
 		}
-		return err // Return original error if not applicable for retry
 	}
 
-	return nil
-}
\ No newline at end of file
+	if !opts.PostAt.IsZero() {
+		return scheduleResult(respBody, channelID)
+	}
+	if opts.EphemeralUserID != "" {
+		// Ephemeral messages have no permalink and cannot be referenced later.
+		return &provider.PostResult{ChannelID: channelID}, nil
+	}
+
+	var postResp postMessageResponse
+	if err := json.Unmarshal(respBody, &postResp); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal chat.postMessage response: %w", err)
+	}
+
+	result := &provider.PostResult{ChannelID: postResp.Channel, Timestamp: postResp.Timestamp}
+	if result.ChannelID == "" {
+		result.ChannelID = channelID
+	}
+	if result.Timestamp != "" {
+		// The message has been posted, so a failed lookup only leaves the permalink empty.
+		result.Permalink, err = p.getPermalink(ctx, result.ChannelID, result.Timestamp)
+		if err != nil && p.Context.Debug {
+			fmt.Fprintf(os.Stderr, "[DEBUG] Could not get permalink for message %s: %v\n", result.Timestamp, err)
+		}
+	}
+	return result, nil
+}
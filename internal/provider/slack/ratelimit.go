@@ -0,0 +1,230 @@
+package slack
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// This file implements client-side pacing of Slack Web API calls.
+//
+// Slack assigns every Web API method to a rate limit tier. Requests are paced
+// per method with a token bucket sized for the method's tier, so bulk
+// operations such as exporting a busy channel stay below the limits instead
+// of relying on HTTP 429 responses. When Slack does answer with 429, the
+// Retry-After header is honored for that method before the call is retried.
+
+// rateTier describes a Slack API rate limit tier.
+type rateTier struct {
+	name      string
+	perMinute int // Sustained requests per minute.
+	burst     int // Requests that may be sent back-to-back before pacing kicks in.
+}
+
+var (
+	tier2 = rateTier{name: "Tier 2", perMinute: 20, burst: 5}
+	tier3 = rateTier{name: "Tier 3", perMinute: 50, burst: 10}
+	tier4 = rateTier{name: "Tier 4", perMinute: 100, burst: 20}
+	// tierPost covers chat.postMessage, which Slack limits to roughly one
+	// message per second with short bursts allowed.
+	tierPost = rateTier{name: "Special (chat.postMessage)", perMinute: 60, burst: 3}
+	// tierNone is used for URLs that are not Web API methods, such as file
+	// downloads. Such requests are not paced, but Retry-After is still honored.
+	tierNone = rateTier{name: "Unpaced"}
+)
+
+// methodTiers maps Web API method names to their documented rate limit tier.
+// Methods not listed here fall back to Tier 3.
+var methodTiers = map[string]rateTier{
+	"chat.delete":                  tier3,
+	"chat.deleteScheduledMessage":  tier3,
+	"chat.getPermalink":            tier4,
+	"chat.postEphemeral":           tierPost,
+	"chat.postMessage":             tierPost,
+	"chat.scheduleMessage":         tier3,
+	"chat.scheduledMessages.list":  tier3,
+	"chat.update":                  tier3,
+	"conversations.create":         tier2,
+	"conversations.history":        tier3,
+	"conversations.invite":         tier3,
+	"conversations.join":           tier3,
+	"conversations.list":           tier2,
+	"conversations.open":           tier3,
+	"conversations.replies":        tier3,
+	"files.completeUploadExternal": tier4,
+	"files.info":                   tier4,
+	"files.getUploadURLExternal":   tier4,
+	"pins.add":                     tier2,
+	"pins.list":                    tier2,
+	"pins.remove":                  tier2,
+	"reactions.add":                tier3,
+	"reactions.get":                tier3,
+	"reactions.remove":             tier2,
+	"usergroups.list":              tier2,
+	"usergroups.users.list":        tier2,
+	"users.info":                   tier4,
+	"users.list":                   tier2,
+}
+
+const (
+	// defaultRetryAfter is used when a 429 response carries no usable Retry-After header.
+	defaultRetryAfter = 5 * time.Second
+	// maxRateLimitRetries is the number of times a request is retried after HTTP 429.
+	maxRateLimitRetries = 5
+)
+
+// tierFor returns the rate limit tier for a Web API method name.
+// An empty method name denotes a non-API URL.
+func tierFor(method string) rateTier {
+	if method == "" {
+		return tierNone
+	}
+	if t, ok := methodTiers[method]; ok {
+		return t
+	}
+	return tier3
+}
+
+// apiMethodFromURL extracts the Web API method name (e.g. "conversations.history")
+// from a request URL. It returns an empty string for URLs outside /api/.
+func apiMethodFromURL(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return ""
+	}
+	method, ok := strings.CutPrefix(u.Path, "/api/")
+	if !ok || method == "" || strings.Contains(method, "/") {
+		return ""
+	}
+	return method
+}
+
+// parseRetryAfter interprets a Retry-After header value given in seconds.
+func parseRetryAfter(value string) time.Duration {
+	secs, err := strconv.Atoi(strings.TrimSpace(value))
+	if err != nil || secs < 0 {
+		return defaultRetryAfter
+	}
+	return time.Duration(secs) * time.Second
+}
+
+// tokenBucket tracks the pacing state of a single API method.
+type tokenBucket struct {
+	tokens       float64
+	last         time.Time
+	blockedUntil time.Time
+}
+
+// rateLimiter paces requests per Web API method. It is safe for concurrent use.
+type rateLimiter struct {
+	mu      sync.Mutex
+	buckets map[string]*tokenBucket
+
+	// now and sleep are replaceable for testing.
+	now   func() time.Time
+	sleep func(ctx context.Context, d time.Duration) error
+}
+
+// newRateLimiter creates a rateLimiter using the real clock.
+func newRateLimiter() *rateLimiter {
+	return &rateLimiter{
+		buckets: make(map[string]*tokenBucket),
+		now:     time.Now,
+		sleep:   sleepContext,
+	}
+}
+
+// sleepContext waits for d or until ctx is done, whichever comes first.
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// reserve claims a slot for method and returns how long the caller must wait
+// before sending the request.
+func (l *rateLimiter) reserve(method string) time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	tier := tierFor(method)
+	b, ok := l.buckets[method]
+	if !ok {
+		b = &tokenBucket{tokens: float64(tier.burst), last: now}
+		l.buckets[method] = b
+	}
+
+	var delay time.Duration
+	if tier.perMinute > 0 {
+		rate := float64(tier.perMinute) / 60 // tokens per second
+		b.tokens += now.Sub(b.last).Seconds() * rate
+		if b.tokens > float64(tier.burst) {
+			b.tokens = float64(tier.burst)
+		}
+		b.last = now
+		b.tokens--
+		if b.tokens < 0 {
+			delay = time.Duration(-b.tokens / rate * float64(time.Second))
+		}
+	}
+	if blocked := b.blockedUntil.Sub(now); blocked > delay {
+		delay = blocked
+	}
+	return delay
+}
+
+// wait blocks until a request to method may be sent, or ctx is done.
+// A nil rateLimiter never waits.
+func (l *rateLimiter) wait(ctx context.Context, method string, debug bool) error {
+	if l == nil {
+		return ctx.Err()
+	}
+	delay := l.reserve(method)
+	if delay <= 0 {
+		return ctx.Err()
+	}
+	if debug {
+		fmt.Fprintf(os.Stderr, "[DEBUG] Rate limit: waiting %s before calling %s (%s)\n", delay.Round(time.Millisecond), displayMethod(method), tierFor(method).name)
+	}
+	return l.sleep(ctx, delay)
+}
+
+// block prevents further requests to method for d, typically after HTTP 429.
+func (l *rateLimiter) block(method string, d time.Duration) {
+	if l == nil {
+		return
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	b, ok := l.buckets[method]
+	if !ok {
+		b = &tokenBucket{last: now}
+		l.buckets[method] = b
+	}
+	b.tokens = 0
+	b.last = now
+	if until := now.Add(d); until.After(b.blockedUntil) {
+		b.blockedUntil = until
+	}
+}
+
+// displayMethod returns a human-readable name for a rate limit key.
+func displayMethod(method string) string {
+	if method == "" {
+		return "non-API URL"
+	}
+	return method
+}
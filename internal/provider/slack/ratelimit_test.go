@@ -0,0 +1,140 @@
+package slack
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestAPIMethodFromURL(t *testing.T) {
+	tests := []struct {
+		url  string
+		want string
+	}{
+		{"https://slack.com/api/conversations.history?channel=C01", "conversations.history"},
+		{"https://slack.com/api/chat.postMessage", "chat.postMessage"},
+		{"https://files.slack.com/files-pri/T01-F01/download/report.pdf", ""},
+		{"https://slack.com/api/", ""},
+	}
+	for _, tt := range tests {
+		if got := apiMethodFromURL(tt.url); got != tt.want {
+			t.Errorf("apiMethodFromURL(%q) = %q, want %q", tt.url, got, tt.want)
+		}
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	if got := parseRetryAfter("3"); got != 3*time.Second {
+		t.Errorf("parseRetryAfter(\"3\") = %s, want 3s", got)
+	}
+	if got := parseRetryAfter(""); got != defaultRetryAfter {
+		t.Errorf("parseRetryAfter(\"\") = %s, want %s", got, defaultRetryAfter)
+	}
+}
+
+func TestRateLimiter_PacesAfterBurst(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	l := newRateLimiter()
+	l.now = func() time.Time { return now }
+
+	// users.list is Tier 2: a burst of 5, then one request every 3 seconds.
+	for i := 0; i < tier2.burst; i++ {
+		if d := l.reserve("users.list"); d != 0 {
+			t.Fatalf("request %d within burst: got delay %s, want 0", i+1, d)
+		}
+	}
+	if d := l.reserve("users.list"); d != 3*time.Second {
+		t.Errorf("first request after burst: got delay %s, want 3s", d)
+	}
+	if d := l.reserve("users.list"); d != 6*time.Second {
+		t.Errorf("second request after burst: got delay %s, want 6s", d)
+	}
+
+	// Other methods have their own bucket.
+	if d := l.reserve("users.info"); d != 0 {
+		t.Errorf("users.info: got delay %s, want 0", d)
+	}
+
+	// Tokens are refilled as time passes.
+	now = now.Add(time.Minute)
+	if d := l.reserve("users.list"); d != 0 {
+		t.Errorf("after refill: got delay %s, want 0", d)
+	}
+}
+
+func TestRateLimiter_Block(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	l := newRateLimiter()
+	l.now = func() time.Time { return now }
+
+	l.block("conversations.history", 10*time.Second)
+	if d := l.reserve("conversations.history"); d != 10*time.Second {
+		t.Errorf("got delay %s, want 10s", d)
+	}
+
+	// Non-API URLs are not paced but still honor a block.
+	if d := l.reserve(""); d != 0 {
+		t.Errorf("non-API URL: got delay %s, want 0", d)
+	}
+	l.block("", 2*time.Second)
+	if d := l.reserve(""); d != 2*time.Second {
+		t.Errorf("blocked non-API URL: got delay %s, want 2s", d)
+	}
+}
+
+func TestSendRequest_RetriesAfter429(t *testing.T) {
+	calls := 0
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/conversations.history", func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls == 1 {
+			w.Header().Set("Retry-After", "7")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		_, _ = w.Write([]byte(`{"ok": true, "messages": []}`))
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	p := newTestProvider(server, "general")
+	var waits []time.Duration
+	p.limiter.sleep = func(ctx context.Context, d time.Duration) error {
+		waits = append(waits, d)
+		return nil
+	}
+
+	if _, err := p.sendRequest(context.Background(), "GET", conversationsHistoryURL+"?channel=C01TEST", nil, ""); err != nil {
+		t.Fatalf("sendRequest() returned an unexpected error: %v", err)
+	}
+	if calls != 2 {
+		t.Errorf("Expected 2 calls to conversations.history, got %d", calls)
+	}
+	if len(waits) != 1 || waits[0] < 6*time.Second || waits[0] > 7*time.Second {
+		t.Errorf("Expected a single wait of about 7s, got %v", waits)
+	}
+}
+
+func TestSendRequest_GivesUpAfterRepeated429(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/chat.postMessage", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Retry-After", "1")
+		w.WriteHeader(http.StatusTooManyRequests)
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	p := newTestProvider(server, "general")
+	p.limiter.sleep = func(ctx context.Context, d time.Duration) error { return nil }
+
+	_, err := p.sendRequest(context.Background(), "POST", postMessageURL, strings.NewReader(`{}`), "application/json; charset=utf-8")
+	if err == nil {
+		t.Fatal("sendRequest() expected an error, got nil")
+	}
+	if !strings.Contains(err.Error(), "rate limited on chat.postMessage") {
+		t.Errorf("Unexpected error: %v", err)
+	}
+}
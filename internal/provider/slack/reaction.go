@@ -0,0 +1,96 @@
+package slack
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+
+	"github.com/nlink-jp/scat/internal/provider"
+)
+
+// reactionPayload is the payload for the reactions.add and reactions.remove APIs.
+type reactionPayload struct {
+	Channel   string `json:"channel"`
+	Timestamp string `json:"timestamp"`
+	Name      string `json:"name"`
+}
+
+// reactionsGetResponse defines the structure for the reactions.get API response.
+type reactionsGetResponse struct {
+	Ok      bool   `json:"ok"`
+	Error   string `json:"error"`
+	Message struct {
+		Reactions []struct {
+			Name  string   `json:"name"`
+			Count int      `json:"count"`
+			Users []string `json:"users"`
+		} `json:"reactions"`
+	} `json:"message"`
+}
+
+// AddReaction adds an emoji reaction using reactions.add.
+// Adding a reaction that is already present is not an error.
+func (p *Provider) AddReaction(ctx context.Context, opts provider.ReactionOptions) error {
+	return p.sendReaction(ctx, reactionsAddURL, "already_reacted", opts)
+}
+
+// RemoveReaction removes an emoji reaction using reactions.remove.
+// Removing a reaction that is not present is not an error.
+func (p *Provider) RemoveReaction(ctx context.Context, opts provider.ReactionOptions) error {
+	return p.sendReaction(ctx, reactionsRemoveURL, "no_reaction", opts)
+}
+
+// sendReaction posts a reaction change to apiURL, treating the error code
+// noopCode as success because the reaction is already in the desired state.
+func (p *Provider) sendReaction(ctx context.Context, apiURL, noopCode string, opts provider.ReactionOptions) error {
+	channelID, err := p.messageChannelID(ctx, opts.Channel)
+	if err != nil {
+		return err
+	}
+
+	jsonPayload, err := json.Marshal(reactionPayload{Channel: channelID, Timestamp: opts.Timestamp, Name: opts.Name})
+	if err != nil {
+		return fmt.Errorf("failed to marshal reaction payload: %w", err)
+	}
+
+	_, err = p.sendRequest(ctx, "POST", apiURL, bytes.NewBuffer(jsonPayload), "application/json; charset=utf-8")
+	if isAPIErrorCode(err, noopCode) {
+		if p.Context.Debug {
+			fmt.Fprintf(os.Stderr, "[DEBUG] Ignoring %s for reaction :%s: on message %s.\n", noopCode, opts.Name, opts.Timestamp)
+		}
+		return nil
+	}
+	return err
+}
+
+// ListReactions lists the emoji reactions on a message using reactions.get.
+func (p *Provider) ListReactions(ctx context.Context, opts provider.ListReactionsOptions) ([]provider.Reaction, error) {
+	channelID, err := p.messageChannelID(ctx, opts.Channel)
+	if err != nil {
+		return nil, err
+	}
+
+	params := url.Values{}
+	params.Add("channel", channelID)
+	params.Add("timestamp", opts.Timestamp)
+	params.Add("full", "true")
+
+	respBody, err := p.sendRequest(ctx, "GET", reactionsGetURL+"?"+params.Encode(), nil, "")
+	if err != nil {
+		return nil, err
+	}
+
+	var getResp reactionsGetResponse
+	if err := json.Unmarshal(respBody, &getResp); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal reactions.get response: %w", err)
+	}
+
+	reactions := make([]provider.Reaction, 0, len(getResp.Message.Reactions))
+	for _, r := range getResp.Message.Reactions {
+		reactions = append(reactions, provider.Reaction{Name: r.Name, Count: r.Count, Users: r.Users})
+	}
+	return reactions, nil
+}
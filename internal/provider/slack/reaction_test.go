@@ -0,0 +1,82 @@
+package slack
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/nlink-jp/scat/internal/provider"
+)
+
+func TestAddReaction(t *testing.T) {
+	var payloads []reactionPayload
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/reactions.add", func(w http.ResponseWriter, r *http.Request) {
+		var payload reactionPayload
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			t.Errorf("Failed to decode request body: %v", err)
+		}
+		payloads = append(payloads, payload)
+		if len(payloads) > 1 {
+			_, _ = w.Write([]byte(`{"ok": false, "error": "already_reacted"}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"ok": true}`))
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	p := newTestProvider(server, "general")
+	opts := provider.ReactionOptions{Timestamp: "1700000000.000100", Name: "white_check_mark"}
+	if err := p.AddReaction(context.Background(), opts); err != nil {
+		t.Fatalf("AddReaction() returned an unexpected error: %v", err)
+	}
+	if payloads[0] != (reactionPayload{Channel: "C01TEST", Timestamp: "1700000000.000100", Name: "white_check_mark"}) {
+		t.Errorf("Unexpected reactions.add payload: %+v", payloads[0])
+	}
+
+	// Adding the same reaction again is not an error.
+	if err := p.AddReaction(context.Background(), opts); err != nil {
+		t.Errorf("AddReaction() on an existing reaction returned an error: %v", err)
+	}
+}
+
+func TestRemoveReaction_MessageNotFound(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/reactions.remove", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"ok": false, "error": "message_not_found"}`))
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	p := newTestProvider(server, "general")
+	err := p.RemoveReaction(context.Background(), provider.ReactionOptions{Timestamp: "1700000000.000100", Name: "eyes"})
+	if !errors.Is(err, provider.ErrMessageNotFound) {
+		t.Errorf("RemoveReaction() error = %v, want provider.ErrMessageNotFound", err)
+	}
+}
+
+func TestListReactions(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/reactions.get", func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		if q.Get("channel") != "C01TEST" || q.Get("timestamp") != "1700000000.000100" || q.Get("full") != "true" {
+			t.Errorf("Unexpected reactions.get query: %s", r.URL.RawQuery)
+		}
+		_, _ = w.Write([]byte(`{"ok": true, "type": "message", "message": {"reactions": [{"name": "eyes", "count": 2, "users": ["U01", "U02"]}]}}`))
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	p := newTestProvider(server, "general")
+	reactions, err := p.ListReactions(context.Background(), provider.ListReactionsOptions{Channel: "general", Timestamp: "1700000000.000100"})
+	if err != nil {
+		t.Fatalf("ListReactions() returned an unexpected error: %v", err)
+	}
+	if len(reactions) != 1 || reactions[0].Name != "eyes" || reactions[0].Count != 2 || len(reactions[0].Users) != 2 {
+		t.Errorf("Unexpected reactions: %+v", reactions)
+	}
+}
@@ -0,0 +1,133 @@
+package slack
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"os"
+	"time"
+
+	"github.com/nlink-jp/scat/internal/config"
+)
+
+// This file implements retries with exponential backoff and jitter for
+// transient failures: network errors and HTTP 5xx responses. Retries are only
+// applied to calls that are safe to repeat (see isIdempotent); message posts
+// are never retried because a repeated chat.postMessage could duplicate the
+// message if the first attempt actually reached Slack. For the same reason,
+// of the file upload steps only the upload URL request and the byte upload are
+// retried, not files.completeUploadExternal, which shares the files.
+
+const (
+	retryInitialInterval = 500 * time.Millisecond
+	retryMaxInterval     = 30 * time.Second
+)
+
+// idempotentMethods lists non-GET Web API methods that can be repeated safely.
+var idempotentMethods = map[string]bool{
+	"chat.update":        true,
+	"conversations.join": true,
+	"conversations.open": true,
+	"pins.add":           true, // A repeated add fails with already_pinned, which is treated as success.
+	"pins.remove":        true, // A repeated remove fails with no_pin, which is treated as success.
+	"reactions.add":      true, // A repeated add fails with already_reacted, which is treated as success.
+	"reactions.remove":   true, // A repeated remove fails with no_reaction, which is treated as success.
+}
+
+// retryPolicy controls how transient failures are retried.
+// The zero value performs a single attempt.
+type retryPolicy struct {
+	maxAttempts int
+	maxElapsed  time.Duration
+
+	// sleep and jitter are replaceable for testing.
+	sleep  func(ctx context.Context, d time.Duration) error
+	jitter func() float64
+}
+
+// newRetryPolicy builds a retryPolicy from profile settings.
+func newRetryPolicy(cfg config.Retry) retryPolicy {
+	return retryPolicy{
+		maxAttempts: cfg.MaxAttempts,
+		maxElapsed:  time.Duration(cfg.MaxElapsedSeconds) * time.Second,
+		sleep:       sleepContext,
+		jitter:      rand.Float64,
+	}
+}
+
+// backoff returns the delay before the given retry (1 for the first retry).
+// It grows exponentially from retryInitialInterval up to retryMaxInterval and
+// is randomized to between half and the full interval.
+func (rp retryPolicy) backoff(retry int) time.Duration {
+	interval := retryInitialInterval << (retry - 1)
+	if interval > retryMaxInterval || interval <= 0 {
+		interval = retryMaxInterval
+	}
+	jitter := 1.0
+	if rp.jitter != nil {
+		jitter = rp.jitter()
+	}
+	return interval/2 + time.Duration(jitter*float64(interval/2))
+}
+
+// transientError marks a failure that may succeed if the call is repeated.
+type transientError struct {
+	err error
+}
+
+func (e *transientError) Error() string { return e.err.Error() }
+func (e *transientError) Unwrap() error { return e.err }
+
+// isTransient reports whether err was marked as retryable.
+func isTransient(err error) bool {
+	var te *transientError
+	return errors.As(err, &te)
+}
+
+// networkError marks err as transient unless it was caused by ctx ending.
+func networkError(ctx context.Context, err error) error {
+	if ctx.Err() != nil {
+		return err
+	}
+	return &transientError{err}
+}
+
+// isIdempotent reports whether a request can be repeated without side effects.
+func isIdempotent(httpMethod, apiMethod string) bool {
+	return httpMethod == "GET" || idempotentMethods[apiMethod]
+}
+
+// withRetry calls fn until it succeeds, fails with a non-transient error, or
+// the retry policy is exhausted. name identifies the call in progress reports.
+func (p *Provider) withRetry(ctx context.Context, name string, fn func() error) error {
+	rp := p.retry
+	start := time.Now()
+	for attempt := 1; ; attempt++ {
+		err := fn()
+		if err == nil {
+			if attempt > 1 && !p.Context.Silent {
+				fmt.Fprintf(os.Stderr, "%s succeeded on attempt %d of %d.\n", name, attempt, rp.maxAttempts)
+			}
+			return nil
+		}
+		if !isTransient(err) || ctx.Err() != nil || attempt >= rp.maxAttempts {
+			return err
+		}
+
+		delay := rp.backoff(attempt)
+		if rp.maxElapsed > 0 && time.Since(start)+delay > rp.maxElapsed {
+			return fmt.Errorf("%w (gave up retrying after %s)", err, time.Since(start).Round(time.Millisecond))
+		}
+		if !p.Context.Silent {
+			fmt.Fprintf(os.Stderr, "Warning: %s failed (attempt %d of %d): %v. Retrying in %s...\n", name, attempt, rp.maxAttempts, err, delay.Round(time.Millisecond))
+		}
+		sleep := rp.sleep
+		if sleep == nil {
+			sleep = sleepContext
+		}
+		if err := sleep(ctx, delay); err != nil {
+			return err
+		}
+	}
+}
@@ -0,0 +1,200 @@
+package slack
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nlink-jp/scat/internal/config"
+	"github.com/nlink-jp/scat/internal/provider"
+)
+
+// withTestRetry configures p to retry up to maxAttempts times without sleeping.
+func withTestRetry(p *Provider, maxAttempts int) *[]time.Duration {
+	var waits []time.Duration
+	p.retry = newRetryPolicy(config.Retry{MaxAttempts: maxAttempts})
+	p.retry.sleep = func(ctx context.Context, d time.Duration) error {
+		waits = append(waits, d)
+		return nil
+	}
+	return &waits
+}
+
+func TestRetryPolicy_Backoff(t *testing.T) {
+	rp := newRetryPolicy(config.Retry{MaxAttempts: 10})
+	rp.jitter = func() float64 { return 1 }
+
+	want := []time.Duration{500 * time.Millisecond, time.Second, 2 * time.Second, 4 * time.Second}
+	for i, w := range want {
+		if got := rp.backoff(i + 1); got != w {
+			t.Errorf("backoff(%d) = %s, want %s", i+1, got, w)
+		}
+	}
+	if got := rp.backoff(20); got != retryMaxInterval {
+		t.Errorf("backoff(20) = %s, want %s", got, retryMaxInterval)
+	}
+
+	rp.jitter = func() float64 { return 0 }
+	if got := rp.backoff(1); got != 250*time.Millisecond {
+		t.Errorf("backoff(1) with no jitter = %s, want 250ms", got)
+	}
+}
+
+func TestSendRequest_RetriesTransient5xx(t *testing.T) {
+	calls := 0
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/users.list", func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls < 3 {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		_, _ = w.Write([]byte(`{"ok": true, "members": []}`))
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	p := newTestProvider(server, "general")
+	waits := withTestRetry(p, 3)
+
+	if _, err := p.sendRequest(context.Background(), "GET", usersListURL, nil, ""); err != nil {
+		t.Fatalf("sendRequest() returned an unexpected error: %v", err)
+	}
+	if calls != 3 {
+		t.Errorf("Expected 3 calls, got %d", calls)
+	}
+	if len(*waits) != 2 {
+		t.Errorf("Expected 2 backoff waits, got %v", *waits)
+	}
+}
+
+func TestSendRequest_GivesUpAfterMaxAttempts(t *testing.T) {
+	calls := 0
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/users.list", func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.WriteHeader(http.StatusServiceUnavailable)
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	p := newTestProvider(server, "general")
+	withTestRetry(p, 2)
+
+	_, err := p.sendRequest(context.Background(), "GET", usersListURL, nil, "")
+	if err == nil {
+		t.Fatal("sendRequest() expected an error, got nil")
+	}
+	if !strings.Contains(err.Error(), "status code 503") {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	if calls != 2 {
+		t.Errorf("Expected 2 calls, got %d", calls)
+	}
+}
+
+func TestPostMessage_NotRetriedOn5xx(t *testing.T) {
+	calls := 0
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/chat.postMessage", func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.WriteHeader(http.StatusBadGateway)
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	p := newTestProvider(server, "general")
+	withTestRetry(p, 3)
+
+	if _, err := p.PostMessage(context.Background(), provider.PostMessageOptions{Text: "hello"}); err == nil {
+		t.Fatal("PostMessage() expected an error, got nil")
+	}
+	if calls != 1 {
+		t.Errorf("Expected chat.postMessage to be called once, got %d", calls)
+	}
+}
+
+func TestPostFile_CompleteUploadNotRetriedOn5xx(t *testing.T) {
+	filePath := filepath.Join(t.TempDir(), "test.txt")
+	if err := os.WriteFile(filePath, []byte("hello file"), 0666); err != nil {
+		t.Fatal(err)
+	}
+
+	var server *httptest.Server
+	completeCalls := 0
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/files.getUploadURLExternal", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(fmt.Sprintf(`{"ok": true, "upload_url": "%s/upload-here", "file_id": "F01"}`, server.URL)))
+	})
+	mux.HandleFunc("/upload-here", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	// The files may have been shared even though the response was lost, so
+	// the call is not repeated.
+	mux.HandleFunc("/api/files.completeUploadExternal", func(w http.ResponseWriter, r *http.Request) {
+		completeCalls++
+		w.WriteHeader(http.StatusBadGateway)
+	})
+	server = httptest.NewServer(mux)
+	defer server.Close()
+
+	p := newTestProvider(server, "general")
+	withTestRetry(p, 3)
+
+	opts := provider.PostFileOptions{FilePath: filePath, Filename: "test.txt"}
+	if _, err := p.PostFile(context.Background(), opts); err == nil {
+		t.Fatal("PostFile() expected an error, got nil")
+	}
+	if completeCalls != 1 {
+		t.Errorf("Expected files.completeUploadExternal to be called once, got %d", completeCalls)
+	}
+}
+
+func TestPostFile_RetriesUploadStep(t *testing.T) {
+	filePath := filepath.Join(t.TempDir(), "test.txt")
+	if err := os.WriteFile(filePath, []byte("hello file"), 0666); err != nil {
+		t.Fatal(err)
+	}
+
+	var server *httptest.Server
+	uploads := 0
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/files.getUploadURLExternal", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(fmt.Sprintf(`{"ok": true, "upload_url": "%s/upload-here", "file_id": "F01"}`, server.URL)))
+	})
+	mux.HandleFunc("/upload-here", func(w http.ResponseWriter, r *http.Request) {
+		uploads++
+		body := make([]byte, 64)
+		n, _ := r.Body.Read(body)
+		if string(body[:n]) != "hello file" {
+			t.Errorf("Upload attempt %d: unexpected body %q", uploads, string(body[:n]))
+		}
+		if uploads == 1 {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	})
+	mux.HandleFunc("/api/files.completeUploadExternal", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"ok": true, "files": []}`))
+	})
+	server = httptest.NewServer(mux)
+	defer server.Close()
+
+	p := newTestProvider(server, "general")
+	withTestRetry(p, 3)
+
+	opts := provider.PostFileOptions{FilePath: filePath, Filename: "test.txt"}
+	if _, err := p.PostFile(context.Background(), opts); err != nil {
+		t.Fatalf("PostFile() returned an unexpected error: %v", err)
+	}
+	if uploads != 2 {
+		t.Errorf("Expected 2 upload attempts, got %d", uploads)
+	}
+}
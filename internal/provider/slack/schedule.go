@@ -0,0 +1,133 @@
+package slack
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"time"
+
+	"github.com/nlink-jp/scat/internal/provider"
+)
+
+// scheduleMessageResponse defines the structure for the chat.scheduleMessage API response.
+type scheduleMessageResponse struct {
+	Ok                 bool   `json:"ok"`
+	Error              string `json:"error"`
+	Channel            string `json:"channel"`
+	ScheduledMessageID string `json:"scheduled_message_id"`
+	PostAt             int64  `json:"post_at"`
+}
+
+// scheduledMessagesListResponse defines the structure for the chat.scheduledMessages.list API response.
+type scheduledMessagesListResponse struct {
+	Ok                bool   `json:"ok"`
+	Error             string `json:"error"`
+	ScheduledMessages []struct {
+		ID          string `json:"id"`
+		ChannelID   string `json:"channel_id"`
+		PostAt      int64  `json:"post_at"`
+		DateCreated int64  `json:"date_created"`
+		Text        string `json:"text"`
+	} `json:"scheduled_messages"`
+	ResponseMetadata metadata `json:"response_metadata"`
+}
+
+// deleteScheduledMessagePayload is the payload for the chat.deleteScheduledMessage API.
+type deleteScheduledMessagePayload struct {
+	Channel            string `json:"channel"`
+	ScheduledMessageID string `json:"scheduled_message_id"`
+}
+
+// scheduleResult converts a chat.scheduleMessage response into a PostResult.
+func scheduleResult(respBody []byte, channelID string) (*provider.PostResult, error) {
+	var schedResp scheduleMessageResponse
+	if err := json.Unmarshal(respBody, &schedResp); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal chat.scheduleMessage response: %w", err)
+	}
+	result := &provider.PostResult{
+		ChannelID:          schedResp.Channel,
+		ScheduledMessageID: schedResp.ScheduledMessageID,
+		PostAt:             time.Unix(schedResp.PostAt, 0),
+	}
+	if result.ChannelID == "" {
+		result.ChannelID = channelID
+	}
+	return result, nil
+}
+
+// ListScheduledMessages lists pending messages using chat.scheduledMessages.list.
+func (p *Provider) ListScheduledMessages(ctx context.Context, opts provider.ListScheduledMessagesOptions) ([]provider.ScheduledMessage, error) {
+	var channelID string
+	if opts.Channel != "" {
+		var err error
+		channelID, err = p.ResolveChannelID(ctx, opts.Channel)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var messages []provider.ScheduledMessage
+	cursor := ""
+	for {
+		params := url.Values{}
+		params.Add("limit", "100")
+		if channelID != "" {
+			params.Add("channel", channelID)
+		}
+		if cursor != "" {
+			params.Add("cursor", cursor)
+		}
+
+		respBody, err := p.sendRequest(ctx, "GET", scheduledMessagesListURL+"?"+params.Encode(), nil, "")
+		if err != nil {
+			return nil, err
+		}
+
+		var listResp scheduledMessagesListResponse
+		if err := json.Unmarshal(respBody, &listResp); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal chat.scheduledMessages.list response: %w", err)
+		}
+
+		for _, m := range listResp.ScheduledMessages {
+			msg := provider.ScheduledMessage{
+				ID:        m.ID,
+				ChannelID: m.ChannelID,
+				PostAt:    time.Unix(m.PostAt, 0),
+				Text:      m.Text,
+			}
+			if m.DateCreated > 0 {
+				msg.CreatedAt = time.Unix(m.DateCreated, 0)
+			}
+			messages = append(messages, msg)
+		}
+
+		cursor = listResp.ResponseMetadata.NextCursor
+		if cursor == "" {
+			break
+		}
+	}
+
+	if p.Context.Debug {
+		fmt.Fprintf(os.Stderr, "[DEBUG] Found %d scheduled messages.\n", len(messages))
+	}
+	return messages, nil
+}
+
+// DeleteScheduledMessage cancels a scheduled message using chat.deleteScheduledMessage.
+func (p *Provider) DeleteScheduledMessage(ctx context.Context, opts provider.DeleteScheduledMessageOptions) error {
+	channelID, err := p.messageChannelID(ctx, opts.Channel)
+	if err != nil {
+		return err
+	}
+
+	jsonPayload, err := json.Marshal(deleteScheduledMessagePayload{Channel: channelID, ScheduledMessageID: opts.ID})
+	if err != nil {
+		return fmt.Errorf("failed to marshal chat.deleteScheduledMessage payload: %w", err)
+	}
+
+	_, err = p.sendRequest(ctx, "POST", deleteScheduledMessageURL, bytes.NewBuffer(jsonPayload), "application/json; charset=utf-8")
+	return err
+}
@@ -0,0 +1,103 @@
+package slack
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/nlink-jp/scat/internal/provider"
+)
+
+func TestPostMessage_Scheduled(t *testing.T) {
+	var payload messagePayload
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/chat.scheduleMessage", func(w http.ResponseWriter, r *http.Request) {
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			t.Errorf("Failed to decode request body: %v", err)
+		}
+		_, _ = w.Write([]byte(`{"ok": true, "channel": "C01TEST", "scheduled_message_id": "Q1298393284", "post_at": 1793491200}`))
+	})
+	mux.HandleFunc("/api/chat.postMessage", func(w http.ResponseWriter, r *http.Request) {
+		t.Error("chat.postMessage must not be called for a scheduled message")
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	p := newTestProvider(server, "general")
+	postAt := time.Unix(1793491200, 0)
+	result, err := p.PostMessage(context.Background(), provider.PostMessageOptions{Text: "announcement", PostAt: postAt})
+	if err != nil {
+		t.Fatalf("PostMessage() returned an unexpected error: %v", err)
+	}
+	if payload.Channel != "C01TEST" || payload.PostAt != 1793491200 || payload.Text != "announcement" {
+		t.Errorf("Unexpected chat.scheduleMessage payload: %+v", payload)
+	}
+	if result.ChannelID != "C01TEST" || result.ScheduledMessageID != "Q1298393284" || !result.PostAt.Equal(postAt) || result.Timestamp != "" {
+		t.Errorf("Unexpected result: %+v", result)
+	}
+}
+
+func TestListScheduledMessages(t *testing.T) {
+	var channels, cursors []string
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/chat.scheduledMessages.list", func(w http.ResponseWriter, r *http.Request) {
+		channels = append(channels, r.URL.Query().Get("channel"))
+		cursors = append(cursors, r.URL.Query().Get("cursor"))
+		if r.URL.Query().Get("cursor") == "" {
+			_, _ = w.Write([]byte(`{"ok": true, "scheduled_messages": [{"id": "Q1", "channel_id": "C01TEST", "post_at": 1793491200, "date_created": 1793400000, "text": "first"}], "response_metadata": {"next_cursor": "page2"}}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"ok": true, "scheduled_messages": [{"id": "Q2", "channel_id": "C01TEST", "post_at": 1793577600, "text": "second"}], "response_metadata": {"next_cursor": ""}}`))
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	p := newTestProvider(server, "general")
+	messages, err := p.ListScheduledMessages(context.Background(), provider.ListScheduledMessagesOptions{Channel: "general"})
+	if err != nil {
+		t.Fatalf("ListScheduledMessages() returned an unexpected error: %v", err)
+	}
+	if len(messages) != 2 || messages[0].ID != "Q1" || messages[1].ID != "Q2" {
+		t.Fatalf("Unexpected messages: %+v", messages)
+	}
+	if !messages[0].PostAt.Equal(time.Unix(1793491200, 0)) || !messages[0].CreatedAt.Equal(time.Unix(1793400000, 0)) || !messages[1].CreatedAt.IsZero() {
+		t.Errorf("Unexpected times: %+v", messages)
+	}
+	if len(cursors) != 2 || cursors[1] != "page2" || channels[0] != "C01TEST" {
+		t.Errorf("Unexpected requests: channels=%v cursors=%v", channels, cursors)
+	}
+}
+
+func TestDeleteScheduledMessage(t *testing.T) {
+	var payload deleteScheduledMessagePayload
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/chat.deleteScheduledMessage", func(w http.ResponseWriter, r *http.Request) {
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			t.Errorf("Failed to decode request body: %v", err)
+		}
+		if payload.ScheduledMessageID == "Q404" {
+			_, _ = w.Write([]byte(`{"ok": false, "error": "invalid_scheduled_message_id"}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"ok": true}`))
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	p := newTestProvider(server, "general")
+	if err := p.DeleteScheduledMessage(context.Background(), provider.DeleteScheduledMessageOptions{ID: "Q1"}); err != nil {
+		t.Fatalf("DeleteScheduledMessage() returned an unexpected error: %v", err)
+	}
+	if payload.Channel != "C01TEST" || payload.ScheduledMessageID != "Q1" {
+		t.Errorf("Unexpected chat.deleteScheduledMessage payload: %+v", payload)
+	}
+
+	err := p.DeleteScheduledMessage(context.Background(), provider.DeleteScheduledMessageOptions{ID: "Q404"})
+	if !errors.Is(err, provider.ErrMessageNotFound) {
+		t.Errorf("DeleteScheduledMessage() error = %v, want provider.ErrMessageNotFound", err)
+	}
+}
@@ -1,9 +1,8 @@
 package slack
 
 import (
-	"fmt"
 	"net/http"
-	"os"
+	"sync"
 
 	"github.com/nlink-jp/scat/internal/appcontext"
 	"github.com/nlink-jp/scat/internal/config"
@@ -16,51 +15,45 @@ type Provider struct {
 	Context          appcontext.Context
 	httpClient       *http.Client
 	channelIDCache   map[string]string
+	channels         []provider.Channel // Channels listed when channelIDCache was populated.
+	channelMu        sync.Mutex         // Guards channelIDCache and channels.
 	userIDCache      map[string]string
+	userNames        userNameCache // User ID to name, shared by all exports.
+	downloads        downloadStore // Attachments downloaded by exports during this run.
 	userGroupIDCache map[string]string
+	limiter          *rateLimiter
+	retry            retryPolicy
 }
 
 // NewProvider creates a new Slack Provider.
+// The channel, user, and user group caches are populated lazily on first use
+// so that the API calls involved run under the caller's context.
 func NewProvider(p config.Profile, ctx appcontext.Context) (provider.Interface, error) {
 	prov := &Provider{
-		Profile:          p,
-		Context:          ctx,
-		httpClient:       &http.Client{},
-		channelIDCache:   make(map[string]string),
-		userIDCache:      make(map[string]string),
-		userGroupIDCache: make(map[string]string),
+		Profile:    p,
+		Context:    ctx,
+		httpClient: &http.Client{},
+		limiter:    newRateLimiter(),
+		retry:      newRetryPolicy(p.Retry),
 	}
-
-	// Best-effort attempt to populate caches on initialization.
-	if err := prov.populateChannelCache(); err != nil {
-		if ctx.Debug {
-			fmt.Fprintf(os.Stderr, "[DEBUG] Failed to populate channel cache on init: %v\n", err)
-		}
-	}
-	if err := prov.populateUserCache(); err != nil {
-		if ctx.Debug {
-			fmt.Fprintf(os.Stderr, "[DEBUG] Failed to populate user cache on init: %v\n", err)
-		}
-	}
-	if err := prov.populateUserGroupCache(); err != nil {
-		if ctx.Debug {
-			fmt.Fprintf(os.Stderr, "[DEBUG] Failed to populate user group cache on init: %v\n", err)
-		}
-	}
-
 	return prov, nil
 }
 
 // Capabilities returns the features supported by the Slack provider.
 func (p *Provider) Capabilities() provider.Capabilities {
 	return provider.Capabilities{
-		CanListChannels:    true,
-		CanListUsers:       true,
-		CanPostFile:        true,
-		CanUseIconEmoji:    true,
-		CanExportLogs:      true,
-		CanPostBlocks:      true,
-		CanCreateChannel:   true,
-		CanInviteToChannel: true,
+		CanListChannels:     true,
+		CanListUsers:        true,
+		CanPostFile:         true,
+		CanUseIconEmoji:     true,
+		CanExportLogs:       true,
+		CanPostBlocks:       true,
+		CanCreateChannel:    true,
+		CanInviteToChannel:  true,
+		CanEditMessages:     true,
+		CanScheduleMessages: true,
+		CanReact:            true,
+		CanPostEphemeral:    true,
+		CanPin:              true,
 	}
 }
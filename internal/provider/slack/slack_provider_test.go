@@ -1,18 +1,29 @@
 package slack
 
 import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
 	"os"
+	"reflect"
+	"path/filepath"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/nlink-jp/scat/internal/appcontext"
 	"github.com/nlink-jp/scat/internal/config"
 	"github.com/nlink-jp/scat/internal/export"
 	"github.com/nlink-jp/scat/internal/provider"
+	"github.com/nlink-jp/scat/internal/util"
 )
 
 // mockServerTransport is a custom http.RoundTripper that rewrites request URLs to the mock server.
@@ -49,6 +60,7 @@ func newTestProvider(server *httptest.Server, channelName string) *Provider {
 		httpClient:     client,
 		channelIDCache: make(map[string]string),
 		userIDCache:    make(map[string]string),
+		limiter:        newRateLimiter(),
 	}
 	// Pre-populate caches for tests
 	p.channelIDCache[channelName] = "C01TEST"
@@ -59,6 +71,13 @@ func newTestProvider(server *httptest.Server, channelName string) *Provider {
 	return p
 }
 
+// collectLog exports a channel and gathers all pages into one log.
+func collectLog(p *Provider, opts export.Options) (*export.ExportedLog, error) {
+	return export.Collect(func(onPage export.PageFunc) (*export.LogInfo, error) {
+		return p.ExportLog(context.Background(), opts, onPage)
+	})
+}
+
 func TestPostMessage(t *testing.T) {
 	mux := http.NewServeMux()
 	mux.HandleFunc("/api/chat.postMessage", func(w http.ResponseWriter, r *http.Request) {
@@ -74,11 +93,116 @@ func TestPostMessage(t *testing.T) {
 		Text: "hello world",
 	}
 
-	if err := p.PostMessage(opts); err != nil {
+	if _, err := p.PostMessage(context.Background(), opts); err != nil {
 		t.Errorf("PostMessage() returned an unexpected error: %v", err)
 	}
 }
 
+func TestPostMessage_ReturnsResult(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/chat.postMessage", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"ok": true, "channel": "C01TEST", "ts": "1700000000.000100"}`))
+	})
+	mux.HandleFunc("/api/chat.getPermalink", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("channel") != "C01TEST" || r.URL.Query().Get("message_ts") != "1700000000.000100" {
+			t.Errorf("Unexpected chat.getPermalink query: %s", r.URL.RawQuery)
+		}
+		_, _ = w.Write([]byte(`{"ok": true, "permalink": "https://example.slack.com/archives/C01TEST/p1700000000000100"}`))
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	p := newTestProvider(server, "general")
+	result, err := p.PostMessage(context.Background(), provider.PostMessageOptions{Text: "hello world"})
+	if err != nil {
+		t.Fatalf("PostMessage() returned an unexpected error: %v", err)
+	}
+	want := provider.PostResult{
+		ChannelID: "C01TEST",
+		Timestamp: "1700000000.000100",
+		Permalink: "https://example.slack.com/archives/C01TEST/p1700000000000100",
+	}
+	if !reflect.DeepEqual(*result, want) {
+		t.Errorf("PostMessage() result = %+v, want %+v", *result, want)
+	}
+}
+
+func TestPostMessage_ThreadReply(t *testing.T) {
+	var payload messagePayload
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/chat.postMessage", func(w http.ResponseWriter, r *http.Request) {
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			t.Errorf("Failed to decode request body: %v", err)
+		}
+		_, _ = w.Write([]byte(`{"ok": true}`))
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	p := newTestProvider(server, "general")
+	opts := provider.PostMessageOptions{Text: "reply", ThreadTS: "1700000000.000100", ReplyBroadcast: true}
+	if _, err := p.PostMessage(context.Background(), opts); err != nil {
+		t.Fatalf("PostMessage() returned an unexpected error: %v", err)
+	}
+	if payload.ThreadTS != "1700000000.000100" || !payload.ReplyBroadcast {
+		t.Errorf("Expected thread_ts and reply_broadcast in the payload, got %+v", payload)
+	}
+}
+
+func TestPostMessage_Ephemeral(t *testing.T) {
+	var payload messagePayload
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/chat.postEphemeral", func(w http.ResponseWriter, r *http.Request) {
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			t.Errorf("Failed to decode request body: %v", err)
+		}
+		_, _ = w.Write([]byte(`{"ok": true, "message_ts": "1700000000.000200"}`))
+	})
+	mux.HandleFunc("/api/chat.postMessage", func(w http.ResponseWriter, r *http.Request) {
+		t.Error("chat.postMessage must not be called for an ephemeral message")
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	p := newTestProvider(server, "general")
+	opts := provider.PostMessageOptions{Text: "only for you", EphemeralUserID: "@user_one"}
+	result, err := p.PostMessage(context.Background(), opts)
+	if err != nil {
+		t.Fatalf("PostMessage() returned an unexpected error: %v", err)
+	}
+	if payload.Channel != "C01TEST" || payload.User != "U01" || payload.Text != "only for you" {
+		t.Errorf("Unexpected chat.postEphemeral payload: %+v", payload)
+	}
+	if result.ChannelID != "C01TEST" || result.Timestamp != "" || result.Permalink != "" {
+		t.Errorf("Unexpected result: %+v", result)
+	}
+}
+
+func TestPostMessage_ContextDeadline(t *testing.T) {
+	release := make(chan struct{})
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/chat.postMessage", func(w http.ResponseWriter, r *http.Request) {
+		// Simulate a hung Slack API call.
+		<-release
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+	defer close(release)
+
+	p := newTestProvider(server, "general")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	_, err := p.PostMessage(ctx, provider.PostMessageOptions{Text: "hello world"})
+	if err == nil {
+		t.Fatal("PostMessage() expected an error, got nil")
+	}
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("PostMessage() error = %v, want context.DeadlineExceeded", err)
+	}
+}
+
 func TestPostFile(t *testing.T) {
 	// Create a dummy file to upload
 	tempDir := t.TempDir()
@@ -115,11 +239,274 @@ func TestPostFile(t *testing.T) {
 		Comment:  "a test file",
 	}
 
-	if err := p.PostFile(opts); err != nil {
+	if _, err := p.PostFile(context.Background(), opts); err != nil {
 		t.Errorf("PostFile() returned an unexpected error: %v", err)
 	}
 }
 
+func TestPostFile_ReturnsResult(t *testing.T) {
+	filePath := filepath.Join(t.TempDir(), "test.txt")
+	if err := os.WriteFile(filePath, []byte("hello file"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	var server *httptest.Server
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/files.getUploadURLExternal", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `{"ok": true, "upload_url": "%s/upload-here", "file_id": "F01"}`, server.URL)
+	})
+	mux.HandleFunc("/upload-here", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("ok"))
+	})
+	mux.HandleFunc("/api/files.completeUploadExternal", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"ok": true, "files": [{"id": "F01"}]}`))
+	})
+	mux.HandleFunc("/api/files.info", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"ok": true, "file": {"id": "F01", "permalink": "https://example.slack.com/files/U01/F01/test.txt", "shares": {"public": {"C01TEST": [{"ts": "1700000000.000200"}]}}}}`))
+	})
+	mux.HandleFunc("/api/chat.getPermalink", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"ok": true, "permalink": "https://example.slack.com/archives/C01TEST/p1700000000000200"}`))
+	})
+	server = httptest.NewServer(mux)
+	defer server.Close()
+
+	p := newTestProvider(server, "general")
+	result, err := p.PostFile(context.Background(), provider.PostFileOptions{FilePath: filePath, Filename: "test.txt"})
+	if err != nil {
+		t.Fatalf("PostFile() returned an unexpected error: %v", err)
+	}
+	want := provider.PostResult{
+		ChannelID: "C01TEST",
+		Timestamp: "1700000000.000200",
+		FileID:    "F01",
+		Permalink: "https://example.slack.com/archives/C01TEST/p1700000000000200",
+	}
+	if !reflect.DeepEqual(*result, want) {
+		t.Errorf("PostFile() result = %+v, want %+v", *result, want)
+	}
+}
+
+func TestPostFile_ResultWithoutShareInfo(t *testing.T) {
+	filePath := filepath.Join(t.TempDir(), "test.txt")
+	if err := os.WriteFile(filePath, []byte("hello file"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	var server *httptest.Server
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/files.getUploadURLExternal", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `{"ok": true, "upload_url": "%s/upload-here", "file_id": "F01"}`, server.URL)
+	})
+	mux.HandleFunc("/upload-here", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("ok"))
+	})
+	mux.HandleFunc("/api/files.completeUploadExternal", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"ok": true, "files": [{"id": "F01"}]}`))
+	})
+	mux.HandleFunc("/api/files.info", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"ok": false, "error": "missing_scope", "needed": "files:read"}`))
+	})
+	server = httptest.NewServer(mux)
+	defer server.Close()
+
+	p := newTestProvider(server, "general")
+	result, err := p.PostFile(context.Background(), provider.PostFileOptions{FilePath: filePath, Filename: "test.txt"})
+	if err != nil {
+		t.Fatalf("PostFile() should succeed even if the share lookup fails, got: %v", err)
+	}
+	want := provider.PostResult{ChannelID: "C01TEST", FileID: "F01"}
+	if !reflect.DeepEqual(*result, want) {
+		t.Errorf("PostFile() result = %+v, want %+v", *result, want)
+	}
+}
+
+func TestPostFile_Content(t *testing.T) {
+	// Large enough to be uploaded with a progress bar.
+	data := bytes.Repeat([]byte("0123456789abcdef"), util.ProgressMinSize/8)
+
+	var server *httptest.Server
+	var gotLength string
+	var attempts int
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/files.getUploadURLExternal", func(w http.ResponseWriter, r *http.Request) {
+		gotLength = r.URL.Query().Get("length")
+		fmt.Fprintf(w, `{"ok": true, "upload_url": "%s/upload-here", "file_id": "F01"}`, server.URL)
+	})
+	mux.HandleFunc("/upload-here", func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		body, _ := io.ReadAll(r.Body)
+		if attempts == 1 {
+			// Fail the first attempt after the content was sent to check that it is rewound.
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		if !bytes.Equal(body, data) {
+			t.Errorf("Uploaded %d bytes that differ from the content (%d bytes)", len(body), len(data))
+		}
+		_, _ = w.Write([]byte("ok"))
+	})
+	mux.HandleFunc("/api/files.completeUploadExternal", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"ok": true, "files": []}`))
+	})
+	mux.HandleFunc("/api/files.info", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"ok": false, "error": "missing_scope"}`))
+	})
+	server = httptest.NewServer(mux)
+	defer server.Close()
+
+	p := newTestProvider(server, "general")
+	p.retry = retryPolicy{maxAttempts: 2, sleep: func(ctx context.Context, d time.Duration) error { return nil }}
+
+	opts := provider.PostFileOptions{Filename: "stdin-upload", Content: bytes.NewReader(data), Size: int64(len(data))}
+	if _, err := p.PostFile(context.Background(), opts); err != nil {
+		t.Fatalf("PostFile() returned an unexpected error: %v", err)
+	}
+	if gotLength != fmt.Sprint(len(data)) {
+		t.Errorf("Expected length %d, got %s", len(data), gotLength)
+	}
+	if attempts != 2 {
+		t.Errorf("Expected 2 upload attempts, got %d", attempts)
+	}
+}
+
+func TestPostFile_SnippetAndAltText(t *testing.T) {
+	tempDir := t.TempDir()
+	script := filepath.Join(tempDir, "deploy")
+	if err := os.WriteFile(script, []byte("#!/bin/bash\necho deploy\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	image := filepath.Join(tempDir, "chart.png")
+	if err := os.WriteFile(image, []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name      string
+		opts      provider.PostFileOptions
+		wantQuery url.Values
+		wantErr   string
+	}{
+		{
+			name:      "detected snippet type",
+			opts:      provider.PostFileOptions{FilePath: script, Filename: "deploy", Snippet: true},
+			wantQuery: url.Values{"filename": {"deploy"}, "length": {"24"}, "snippet_type": {"shell"}},
+		},
+		{
+			name:      "explicit snippet type",
+			opts:      provider.PostFileOptions{FilePath: script, Filename: "deploy", Filetype: "text", Snippet: true},
+			wantQuery: url.Values{"filename": {"deploy"}, "length": {"24"}, "snippet_type": {"text"}},
+		},
+		{
+			name:      "alt text",
+			opts:      provider.PostFileOptions{FilePath: image, Filename: "chart.png", AltText: "Build times"},
+			wantQuery: url.Values{"filename": {"chart.png"}, "length": {"16"}, "alt_txt": {"Build times"}},
+		},
+		{
+			name:    "binary snippet",
+			opts:    provider.PostFileOptions{FilePath: image, Filename: "chart.png", Snippet: true},
+			wantErr: "cannot upload chart.png as a snippet",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var server *httptest.Server
+			var gotQuery url.Values
+			mux := http.NewServeMux()
+			mux.HandleFunc("/api/files.getUploadURLExternal", func(w http.ResponseWriter, r *http.Request) {
+				gotQuery = r.URL.Query()
+				fmt.Fprintf(w, `{"ok": true, "upload_url": "%s/upload-here", "file_id": "F01"}`, server.URL)
+			})
+			mux.HandleFunc("/upload-here", func(w http.ResponseWriter, r *http.Request) {
+				_, _ = w.Write([]byte("ok"))
+			})
+			mux.HandleFunc("/api/files.completeUploadExternal", func(w http.ResponseWriter, r *http.Request) {
+				_, _ = w.Write([]byte(`{"ok": true, "files": []}`))
+			})
+			mux.HandleFunc("/api/files.info", func(w http.ResponseWriter, r *http.Request) {
+				_, _ = w.Write([]byte(`{"ok": false, "error": "missing_scope"}`))
+			})
+			server = httptest.NewServer(mux)
+			defer server.Close()
+
+			p := newTestProvider(server, "general")
+			_, err := p.PostFile(context.Background(), tt.opts)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("Expected error containing '%s', got: %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("PostFile() returned an unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(gotQuery, tt.wantQuery) {
+				t.Errorf("getUploadURLExternal query = %v, want %v", gotQuery, tt.wantQuery)
+			}
+		})
+	}
+}
+
+func TestPostFile_MultipleFiles(t *testing.T) {
+	tempDir := t.TempDir()
+	var files []provider.UploadFile
+	for i, name := range []string{"a.png", "b.txt"} {
+		path := filepath.Join(tempDir, name)
+		if err := os.WriteFile(path, []byte("content of "+name), 0600); err != nil {
+			t.Fatal(err)
+		}
+		files = append(files, provider.UploadFile{Path: path, Filename: name, Title: fmt.Sprintf("Title %d", i+1)})
+	}
+
+	var server *httptest.Server
+	var uploadedNames []string
+	var completeCalls int
+	var completePayload completeUploadExternalPayload
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/files.getUploadURLExternal", func(w http.ResponseWriter, r *http.Request) {
+		uploadedNames = append(uploadedNames, r.URL.Query().Get("filename"))
+		fmt.Fprintf(w, `{"ok": true, "upload_url": "%s/upload-here", "file_id": "F0%d"}`, server.URL, len(uploadedNames))
+	})
+	mux.HandleFunc("/upload-here", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("ok"))
+	})
+	mux.HandleFunc("/api/files.completeUploadExternal", func(w http.ResponseWriter, r *http.Request) {
+		completeCalls++
+		if err := json.NewDecoder(r.Body).Decode(&completePayload); err != nil {
+			t.Errorf("Failed to decode completeUploadExternal payload: %v", err)
+		}
+		_, _ = w.Write([]byte(`{"ok": true, "files": []}`))
+	})
+	mux.HandleFunc("/api/files.info", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"ok": false, "error": "missing_scope"}`))
+	})
+	server = httptest.NewServer(mux)
+	defer server.Close()
+
+	p := newTestProvider(server, "general")
+	result, err := p.PostFile(context.Background(), provider.PostFileOptions{Files: files, Comment: "two files"})
+	if err != nil {
+		t.Fatalf("PostFile() returned an unexpected error: %v", err)
+	}
+
+	if !reflect.DeepEqual(uploadedNames, []string{"a.png", "b.txt"}) {
+		t.Errorf("Expected upload URLs for a.png and b.txt, got %v", uploadedNames)
+	}
+	if completeCalls != 1 {
+		t.Fatalf("Expected a single files.completeUploadExternal call, got %d", completeCalls)
+	}
+	wantFiles := []fileInfo{{ID: "F01", Title: "Title 1"}, {ID: "F02", Title: "Title 2"}}
+	if !reflect.DeepEqual(completePayload.Files, wantFiles) {
+		t.Errorf("completeUploadExternal files = %+v, want %+v", completePayload.Files, wantFiles)
+	}
+	if completePayload.InitialComment != "two files" || completePayload.ChannelID != "C01TEST" {
+		t.Errorf("Unexpected completeUploadExternal payload: %+v", completePayload)
+	}
+	want := provider.PostResult{ChannelID: "C01TEST", FileID: "F01", FileIDs: []string{"F01", "F02"}}
+	if !reflect.DeepEqual(*result, want) {
+		t.Errorf("PostFile() result = %+v, want %+v", *result, want)
+	}
+}
+
 func TestExportLog(t *testing.T) {
 	var server *httptest.Server
 	mux := http.NewServeMux()
@@ -176,7 +563,7 @@ func TestExportLog(t *testing.T) {
 		OutputDir:    tempDir,
 	}
 
-	log, err := p.ExportLog(opts)
+	log, err := collectLog(p, opts)
 	if err != nil {
 		t.Fatalf("ExportLog() returned an unexpected error: %v", err)
 	}
@@ -268,7 +655,7 @@ func TestExportLog_WithThread(t *testing.T) {
 	p := newTestProvider(server, "test-thread-export")
 	opts := export.Options{ChannelName: "test-thread-export"}
 
-	log, err := p.ExportLog(opts)
+	log, err := collectLog(p, opts)
 	if err != nil {
 		t.Fatalf("ExportLog() returned an unexpected error: %v", err)
 	}
@@ -324,7 +711,7 @@ func TestCreateChannel(t *testing.T) {
 	opts := provider.CreateChannelOptions{
 		Name: "new-channel",
 	}
-	channelID, err := p.CreateChannel(opts)
+	channelID, err := p.CreateChannel(context.Background(), opts)
 	if err != nil {
 		t.Errorf("CreateChannel() returned an unexpected error: %v", err)
 	}
@@ -332,4 +719,518 @@ func TestCreateChannel(t *testing.T) {
 	if channelID != "C024BE91L" {
 		t.Errorf("Expected channel ID C024BE91L, got %s", channelID)
 	}
-}
\ No newline at end of file
+}
+func TestExportLog_Incremental(t *testing.T) {
+	var historyCursors []string
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("/api/conversations.history", func(w http.ResponseWriter, r *http.Request) {
+		cursor := r.URL.Query().Get("cursor")
+		historyCursors = append(historyCursors, cursor)
+		if cursor == "" {
+			_, _ = w.Write([]byte(`{
+				"ok": true,
+				"messages": [
+					{"type": "message", "user": "U01", "text": "new message", "ts": "1700000300.000000"},
+					{"type": "message", "user": "U01", "text": "thread with new replies", "ts": "1700000100.000000", "thread_ts": "1700000100.000000", "reply_count": 2, "latest_reply": "1700000250.000000"},
+					{"type": "message", "user": "U01", "text": "thread without new replies", "ts": "1700000050.000000", "thread_ts": "1700000050.000000", "reply_count": 1, "latest_reply": "1700000060.000000"},
+					{"type": "message", "user": "U01", "text": "old message", "ts": "1700000010.000000"}
+				],
+				"has_more": true,
+				"response_metadata": {"next_cursor": "page2"}
+			}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{
+			"ok": true,
+			"messages": [{"type": "message", "user": "U01", "text": "older message", "ts": "1700000005.000000"}],
+			"has_more": false
+		}`))
+	})
+
+	mux.HandleFunc("/api/conversations.replies", func(w http.ResponseWriter, r *http.Request) {
+		if ts := r.URL.Query().Get("ts"); ts != "1700000100.000000" {
+			t.Errorf("Unexpected replies request for thread %s", ts)
+		}
+		if oldest := r.URL.Query().Get("oldest"); oldest != "1700000200.000000" {
+			t.Errorf("Expected replies newer than the known reply, got oldest=%s", oldest)
+		}
+		// Slack always includes the parent message.
+		_, _ = w.Write([]byte(`{
+			"ok": true,
+			"messages": [
+				{"type": "message", "user": "U01", "text": "thread with new replies", "ts": "1700000100.000000", "thread_ts": "1700000100.000000"},
+				{"type": "message", "user": "U02", "text": "new reply", "ts": "1700000250.000000", "thread_ts": "1700000100.000000"}
+			],
+			"has_more": false
+		}`))
+	})
+
+	mux.HandleFunc("/api/users.info", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"ok": true, "user": {"id": "U01", "name": "user_one"}}`))
+	})
+
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	p := newTestProvider(server, "test-export")
+	var pages []export.Page
+	opts := export.Options{
+		ChannelName:   "test-export",
+		ExportedUntil: "1700000100.000000",
+		Threads: map[string]string{
+			"1700000100.000000": "1700000200.000000",
+			"1700000050.000000": "1700000060.000000",
+		},
+	}
+	onPage := func(page export.Page) error {
+		pages = append(pages, page)
+		return nil
+	}
+
+	info, err := p.ExportLog(context.Background(), opts, onPage)
+	if err != nil {
+		t.Fatalf("ExportLog() returned an unexpected error: %v", err)
+	}
+	if info.ChannelID != "C01TEST" {
+		t.Errorf("ChannelID = %q, want C01TEST", info.ChannelID)
+	}
+	if len(pages) != 2 {
+		t.Fatalf("Expected 2 pages, got %d", len(pages))
+	}
+
+	var got []string
+	for _, msg := range pages[0].Messages {
+		got = append(got, msg.Text)
+	}
+	if want := []string{"new reply", "new message"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("First page messages = %v, want %v", got, want)
+	}
+	if pages[0].NextCursor != "page2" {
+		t.Errorf("Expected NextCursor page2 on the first page, got %q", pages[0].NextCursor)
+	}
+	if len(pages[1].Messages) != 0 || pages[1].NextCursor != "" {
+		t.Errorf("Expected an empty final page, got %+v", pages[1])
+	}
+
+	// A resumed export continues at the saved cursor.
+	historyCursors, pages = nil, nil
+	opts.Cursor = "page2"
+	if _, err := p.ExportLog(context.Background(), opts, onPage); err != nil {
+		t.Fatalf("ExportLog() returned an unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(historyCursors, []string{"page2"}) {
+		t.Errorf("Expected history to be requested from cursor page2 only, got %v", historyCursors)
+	}
+}
+
+func TestExportLog_SharesUserNameCache(t *testing.T) {
+	var usersInfoCalls atomic.Int32
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/conversations.history", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"ok": true, "messages": [{"type": "message", "user": "U01", "text": "hello", "ts": "1700000000.000000"}], "has_more": false}`))
+	})
+	mux.HandleFunc("/api/users.info", func(w http.ResponseWriter, r *http.Request) {
+		usersInfoCalls.Add(1)
+		_, _ = w.Write([]byte(`{"ok": true, "user": {"id": "U01", "name": "user_one"}}`))
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	p := newTestProvider(server, "first")
+	p.channelIDCache["second"] = "C02TEST"
+
+	for _, channel := range []string{"first", "second"} {
+		log, err := collectLog(p, export.Options{ChannelName: channel})
+		if err != nil {
+			t.Fatalf("ExportLog(%s) returned an unexpected error: %v", channel, err)
+		}
+		if len(log.Messages) != 1 || log.Messages[0].UserName != "user_one" {
+			t.Errorf("Unexpected messages for %s: %+v", channel, log.Messages)
+		}
+	}
+	if n := usersInfoCalls.Load(); n != 1 {
+		t.Errorf("Expected users.info to be called once across channels, got %d calls", n)
+	}
+}
+
+func TestListChannels(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/conversations.list", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"ok": true, "channels": [
+			{"id": "C01", "name": "general"},
+			{"id": "C02", "name": "old-project", "is_archived": true},
+			{"id": "G01", "name": "secret", "is_private": true}
+		]}`))
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	p := newTestProvider(server, "general")
+	channels, err := p.ListChannels(context.Background())
+	if err != nil {
+		t.Fatalf("ListChannels() returned an unexpected error: %v", err)
+	}
+	want := []provider.Channel{
+		{ID: "C01", Name: "general"},
+		{ID: "C02", Name: "old-project", IsArchived: true},
+		{ID: "G01", Name: "secret", IsPrivate: true},
+	}
+	if !reflect.DeepEqual(channels, want) {
+		t.Errorf("ListChannels() = %+v, want %+v", channels, want)
+	}
+}
+
+func TestExportLog_PreservesSlackFields(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/conversations.history", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"ok": true, "has_more": false, "messages": [
+			{"type": "message", "subtype": "bot_message", "bot_id": "B01", "username": "deploy-bot", "text": "deployed", "ts": "1700000000.000000",
+			 "files": [{"id": "F01", "name": "log.txt", "title": "Deploy log", "mimetype": "text/plain", "filetype": "text", "size": 42,
+			            "url_private": "https://files.slack.com/F01", "url_private_download": "https://files.slack.com/F01/download",
+			            "permalink": "https://example.slack.com/files/F01", "created": 1700000000, "user": "U01", "pretty_type": "Plain Text",
+			            "mode": "hosted", "is_external": false}]}
+		]}`))
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	p := newTestProvider(server, "general")
+	log, err := collectLog(p, export.Options{ChannelName: "general"})
+	if err != nil {
+		t.Fatalf("ExportLog() returned an unexpected error: %v", err)
+	}
+	if log.ChannelID != "C01TEST" {
+		t.Errorf("ChannelID = %q, want C01TEST", log.ChannelID)
+	}
+	if len(log.Messages) != 1 {
+		t.Fatalf("Expected 1 message, got %d", len(log.Messages))
+	}
+	msg := log.Messages[0]
+	if msg.Subtype != "bot_message" || msg.UserID != "B01" || msg.PostType != "bot" {
+		t.Errorf("Unexpected message: %+v", msg)
+	}
+	want := []export.ExportedFile{{ID: "F01", Name: "log.txt", Title: "Deploy log", Mimetype: "text/plain", Filetype: "text", Size: 42,
+		URLPrivate: "https://files.slack.com/F01", Permalink: "https://example.slack.com/files/F01", Created: 1700000000, UserID: "U01",
+		PrettyType: "Plain Text", Mode: "hosted", URLPrivateDownload: "https://files.slack.com/F01/download"}}
+	if !reflect.DeepEqual(msg.Files, want) {
+		t.Errorf("Files = %+v, want %+v", msg.Files, want)
+	}
+}
+
+func TestExportLog_RichMessages(t *testing.T) {
+	const parent = `{"type": "message", "user": "U01", "client_msg_id": "c0ffee", "text": "ship it?", "ts": "1700000000.000100",
+		"thread_ts": "1700000000.000100", "reply_count": 1, "reply_users": ["U02"], "latest_reply": "1700000100.000200",
+		"edited": {"user": "U01", "ts": "1700000050.000000"},
+		"reactions": [{"name": "thumbsup", "count": 2, "users": ["U02", "U03"]}],
+		"blocks": [{"type": "rich_text", "block_id": "b1"}]}`
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/conversations.history", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"ok": true, "has_more": false, "messages": [` + parent + `,
+			{"type": "message", "subtype": "channel_join", "user": "U03", "text": "<@U03> has joined the channel", "ts": "1700000200.000000"},
+			{"type": "message", "subtype": "bot_message", "bot_id": "B01", "text": "", "ts": "1700000300.000000",
+			 "attachments": [{"fallback": "build #1 passed", "color": "good"}]}
+		]}`))
+	})
+	mux.HandleFunc("/api/conversations.replies", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"ok": true, "has_more": false, "messages": [` + parent + `,
+			{"type": "message", "subtype": "thread_broadcast", "user": "U02", "text": "shipped", "ts": "1700000100.000200", "thread_ts": "1700000000.000100"}
+		]}`))
+	})
+	mux.HandleFunc("/api/users.info", func(w http.ResponseWriter, r *http.Request) {
+		userID := r.URL.Query().Get("user")
+		_, _ = w.Write([]byte(fmt.Sprintf(`{"ok": true, "user": {"id": "%s", "name": "%s"}}`, userID, strings.ToLower(userID))))
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	p := newTestProvider(server, "general")
+	log, err := collectLog(p, export.Options{ChannelName: "general"})
+	if err != nil {
+		t.Fatalf("ExportLog() returned an unexpected error: %v", err)
+	}
+	if log.FormatVersion != export.FormatVersion {
+		t.Errorf("FormatVersion = %d, want %d", log.FormatVersion, export.FormatVersion)
+	}
+	if len(log.Messages) != 4 {
+		t.Fatalf("Expected 4 messages, got %d", len(log.Messages))
+	}
+
+	first := log.Messages[0]
+	if first.ClientMsgID != "c0ffee" || first.ReplyCount != 1 || !reflect.DeepEqual(first.ReplyUsers, []string{"U02"}) {
+		t.Errorf("Unexpected thread parent: %+v", first)
+	}
+	wantEdit := &export.ExportedEdit{UserID: "U01", Timestamp: "2023-11-14T22:14:10Z", TimestampUnix: "1700000050.000000"}
+	if !reflect.DeepEqual(first.Edited, wantEdit) {
+		t.Errorf("Edited = %+v, want %+v", first.Edited, wantEdit)
+	}
+	wantReactions := []export.ExportedReaction{{Name: "thumbsup", Count: 2, Users: []string{"U02", "U03"}}}
+	if !reflect.DeepEqual(first.Reactions, wantReactions) {
+		t.Errorf("Reactions = %+v, want %+v", first.Reactions, wantReactions)
+	}
+	if string(first.Blocks) != `[{"type": "rich_text", "block_id": "b1"}]` {
+		t.Errorf("Blocks = %s", first.Blocks)
+	}
+	if first.Raw != nil {
+		t.Errorf("Expected no raw message without IncludeRaw, got %s", first.Raw)
+	}
+
+	for i, subtype := range []string{"", "thread_broadcast", "channel_join", "bot_message"} {
+		if log.Messages[i].Subtype != subtype {
+			t.Errorf("Messages[%d].Subtype = %q, want %q", i, log.Messages[i].Subtype, subtype)
+		}
+	}
+	if got := string(log.Messages[3].Attachments); got != `[{"fallback": "build #1 passed", "color": "good"}]` {
+		t.Errorf("Attachments = %s", got)
+	}
+
+	log, err = collectLog(p, export.Options{ChannelName: "general", IncludeRaw: true})
+	if err != nil {
+		t.Fatalf("ExportLog() returned an unexpected error: %v", err)
+	}
+	if got := string(log.Messages[0].Raw); got != parent {
+		t.Errorf("Raw = %s, want %s", got, parent)
+	}
+	for i, msg := range log.Messages {
+		var raw map[string]any
+		if err := json.Unmarshal(msg.Raw, &raw); err != nil || raw["ts"] != msg.TimestampUnix {
+			t.Errorf("Messages[%d].Raw = %s, want the message as returned by Slack", i, msg.Raw)
+		}
+	}
+}
+
+func TestDecodeMessages(t *testing.T) {
+	body := []byte(`{"ok": true, "messages": [{"type": "message", "ts": "1700000000.000100", "text": "a"}, {"type": "message", "ts": "1700000001.000100", "text": "b"}]}`)
+
+	// The JSON of the messages is only kept for raw exports.
+	resp, err := decodeMessages(body, false)
+	if err != nil {
+		t.Fatalf("decodeMessages() returned an unexpected error: %v", err)
+	}
+	for i, msg := range resp.Messages {
+		if msg.raw != nil {
+			t.Errorf("Messages[%d].raw = %s, want nil without raw", i, msg.raw)
+		}
+	}
+
+	resp, err = decodeMessages(body, true)
+	if err != nil {
+		t.Fatalf("decodeMessages() returned an unexpected error: %v", err)
+	}
+	for i, want := range []string{`{"type": "message", "ts": "1700000000.000100", "text": "a"}`, `{"type": "message", "ts": "1700000001.000100", "text": "b"}`} {
+		if got := string(resp.Messages[i].raw); got != want {
+			t.Errorf("Messages[%d].raw = %s, want %s", i, got, want)
+		}
+	}
+}
+
+func TestExportLog_ParallelThreads(t *testing.T) {
+	const threads = 20
+	var history []string
+	for i := 0; i < threads; i++ {
+		history = append(history, fmt.Sprintf(`{"type": "message", "user": "U0%d", "text": "thread %d", "ts": "17000%02d000.000000", "thread_ts": "17000%02d000.000000", "reply_count": 2}`, i%3, i, i, i))
+	}
+	history = append(history, `{"type": "message", "user": "U01", "text": "plain", "ts": "1700099000.000000"}`)
+
+	var inFlight, maxInFlight, usersInfoCalls atomic.Int32
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/conversations.history", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `{"ok": true, "has_more": false, "messages": [%s]}`, strings.Join(history, ","))
+	})
+	mux.HandleFunc("/api/conversations.replies", func(w http.ResponseWriter, r *http.Request) {
+		n := inFlight.Add(1)
+		defer inFlight.Add(-1)
+		for {
+			m := maxInFlight.Load()
+			if n <= m || maxInFlight.CompareAndSwap(m, n) {
+				break
+			}
+		}
+		// Earlier threads take longer, so that workers finish out of order.
+		parent := r.URL.Query().Get("ts")
+		var i int
+		fmt.Sscanf(parent, "17000%02d000", &i)
+		time.Sleep(time.Duration(threads-i) * time.Millisecond)
+		fmt.Fprintf(w, `{"ok": true, "has_more": false, "messages": [
+			{"type": "message", "user": "U00", "text": "parent", "ts": "%[1]s", "thread_ts": "%[1]s", "reply_count": 2},
+			{"type": "message", "user": "U01", "text": "reply <@U02>", "ts": "17000%02[2]d001.000000", "thread_ts": "%[1]s"},
+			{"type": "message", "user": "U02", "text": "reply", "ts": "17000%02[2]d002.000000", "thread_ts": "%[1]s"}
+		]}`, parent, i)
+	})
+	mux.HandleFunc("/api/users.info", func(w http.ResponseWriter, r *http.Request) {
+		usersInfoCalls.Add(1)
+		time.Sleep(5 * time.Millisecond)
+		userID := r.URL.Query().Get("user")
+		fmt.Fprintf(w, `{"ok": true, "user": {"id": "%s", "name": "name-%s"}}`, userID, userID)
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	runExport := func(concurrency int) (*export.ExportedLog, int) {
+		t.Helper()
+		inFlight.Store(0)
+		maxInFlight.Store(0)
+		usersInfoCalls.Store(0)
+		p := newTestProvider(server, "general")
+		var waits atomic.Int32
+		p.limiter.sleep = func(ctx context.Context, d time.Duration) error {
+			waits.Add(1)
+			return nil
+		}
+		log, err := collectLog(p, export.Options{ChannelName: "general", Concurrency: concurrency})
+		if err != nil {
+			t.Fatalf("ExportLog() with concurrency %d returned an unexpected error: %v", concurrency, err)
+		}
+		return log, int(waits.Load())
+	}
+
+	serial, _ := runExport(1)
+	if n := maxInFlight.Load(); n != 1 {
+		t.Errorf("Expected one replies request at a time with concurrency 1, got %d", n)
+	}
+	parallel, waits := runExport(8)
+	if n := maxInFlight.Load(); n < 2 || n > 8 {
+		t.Errorf("Expected between 2 and 8 replies requests at a time with concurrency 8, got %d", n)
+	}
+	// Workers share the user name cache, and concurrent lookups of a user share one call.
+	if n := usersInfoCalls.Load(); n != 3 {
+		t.Errorf("Expected 3 users.info calls, got %d", n)
+	}
+	// Workers share the rate limiter, which paces replies requests beyond the burst.
+	if waits == 0 {
+		t.Error("Expected the rate limiter to pace parallel replies requests")
+	}
+
+	if len(parallel.Messages) != threads*3+1 {
+		t.Fatalf("Expected %d messages, got %d", threads*3+1, len(parallel.Messages))
+	}
+	if !reflect.DeepEqual(parallel.Messages, serial.Messages) {
+		t.Error("Expected the parallel export to produce the same messages, in the same order, as the serial export")
+	}
+	for i := 1; i < len(parallel.Messages); i++ {
+		if parallel.Messages[i-1].TimestampUnix >= parallel.Messages[i].TimestampUnix {
+			t.Fatalf("Messages are out of order at %d: %s >= %s", i, parallel.Messages[i-1].TimestampUnix, parallel.Messages[i].TimestampUnix)
+		}
+	}
+}
+
+func TestExportLog_Downloads(t *testing.T) {
+	var downloads sync.Map // URL path to the number of requests.
+	count := func(path string) int {
+		n, _ := downloads.Load(path)
+		c, _ := n.(*atomic.Int32)
+		if c == nil {
+			return 0
+		}
+		return int(c.Load())
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/conversations.history", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"ok": true, "has_more": false, "messages": [
+			{"type": "message", "user": "U01", "text": "report", "ts": "1700000000.000000",
+			 "files": [{"id": "F01", "name": "report.txt", "size": 6, "url_private_download": "https://files.slack.com/F01"}]},
+			{"type": "message", "user": "U01", "text": "shared again", "ts": "1700000100.000000",
+			 "files": [{"id": "F01", "name": "report.txt", "size": 6, "url_private_download": "https://files.slack.com/F01"}]},
+			{"type": "message", "user": "U01", "text": "same content", "ts": "1700000200.000000",
+			 "files": [{"id": "F02", "name": "copy.txt", "size": 6, "url_private_download": "https://files.slack.com/F02"}]},
+			{"type": "message", "user": "U01", "text": "gone", "ts": "1700000300.000000",
+			 "files": [{"id": "F03", "name": "gone.txt", "url_private_download": "https://files.slack.com/F03"}]},
+			{"type": "message", "user": "U01", "text": "login page", "ts": "1700000400.000000",
+			 "files": [{"id": "F04", "name": "secret.pdf", "size": 1000, "url_private_download": "https://files.slack.com/F04"}]}
+		]}`)
+	})
+	mux.HandleFunc("/api/users.info", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"ok": true, "user": {"id": "U01", "name": "user_one"}}`)
+	})
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		n, _ := downloads.LoadOrStore(r.URL.Path, new(atomic.Int32))
+		n.(*atomic.Int32).Add(1)
+		switch r.URL.Path {
+		case "/F01", "/F02":
+			fmt.Fprint(w, "report")
+		case "/F04":
+			fmt.Fprint(w, "<html>sign in</html>")
+		default:
+			http.NotFound(w, r)
+		}
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	dir := t.TempDir()
+	run := func() *export.ExportedLog {
+		t.Helper()
+		p := newTestProvider(server, "general")
+		log, err := collectLog(p, export.Options{ChannelName: "general", IncludeFiles: true, OutputDir: dir})
+		if err != nil {
+			t.Fatalf("ExportLog() returned an unexpected error: %v", err)
+		}
+		return log
+	}
+
+	log := run()
+	const wantSum = "845e91831319e89c4d656bdb80c278ac09a7230d61e5dfd2e1b1fbb436ac8917" // SHA-256 of "report"
+	report := log.Messages[0].Files[0]
+	if report.LocalPath != filepath.Join(dir, "F01_report.txt") || report.SHA256 != wantSum || report.Size != 6 {
+		t.Errorf("Unexpected download of F01: %+v", report)
+	}
+
+	// The same file in several messages and identical content are stored once.
+	if shared := log.Messages[1].Files[0]; shared.LocalPath != report.LocalPath || shared.SHA256 != report.SHA256 {
+		t.Errorf("Expected the shared file to reuse the first download, got %+v", shared)
+	}
+	if dup := log.Messages[2].Files[0]; dup.LocalPath != report.LocalPath {
+		t.Errorf("Expected identical content to be stored once, got %+v", dup)
+	}
+	if n := count("/F01"); n != 1 {
+		t.Errorf("Expected F01 to be downloaded once, got %d requests", n)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "F02_copy.txt")); !os.IsNotExist(err) {
+		t.Errorf("Expected no separate copy of identical content, got: %v", err)
+	}
+
+	// Failed downloads keep the file metadata and record the error.
+	for i, want := range map[int]string{3: "status code 404", 4: "downloaded 20 bytes, but the file has 1000"} {
+		f := log.Messages[i].Files[0]
+		if f.LocalPath != "" || !strings.Contains(f.DownloadError, want) {
+			t.Errorf("Expected %s to fail with %q, got %+v", f.Name, want, f)
+		}
+	}
+
+	// Only complete files and the checksum lists are left in the directory.
+	entries, _ := os.ReadDir(dir)
+	var names []string
+	for _, e := range entries {
+		names = append(names, e.Name())
+	}
+	if want := []string{export.FileIDsFileName, "F01_report.txt", export.ChecksumFileName}; !reflect.DeepEqual(names, want) {
+		t.Errorf("Files directory contains %v, want %v", names, want)
+	}
+
+	// A re-run skips files whose checksum matches, including those stored
+	// under the name of identical content, and replaces damaged ones.
+	log = run()
+	if n := count("/F01"); n != 1 {
+		t.Errorf("Expected an intact F01 not to be downloaded again, got %d requests", n)
+	}
+	if n := count("/F02"); n != 1 {
+		t.Errorf("Expected F02, stored as F01, not to be downloaded again, got %d requests", n)
+	}
+	if dup := log.Messages[2].Files[0]; dup.LocalPath != report.LocalPath || dup.SHA256 != wantSum || dup.Size != 6 {
+		t.Errorf("Expected F02 to be found in F01 on a re-run, got %+v", dup)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "F01_report.txt"), []byte("damage"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	log = run()
+	if n := count("/F01"); n != 2 {
+		t.Errorf("Expected a damaged F01 to be downloaded again, got %d requests", n)
+	}
+	if got := log.Messages[0].Files[0].SHA256; got != wantSum {
+		t.Errorf("SHA256 after re-download = %s, want %s", got, wantSum)
+	}
+	if n := count("/F02"); n != 1 {
+		t.Errorf("Expected F02 to reuse the re-downloaded F01, got %d requests", n)
+	}
+}
@@ -9,17 +9,23 @@ import (
 
 // apiResponse is a generic response for checking `ok` status and errors.
 type apiResponse struct {
-	Ok    bool   `json:"ok"`
-	Error string `json:"error"`
+	Ok       bool   `json:"ok"`
+	Error    string `json:"error"`
+	Needed   string `json:"needed,omitempty"`   // Scope required by the call (missing_scope).
+	Provided string `json:"provided,omitempty"` // Scopes granted to the token (missing_scope).
 }
 
 // messagePayload is the structure for sending a message.
 type messagePayload struct {
-	Channel   string `json:"channel"`
-	Text      string `json:"text,omitempty"`
-	Username  string `json:"username,omitempty"`
-	IconEmoji string `json:"icon_emoji,omitempty"`
-	Blocks    json.RawMessage `json:"blocks,omitempty"` // New: Block Kit JSON payload
+	Channel        string          `json:"channel"`
+	Text           string          `json:"text,omitempty"`
+	Username       string          `json:"username,omitempty"`
+	IconEmoji      string          `json:"icon_emoji,omitempty"`
+	Blocks         json.RawMessage `json:"blocks,omitempty"` // New: Block Kit JSON payload
+	ThreadTS       string          `json:"thread_ts,omitempty"`
+	ReplyBroadcast bool            `json:"reply_broadcast,omitempty"`
+	PostAt         int64           `json:"post_at,omitempty"` // Unix time; set only for chat.scheduleMessage.
+	User           string          `json:"user,omitempty"`    // Recipient; set only for chat.postEphemeral.
 }
 
 // conversationsListResponse corresponds to the JSON from conversations.list API
@@ -31,8 +37,10 @@ type conversationsListResponse struct {
 }
 
 type channel struct {
-	ID   string `json:"id"`
-	Name string `json:"name"`
+	ID         string `json:"id"`
+	Name       string `json:"name"`
+	IsPrivate  bool   `json:"is_private"`
+	IsArchived bool   `json:"is_archived"`
 }
 
 // conversationsHistoryResponse corresponds to the JSON from conversations.history API
@@ -56,14 +64,73 @@ type message struct {
 	BotID           string `json:"bot_id,omitempty"`
 	ThreadTimestamp string `json:"thread_ts,omitempty"`
 	ReplyCount      int    `json:"reply_count,omitempty"`
+	LatestReply     string `json:"latest_reply,omitempty"`
+
+	ClientMsgID string          `json:"client_msg_id,omitempty"`
+	ReplyUsers  []string        `json:"reply_users,omitempty"`
+	Edited      *edited         `json:"edited,omitempty"`
+	Reactions   []reaction      `json:"reactions,omitempty"`
+	Blocks      json.RawMessage `json:"blocks,omitempty"`
+	Attachments json.RawMessage `json:"attachments,omitempty"`
+
+	// raw is the JSON the message was decoded from. It is only kept for raw
+	// exports; see decodeMessages.
+	raw json.RawMessage
+}
+
+// decodeMessages decodes a conversations.history or conversations.replies
+// response. If raw is set, the messages also keep their JSON, which costs a
+// copy of the page, so it is only done when the export asks for it.
+func decodeMessages(respBody []byte, raw bool) (*conversationsHistoryResponse, error) {
+	var resp conversationsHistoryResponse
+	if err := json.Unmarshal(respBody, &resp); err != nil {
+		return nil, err
+	}
+	if raw {
+		var rawResp struct {
+			Messages []json.RawMessage `json:"messages"`
+		}
+		if err := json.Unmarshal(respBody, &rawResp); err != nil {
+			return nil, err
+		}
+		for i := range resp.Messages {
+			if i < len(rawResp.Messages) {
+				resp.Messages[i].raw = rawResp.Messages[i]
+			}
+		}
+	}
+	return &resp, nil
+}
+
+// edited records the last edit of a message.
+type edited struct {
+	User      string `json:"user"`
+	Timestamp string `json:"ts"`
+}
+
+// reaction is an emoji reaction on a message.
+type reaction struct {
+	Name  string   `json:"name"`
+	Count int      `json:"count"`
+	Users []string `json:"users"`
 }
 
 // file represents a file object from the Slack API.
 type file struct {
 	ID                 string `json:"id"`
 	Name               string `json:"name"`
+	Title              string `json:"title,omitempty"`
 	Mimetype           string `json:"mimetype"`
+	Filetype           string `json:"filetype,omitempty"`
+	Size               int64  `json:"size,omitempty"`
+	URLPrivate         string `json:"url_private,omitempty"`
 	URLPrivateDownload string `json:"url_private_download"`
+	Permalink          string `json:"permalink,omitempty"`
+	Created            int64  `json:"created,omitempty"`
+	User               string `json:"user,omitempty"`
+	PrettyType         string `json:"pretty_type,omitempty"`
+	Mode               string `json:"mode,omitempty"`
+	IsExternal         bool   `json:"is_external,omitempty"`
 }
 
 // userInfoResponse corresponds to the JSON from users.info API
@@ -96,7 +163,8 @@ type getUploadURLExternalResponse struct {
 
 // fileInfo is used in the payload for completing a file upload.
 type fileInfo struct {
-	ID string `json:"id"`
+	ID    string `json:"id"`
+	Title string `json:"title,omitempty"`
 }
 
 // completeUploadExternalPayload is the payload for files.completeUploadExternal API
@@ -104,4 +172,5 @@ type completeUploadExternalPayload struct {
 	Files          []fileInfo `json:"files"`
 	ChannelID      string     `json:"channel_id,omitempty"`
 	InitialComment string     `json:"initial_comment,omitempty"`
+	ThreadTS       string     `json:"thread_ts,omitempty"`
 }
@@ -2,144 +2,270 @@ package slack
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"net/url"
 	"os"
-	"strings"
 
 	"github.com/nlink-jp/scat/internal/provider"
+	"github.com/nlink-jp/scat/internal/util"
 )
 
-func (p *Provider) PostFile(opts provider.PostFileOptions) error {
+func (p *Provider) PostFile(ctx context.Context, opts provider.PostFileOptions) (*provider.PostResult, error) {
+	uploads := opts.UploadFiles()
 	if p.Context.NoOp {
 		fmt.Printf("---\n")
 		fmt.Printf("Provider: slack\n")
-		fmt.Printf("Action: Upload file %s\n", opts.FilePath)
+		for _, f := range uploads {
+			if f.Content != nil {
+				fmt.Printf("Action: Upload %s (%d bytes)\n", f.Filename, f.Size)
+			} else {
+				fmt.Printf("Action: Upload file %s\n", f.Path)
+			}
+		}
 		fmt.Printf("---------------------\n")
-		return nil
-	}
-
-	// Step 1: Get Upload URL
-	fi, err := os.Stat(opts.FilePath)
-	if err != nil {
-		return fmt.Errorf("failed to get file info: %w", err)
-	}
-
-	getURLParams := url.Values{}
-	getURLParams.Add("filename", opts.Filename)
-	getURLParams.Add("length", fmt.Sprintf("%d", fi.Size()))
-
-	respBody, err := p.sendRequest("GET", getUploadURLExternalURL+"?"+getURLParams.Encode(), nil, "")
-	if err != nil {
-		return fmt.Errorf("step 1 (getUploadURLExternal) failed: %w", err)
-	}
-
-	var getURLResp getUploadURLExternalResponse
-	if err := json.Unmarshal(respBody, &getURLResp); err != nil {
-		return fmt.Errorf("failed to unmarshal getUploadURLExternal response: %w", err)
-	}
-	if !getURLResp.Ok {
-		return fmt.Errorf("slack API error on getUploadURLExternal: %s", getURLResp.Error)
-	}
-
-	// Step 2: Upload file to the provided URL
-	file, err := os.Open(opts.FilePath)
-	if err != nil {
-		return fmt.Errorf("failed to open file for upload: %w", err)
-	}
-	defer file.Close()
-
-	uploadReq, err := http.NewRequest("POST", getURLResp.UploadURL, file)
-	if err != nil {
-		return fmt.Errorf("failed to create upload request: %w", err)
+		return &provider.PostResult{}, nil
 	}
-	uploadReq.Header.Set("Content-Type", "application/octet-stream")
 
-	httpClient := &http.Client{}
-	uploadResp, err := httpClient.Do(uploadReq)
-	if err != nil {
-		return fmt.Errorf("step 2 (upload to url) failed: %w", err)
-	}
-	defer uploadResp.Body.Close()
-	if uploadResp.StatusCode != 200 {
-		body, _ := io.ReadAll(uploadResp.Body)
-		return fmt.Errorf("upload to url failed with status %d: %s", uploadResp.StatusCode, string(body))
+	// Steps 1 and 2 are repeated for every file; step 3 shares them all in a
+	// single message.
+	files := make([]fileInfo, 0, len(uploads))
+	for _, f := range uploads {
+		fileID, err := p.uploadFile(ctx, f, opts.Snippet)
+		if err != nil {
+			if len(uploads) > 1 {
+				return nil, fmt.Errorf("failed to upload %s: %w", f.Path, err)
+			}
+			return nil, err
+		}
+		files = append(files, fileInfo{ID: fileID, Title: f.Title})
 	}
 
 	// Step 3: Complete the upload
 	var channelID, destinationName string
+	var err error
 
 	switch {
 	case opts.TargetUserID != "":
 		destinationName = opts.TargetUserID
-		var userID string
-		// Check if the provided user string is a user ID or a mention name
-		if strings.HasPrefix(opts.TargetUserID, "U") || strings.HasPrefix(opts.TargetUserID, "W") {
-			userID = opts.TargetUserID
-		} else {
-			userID, err = p.ResolveUserID(opts.TargetUserID)
-			if err != nil {
-				return err
-			}
+		userID, err := p.userIDFor(ctx, opts.TargetUserID)
+		if err != nil {
+			return nil, err
 		}
-		channelID, err = p.openDMChannel(userID)
+		channelID, err = p.openDMChannel(ctx, userID)
 		if err != nil {
-			return fmt.Errorf("failed to open DM channel with user %s: %w", opts.TargetUserID, err)
+			return nil, fmt.Errorf("failed to open DM channel with user %s: %w", opts.TargetUserID, err)
 		}
 
 	case opts.TargetChannel != "":
 		destinationName = opts.TargetChannel
-		channelID, err = p.ResolveChannelID(opts.TargetChannel)
+		channelID, err = p.ResolveChannelID(ctx, opts.TargetChannel)
 		if err != nil {
-			return err
+			return nil, err
 		}
 
 	default:
 		destinationName = p.Profile.Channel
 		if destinationName == "" {
-			return fmt.Errorf("no channel or user specified; please set a default channel in the profile or use the --channel or --user flag")
+			return nil, fmt.Errorf("no channel or user specified; please set a default channel in the profile or use the --channel or --user flag")
 		}
-		channelID, err = p.ResolveChannelID(p.Profile.Channel)
+		channelID, err = p.ResolveChannelID(ctx, p.Profile.Channel)
 		if err != nil {
-			return err
+			return nil, err
 		}
 	}
 
 	completePayload := completeUploadExternalPayload{
-		Files:          []fileInfo{{ID: getURLResp.FileID}},
+		Files:          files,
 		ChannelID:      channelID,
 		InitialComment: opts.Comment,
+		ThreadTS:       opts.ThreadTS,
 	}
 	completePayloadBytes, err := json.Marshal(completePayload)
 	if err != nil {
-		return fmt.Errorf("failed to marshal completeUploadExternal payload: %w", err)
+		return nil, fmt.Errorf("failed to marshal completeUploadExternal payload: %w", err)
 	}
 
-	_, err = p.sendRequest("POST", completeUploadExternalURL, bytes.NewBuffer(completePayloadBytes), "application/json; charset=utf-8")
+	_, err = p.sendRequest(ctx, "POST", completeUploadExternalURL, bytes.NewBuffer(completePayloadBytes), "application/json; charset=utf-8")
 	if err != nil {
 		// Check if the error is 'not_in_channel' and retry if so.
-		if opts.TargetUserID == "" && strings.Contains(err.Error(), "not_in_channel") {
+		if opts.TargetUserID == "" && errors.Is(err, provider.ErrNotInChannel) {
 			if !p.Context.Silent {
 				fmt.Fprintf(os.Stderr, "Bot not in channel '%s'. Attempting to join...\n", destinationName)
 			}
-			if joinErr := p.joinChannel(channelID); joinErr != nil {
-				return fmt.Errorf("failed to join channel '%s': %w", destinationName, joinErr)
+			if joinErr := p.joinChannel(ctx, channelID); joinErr != nil {
+				return nil, fmt.Errorf("failed to join channel '%s': %w", destinationName, joinErr)
 			}
 			if !p.Context.Silent {
 				fmt.Fprintf(os.Stderr, "Successfully joined channel '%s'. Retrying file upload completion...\n", destinationName)
 			}
 			// Retry completing the upload after joining.
-			_, retryErr := p.sendRequest("POST", completeUploadExternalURL, bytes.NewBuffer(completePayloadBytes), "application/json; charset=utf-8")
+			_, retryErr := p.sendRequest(ctx, "POST", completeUploadExternalURL, bytes.NewBuffer(completePayloadBytes), "application/json; charset=utf-8")
 			if retryErr != nil {
-				return fmt.Errorf("step 3 (completeUploadExternal) failed on retry: %w", retryErr)
+				return nil, fmt.Errorf("step 3 (completeUploadExternal) failed on retry: %w", retryErr)
 			}
-			return nil // Success on retry
+		} else {
+			return nil, fmt.Errorf("step 3 (completeUploadExternal) failed: %w", err)
 		}
-		return fmt.Errorf("step 3 (completeUploadExternal) failed: %w", err)
 	}
 
+	result := p.describeUpload(ctx, files[0].ID, channelID)
+	if len(files) > 1 {
+		for _, f := range files {
+			result.FileIDs = append(result.FileIDs, f.ID)
+		}
+	}
+	return result, nil
+}
+
+// uploadFile reserves an upload URL for f and sends its content (steps 1 and
+// 2 of the external upload flow). It returns the file ID to pass to
+// files.completeUploadExternal. Content larger than util.ProgressMinSize is
+// uploaded with a progress bar on stderr unless the context is silent.
+//
+// If snippet is set, the file becomes a snippet highlighted as f.Filetype,
+// or as the filetype detected from its name and content.
+func (p *Provider) uploadFile(ctx context.Context, f provider.UploadFile, snippet bool) (string, error) {
+	content, size := f.Content, f.Size
+	if content == nil {
+		file, err := os.Open(f.Path)
+		if err != nil {
+			return "", fmt.Errorf("failed to open file for upload: %w", err)
+		}
+		defer file.Close()
+		fi, err := file.Stat()
+		if err != nil {
+			return "", fmt.Errorf("failed to get file info: %w", err)
+		}
+		content, size = file, fi.Size()
+	}
+
+	// Step 1: Get Upload URL
+	getURLParams := url.Values{}
+	getURLParams.Add("filename", f.Filename)
+	getURLParams.Add("length", fmt.Sprintf("%d", size))
+	if f.AltText != "" {
+		getURLParams.Add("alt_txt", f.AltText)
+	}
+	if snippet {
+		snippetType, err := snippetTypeFor(f, content)
+		if err != nil {
+			return "", err
+		}
+		if p.Context.Debug {
+			fmt.Fprintf(os.Stderr, "[DEBUG] Uploading %s as a snippet of type %q\n", f.Filename, snippetType)
+		}
+		if snippetType != "" {
+			getURLParams.Add("snippet_type", snippetType)
+		}
+	}
+
+	respBody, err := p.sendRequest(ctx, "GET", getUploadURLExternalURL+"?"+getURLParams.Encode(), nil, "")
+	if err != nil {
+		return "", fmt.Errorf("step 1 (getUploadURLExternal) failed: %w", err)
+	}
+
+	var getURLResp getUploadURLExternalResponse
+	if err := json.Unmarshal(respBody, &getURLResp); err != nil {
+		return "", fmt.Errorf("failed to unmarshal getUploadURLExternal response: %w", err)
+	}
+	if !getURLResp.Ok {
+		return "", fmt.Errorf("slack API error on getUploadURLExternal: %s", getURLResp.Error)
+	}
+
+	// Step 2: Upload file to the provided URL
+	err = p.withRetry(ctx, "file upload", func() error {
+		if _, err := content.Seek(0, io.SeekStart); err != nil {
+			return fmt.Errorf("failed to rewind file for upload: %w", err)
+		}
+		// The HTTP client closes request bodies, so hide Close to keep the
+		// content usable for a retry.
+		var body io.Reader = content
+		if !p.Context.Silent && size >= util.ProgressMinSize {
+			body = util.NewProgressReader(content, os.Stderr, f.Filename, size)
+		}
+		return p.uploadToURL(ctx, getURLResp.UploadURL, io.NopCloser(body), size)
+	})
+	if err != nil {
+		return "", fmt.Errorf("step 2 (upload to url) failed: %w", err)
+	}
+	return getURLResp.FileID, nil
+}
+
+// snippetTypeFor returns the snippet type for f: its Filetype, or the filetype
+// detected from its name and the beginning of content. Content that does not
+// look like text cannot become a snippet. content is read from the start and
+// left at an unspecified offset.
+func snippetTypeFor(f provider.UploadFile, content io.ReadSeeker) (string, error) {
+	if _, err := content.Seek(0, io.SeekStart); err != nil {
+		return "", fmt.Errorf("failed to rewind file: %w", err)
+	}
+	head := make([]byte, util.SniffLen)
+	n, err := io.ReadFull(content, head)
+	if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+		return "", fmt.Errorf("failed to read file: %w", err)
+	}
+	head = head[:n]
+	if !util.IsText(head) {
+		return "", fmt.Errorf("cannot upload %s as a snippet: it does not look like text", f.Filename)
+	}
+	if f.Filetype != "" {
+		return f.Filetype, nil
+	}
+	return util.DetectFiletype(f.Filename, head), nil
+}
+
+// describeUpload builds the PostResult for a completed upload. Slack shares the
+// file asynchronously, so the message timestamp may not be known yet; lookup
+// failures (e.g. a token without files:read) only leave fields empty.
+func (p *Provider) describeUpload(ctx context.Context, fileID, channelID string) *provider.PostResult {
+	result := &provider.PostResult{ChannelID: channelID, FileID: fileID}
+
+	ts, filePermalink, err := p.getFileShare(ctx, fileID, channelID)
+	if err != nil {
+		if p.Context.Debug {
+			fmt.Fprintf(os.Stderr, "[DEBUG] Could not look up shares of file %s: %v\n", fileID, err)
+		}
+		return result
+	}
+	result.Timestamp = ts
+	result.Permalink = filePermalink
+	if ts != "" {
+		if permalink, err := p.getPermalink(ctx, channelID, ts); err == nil {
+			result.Permalink = permalink
+		} else if p.Context.Debug {
+			fmt.Fprintf(os.Stderr, "[DEBUG] Could not get permalink for message %s: %v\n", ts, err)
+		}
+	}
+	return result
+}
+
+// uploadToURL sends the file content to the upload URL returned by files.getUploadURLExternal.
+// Network errors and 5xx responses are reported as transient so that the caller can retry.
+func (p *Provider) uploadToURL(ctx context.Context, uploadURL string, content io.Reader, size int64) error {
+	uploadReq, err := http.NewRequestWithContext(ctx, "POST", uploadURL, content)
+	if err != nil {
+		return fmt.Errorf("failed to create upload request: %w", err)
+	}
+	uploadReq.ContentLength = size
+	uploadReq.Header.Set("Content-Type", "application/octet-stream")
+
+	uploadResp, err := p.httpClient.Do(uploadReq)
+	if err != nil {
+		return networkError(ctx, err)
+	}
+	defer uploadResp.Body.Close()
+	if uploadResp.StatusCode != 200 {
+		body, _ := io.ReadAll(uploadResp.Body)
+		err := fmt.Errorf("upload to url failed with status %d: %s", uploadResp.StatusCode, string(body))
+		if uploadResp.StatusCode >= 500 {
+			return &transientError{err}
+		}
+		return err
+	}
 	return nil
 }
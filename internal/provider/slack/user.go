@@ -1,6 +1,7 @@
 package slack
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"os"
@@ -10,8 +11,8 @@ import (
 )
 
 // populateUserCache fetches all users and populates the userIDCache.
-func (p *Provider) populateUserCache() error {
-	users, err := p.getUsers()
+func (p *Provider) populateUserCache(ctx context.Context) error {
+	users, err := p.getUsers(ctx)
 	if err != nil {
 		return fmt.Errorf("failed to get users for cache: %w", err)
 	}
@@ -32,7 +33,7 @@ func (p *Provider) populateUserCache() error {
 
 // ResolveUserID finds a user ID for a given user name.
 // It checks the cache first, and repopulates it if the user is not found.
-func (p *Provider) ResolveUserID(userName string) (string, error) {
+func (p *Provider) ResolveUserID(ctx context.Context, userName string) (string, error) {
 	cleanUserName := strings.TrimPrefix(userName, "@")
 
 	id, ok := p.userIDCache[cleanUserName]
@@ -44,21 +45,30 @@ func (p *Provider) ResolveUserID(userName string) (string, error) {
 	if p.Context.Debug {
 		fmt.Fprintf(os.Stderr, "[DEBUG] User '%s' not found in cache, repopulating...\n", cleanUserName)
 	}
-	if err := p.populateUserCache(); err != nil {
+	if err := p.populateUserCache(ctx); err != nil {
 		return "", fmt.Errorf("failed to repopulate user cache: %w", err)
 	}
 
 	id, ok = p.userIDCache[cleanUserName]
 	if !ok {
-		return "", fmt.Errorf("user '%s' not found", userName)
+		return "", fmt.Errorf("%w: '%s'", provider.ErrUserNotFound, userName)
 	}
 
 	return id, nil
 }
 
+// userIDFor returns user as-is if it is already a user ID, and resolves it
+// as a user name otherwise.
+func (p *Provider) userIDFor(ctx context.Context, user string) (string, error) {
+	if strings.HasPrefix(user, "U") || strings.HasPrefix(user, "W") {
+		return user, nil
+	}
+	return p.ResolveUserID(ctx, user)
+}
+
 // ListUsers returns all non-bot, non-deleted users in the workspace.
-func (p *Provider) ListUsers() ([]provider.UserInfo, error) {
-	users, err := p.getUsers()
+func (p *Provider) ListUsers(ctx context.Context) ([]provider.UserInfo, error) {
+	users, err := p.getUsers(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -70,7 +80,7 @@ func (p *Provider) ListUsers() ([]provider.UserInfo, error) {
 }
 
 // getUsers fetches all non-bot, non-deleted users from the workspace.
-func (p *Provider) getUsers() ([]struct{ ID, Name string }, error) {
+func (p *Provider) getUsers(ctx context.Context) ([]struct{ ID, Name string }, error) {
 	if p.Context.Debug {
 		fmt.Fprintf(os.Stderr, "[DEBUG] Fetching users by calling users.list...\n")
 	}
@@ -79,7 +89,7 @@ func (p *Provider) getUsers() ([]struct{ ID, Name string }, error) {
 
 	for {
 		url := fmt.Sprintf("%s?cursor=%s&limit=200", usersListURL, cursor)
-		body, err := p.sendRequest("GET", url, nil, "")
+		body, err := p.sendRequest(ctx, "GET", url, nil, "")
 		if err != nil {
 			return nil, err
 		}
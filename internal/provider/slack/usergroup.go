@@ -1,14 +1,15 @@
 package slack
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"strings"
 )
 
 // populateUserGroupCache fetches all user groups and populates the userGroupIDCache.
-func (p *Provider) populateUserGroupCache() error {
-	userGroups, err := p.getUserGroups()
+func (p *Provider) populateUserGroupCache(ctx context.Context) error {
+	userGroups, err := p.getUserGroups(ctx)
 	if err != nil {
 		return fmt.Errorf("failed to get user groups for cache: %w", err)
 	}
@@ -29,7 +30,7 @@ func (p *Provider) populateUserGroupCache() error {
 
 // ResolveUserGroupID finds a user group ID for a given user group handle.
 // It checks the cache first, and repopulates it if the user group is not found.
-func (p *Provider) ResolveUserGroupID(handle string) (string, error) {
+func (p *Provider) ResolveUserGroupID(ctx context.Context, handle string) (string, error) {
 	cleanHandle := strings.TrimPrefix(handle, "@")
 
 	id, ok := p.userGroupIDCache[cleanHandle]
@@ -41,7 +42,7 @@ func (p *Provider) ResolveUserGroupID(handle string) (string, error) {
 	if p.Context.Debug {
 		fmt.Fprintf(os.Stderr, "[DEBUG] User group '%s' not found in cache, repopulating...\n", cleanHandle)
 	}
-	if err := p.populateUserGroupCache(); err != nil {
+	if err := p.populateUserGroupCache(ctx); err != nil {
 		return "", fmt.Errorf("failed to repopulate user group cache: %w", err)
 	}
 
@@ -1,6 +1,7 @@
 package slack
 
 import (
+	"context"
 	"encoding/json"
 	"regexp"
 	"sync"
@@ -8,18 +9,62 @@ import (
 
 var mentionRegex = regexp.MustCompile(`<@(U[A-Z0-9]+)>`)
 
-func (p *Provider) resolveUserName(userID string, cache map[string]string, mu *sync.Mutex) (string, error) {
+// userNameCache maps user IDs to names. It is safe for concurrent use, and
+// concurrent lookups of the same user share a single users.info call.
+type userNameCache struct {
+	mu       sync.Mutex
+	names    map[string]string
+	inflight map[string]*userNameLookup
+}
+
+// userNameLookup is a users.info call in progress.
+type userNameLookup struct {
+	done chan struct{}
+	name string
+	err  error
+}
+
+func (p *Provider) resolveUserName(ctx context.Context, userID string, cache *userNameCache) (string, error) {
 	if userID == "" {
 		return "", nil
 	}
-	mu.Lock()
-	name, ok := cache[userID]
-	mu.Unlock()
-	if ok {
+	cache.mu.Lock()
+	if name, ok := cache.names[userID]; ok {
+		cache.mu.Unlock()
 		return name, nil
 	}
+	if lookup, ok := cache.inflight[userID]; ok {
+		cache.mu.Unlock()
+		select {
+		case <-lookup.done:
+			return lookup.name, lookup.err
+		case <-ctx.Done():
+			return "", ctx.Err()
+		}
+	}
+	if cache.names == nil {
+		cache.names = make(map[string]string)
+		cache.inflight = make(map[string]*userNameLookup)
+	}
+	lookup := &userNameLookup{done: make(chan struct{})}
+	cache.inflight[userID] = lookup
+	cache.mu.Unlock()
+
+	lookup.name, lookup.err = p.lookupUserName(ctx, userID)
+
+	cache.mu.Lock()
+	delete(cache.inflight, userID)
+	if lookup.err == nil {
+		cache.names[userID] = lookup.name
+	}
+	cache.mu.Unlock()
+	close(lookup.done)
+	return lookup.name, lookup.err
+}
 
-	respBody, err := p.sendRequest("GET", usersInfoURL+"?user="+userID, nil, "")
+// lookupUserName fetches the name of a user from users.info.
+func (p *Provider) lookupUserName(ctx context.Context, userID string) (string, error) {
+	respBody, err := p.sendRequest(ctx, "GET", usersInfoURL+"?user="+userID, nil, "")
 	if err != nil {
 		return "", err
 	}
@@ -28,22 +73,18 @@ func (p *Provider) resolveUserName(userID string, cache map[string]string, mu *s
 		return "", err
 	}
 
-	name = userInfoResp.User.RealName
+	name := userInfoResp.User.RealName
 	if name == "" {
 		name = userInfoResp.User.Name
 	}
-
-	mu.Lock()
-	cache[userID] = name
-	mu.Unlock()
 	return name, nil
 }
 
-func (p *Provider) resolveMentions(text string, cache map[string]string, mu *sync.Mutex) (string, error) {
+func (p *Provider) resolveMentions(ctx context.Context, text string, cache *userNameCache) (string, error) {
 	var firstErr error
 	resolvedText := mentionRegex.ReplaceAllStringFunc(text, func(match string) string {
 		userID := mentionRegex.FindStringSubmatch(match)[1]
-		userName, err := p.resolveUserName(userID, cache, mu)
+		userName, err := p.resolveUserName(ctx, userID, cache)
 		if err != nil {
 			if firstErr == nil {
 				firstErr = err
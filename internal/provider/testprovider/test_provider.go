@@ -1,7 +1,10 @@
 package testprovider
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"time"
@@ -14,6 +17,17 @@ import (
 
 var PostMessageSignal chan struct{}
 
+// Fixed values returned by PostMessage and PostFile so that tests can assert on them.
+const (
+	TestChannelID = "C1234567890"
+	TestMessageTS = "1672531200.000100"
+	TestFileID    = "F12345678"
+	TestPermalink = "https://test.slack.com/archives/C1234567890/p1672531200000100"
+
+	TestScheduledMessageID = "Q1234ABCD"
+	TestExportMessageTS    = "1672531200.000000"
+)
+
 // Provider implements the provider.Interface for testing purposes.
 type Provider struct {
 	Profile config.Profile
@@ -31,34 +45,58 @@ func NewProvider(p config.Profile, ctx appcontext.Context) (provider.Interface,
 func (p *Provider) Capabilities() provider.Capabilities {
 	fmt.Fprintf(os.Stderr, "[TESTPROVIDER] Capabilities called\n")
 	return provider.Capabilities{
-		CanListChannels:    true,
-		CanListUsers:       true,
-		CanPostFile:        true,
-		CanUseIconEmoji:    true,
-		CanExportLogs:      true,
-		CanPostBlocks:      true,
-		CanCreateChannel:   true,
-		CanInviteToChannel: true,
+		CanListChannels:     true,
+		CanListUsers:        true,
+		CanPostFile:         true,
+		CanUseIconEmoji:     true,
+		CanExportLogs:       true,
+		CanPostBlocks:       true,
+		CanCreateChannel:    true,
+		CanInviteToChannel:  true,
+		CanEditMessages:     true,
+		CanScheduleMessages: true,
+		CanReact:            true,
+		CanPostEphemeral:    true,
+		CanPin:              true,
 	}
 }
 
 // PostMessage logs the message options to stderr.
-func (p *Provider) PostMessage(opts provider.PostMessageOptions) error {
+func (p *Provider) PostMessage(ctx context.Context, opts provider.PostMessageOptions) (*provider.PostResult, error) {
 	if opts.Text == `{"test_command": "signal_done"}` {
 		if PostMessageSignal != nil {
 			PostMessageSignal <- struct{}{}
 		}
-		return nil
+		return &provider.PostResult{}, nil
 	}
 
 	// Note: No complex logic for channel/user resolution in test provider.
 	// We just log the raw options to verify that the command layer is sending them correctly.
 	fmt.Fprintf(os.Stderr, "[TESTPROVIDER] PostMessage called with opts: {TargetChannel:%s TargetUserID:%s Text:%s OverrideUsername:%s IconEmoji:%s Blocks:%s}\n", opts.TargetChannel, opts.TargetUserID, opts.Text, opts.OverrideUsername, opts.IconEmoji, string(opts.Blocks))
-	return nil
+	if opts.ThreadTS != "" || opts.ReplyBroadcast {
+		fmt.Fprintf(os.Stderr, "[TESTPROVIDER] PostMessage thread: {ThreadTS:%s ReplyBroadcast:%t}\n", opts.ThreadTS, opts.ReplyBroadcast)
+	}
+	if opts.EphemeralUserID != "" {
+		fmt.Fprintf(os.Stderr, "[TESTPROVIDER] PostMessage ephemeral: {EphemeralUserID:%s}\n", opts.EphemeralUserID)
+		return &provider.PostResult{ChannelID: TestChannelID}, nil
+	}
+	if !opts.PostAt.IsZero() {
+		fmt.Fprintf(os.Stderr, "[TESTPROVIDER] PostMessage schedule: {PostAt:%d}\n", opts.PostAt.Unix())
+		return &provider.PostResult{
+			ChannelID:          TestChannelID,
+			ScheduledMessageID: TestScheduledMessageID,
+			PostAt:             opts.PostAt,
+		}, nil
+	}
+	return &provider.PostResult{
+		ChannelID: TestChannelID,
+		Timestamp: TestMessageTS,
+		Permalink: TestPermalink,
+	}, nil
 }
 
 // PostFile logs the file options to stderr.
-func (p *Provider) PostFile(opts provider.PostFileOptions) error {
+func (p *Provider) PostFile(ctx context.Context, opts provider.PostFileOptions) (*provider.PostResult, error) {
 	// Create a temporary struct for logging that includes all relevant fields.
 	logOpts := struct {
 		TargetChannel    string
@@ -81,20 +119,121 @@ func (p *Provider) PostFile(opts provider.PostFileOptions) error {
 	}
 
 	fmt.Fprintf(os.Stderr, "[TESTPROVIDER] PostFile called with opts: %+v\n", logOpts)
+	if opts.ThreadTS != "" {
+		fmt.Fprintf(os.Stderr, "[TESTPROVIDER] PostFile thread: {ThreadTS:%s}\n", opts.ThreadTS)
+	}
+	if opts.Title != "" {
+		fmt.Fprintf(os.Stderr, "[TESTPROVIDER] PostFile title: {Title:%s}\n", opts.Title)
+	}
+	if opts.AltText != "" {
+		fmt.Fprintf(os.Stderr, "[TESTPROVIDER] PostFile alt text: {AltText:%s}\n", opts.AltText)
+	}
+	if opts.Snippet {
+		fmt.Fprintf(os.Stderr, "[TESTPROVIDER] PostFile snippet: {Snippet:%t}\n", opts.Snippet)
+	}
+	if opts.Content != nil {
+		content, err := io.ReadAll(opts.Content)
+		if err != nil {
+			return nil, err
+		}
+		fmt.Fprintf(os.Stderr, "[TESTPROVIDER] PostFile content: {Size:%d Content:%s}\n", opts.Size, content)
+	}
+	for _, f := range opts.Files {
+		fmt.Fprintf(os.Stderr, "[TESTPROVIDER] PostFile file: {Path:%s Filename:%s Title:%s}\n", f.Path, f.Filename, f.Title)
+		if f.AltText != "" {
+			fmt.Fprintf(os.Stderr, "[TESTPROVIDER] PostFile file alt text: {Filename:%s AltText:%s}\n", f.Filename, f.AltText)
+		}
+	}
+	return &provider.PostResult{
+		ChannelID: TestChannelID,
+		Timestamp: TestMessageTS,
+		FileID:    TestFileID,
+		Permalink: TestPermalink,
+	}, nil
+}
+
+// UpdateMessage logs the update options to stderr.
+func (p *Provider) UpdateMessage(ctx context.Context, opts provider.UpdateMessageOptions) (*provider.PostResult, error) {
+	fmt.Fprintf(os.Stderr, "[TESTPROVIDER] UpdateMessage called with opts: {Channel:%s Timestamp:%s Text:%s Blocks:%s}\n", opts.Channel, opts.Timestamp, opts.Text, string(opts.Blocks))
+	return &provider.PostResult{
+		ChannelID: TestChannelID,
+		Timestamp: opts.Timestamp,
+		Permalink: TestPermalink,
+	}, nil
+}
+
+// DeleteMessage logs the delete options to stderr.
+func (p *Provider) DeleteMessage(ctx context.Context, opts provider.DeleteMessageOptions) error {
+	fmt.Fprintf(os.Stderr, "[TESTPROVIDER] DeleteMessage called with opts: %+v\n", opts)
+	return nil
+}
+
+// ListScheduledMessages logs the call and returns dummy data.
+func (p *Provider) ListScheduledMessages(ctx context.Context, opts provider.ListScheduledMessagesOptions) ([]provider.ScheduledMessage, error) {
+	fmt.Fprintf(os.Stderr, "[TESTPROVIDER] ListScheduledMessages called with opts: %+v\n", opts)
+	return []provider.ScheduledMessage{
+		{ID: TestScheduledMessageID, ChannelID: TestChannelID, PostAt: time.Unix(1767225600, 0), Text: "Happy new year"},
+	}, nil
+}
+
+// DeleteScheduledMessage logs the delete options to stderr.
+func (p *Provider) DeleteScheduledMessage(ctx context.Context, opts provider.DeleteScheduledMessageOptions) error {
+	fmt.Fprintf(os.Stderr, "[TESTPROVIDER] DeleteScheduledMessage called with opts: %+v\n", opts)
 	return nil
 }
 
+// AddReaction logs the reaction options to stderr.
+func (p *Provider) AddReaction(ctx context.Context, opts provider.ReactionOptions) error {
+	fmt.Fprintf(os.Stderr, "[TESTPROVIDER] AddReaction called with opts: %+v\n", opts)
+	return nil
+}
+
+// RemoveReaction logs the reaction options to stderr.
+func (p *Provider) RemoveReaction(ctx context.Context, opts provider.ReactionOptions) error {
+	fmt.Fprintf(os.Stderr, "[TESTPROVIDER] RemoveReaction called with opts: %+v\n", opts)
+	return nil
+}
+
+// ListReactions logs the call and returns dummy data.
+func (p *Provider) ListReactions(ctx context.Context, opts provider.ListReactionsOptions) ([]provider.Reaction, error) {
+	fmt.Fprintf(os.Stderr, "[TESTPROVIDER] ListReactions called with opts: %+v\n", opts)
+	return []provider.Reaction{
+		{Name: "white_check_mark", Count: 2, Users: []string{"U0000000001", "U0000000002"}},
+		{Name: "eyes", Count: 1, Users: []string{"U0000000001"}},
+	}, nil
+}
+
+// AddPin logs the pin options to stderr.
+func (p *Provider) AddPin(ctx context.Context, opts provider.PinOptions) error {
+	fmt.Fprintf(os.Stderr, "[TESTPROVIDER] AddPin called with opts: %+v\n", opts)
+	return nil
+}
+
+// RemovePin logs the pin options to stderr.
+func (p *Provider) RemovePin(ctx context.Context, opts provider.PinOptions) error {
+	fmt.Fprintf(os.Stderr, "[TESTPROVIDER] RemovePin called with opts: %+v\n", opts)
+	return nil
+}
+
+// ListPins logs the call and returns dummy data.
+func (p *Provider) ListPins(ctx context.Context, opts provider.ListPinsOptions) ([]provider.PinnedMessage, error) {
+	fmt.Fprintf(os.Stderr, "[TESTPROVIDER] ListPins called with opts: %+v\n", opts)
+	return []provider.PinnedMessage{
+		{ChannelID: TestChannelID, Timestamp: TestMessageTS, UserID: "U0000000001", Text: "Release notes v1.15.0", Permalink: TestPermalink, PinnedBy: "U0000000002", PinnedAt: time.Unix(1672531300, 0)},
+	}, nil
+}
+
 // ListChannels logs the call and returns dummy data.
-func (p *Provider) ListChannels() ([]provider.Channel, error) {
+func (p *Provider) ListChannels(ctx context.Context) ([]provider.Channel, error) {
 	fmt.Fprintf(os.Stderr, "[TESTPROVIDER] ListChannels called\n")
 	return []provider.Channel{
 		{ID: "C0000000001", Name: "test-channel-1"},
-		{ID: "C0000000002", Name: "test-channel-2"},
+		{ID: "C0000000002", Name: "test-channel-2", IsArchived: true},
 	}, nil
 }
 
 // ListUsers logs the call and returns dummy data.
-func (p *Provider) ListUsers() ([]provider.UserInfo, error) {
+func (p *Provider) ListUsers(ctx context.Context) ([]provider.UserInfo, error) {
 	fmt.Fprintf(os.Stderr, "[TESTPROVIDER] ListUsers called\n")
 	return []provider.UserInfo{
 		{ID: "U0000000001", Name: "test-user-1"},
@@ -103,20 +242,46 @@ func (p *Provider) ListUsers() ([]provider.UserInfo, error) {
 }
 
 // InviteToChannel logs the call.
-func (p *Provider) InviteToChannel(opts provider.InviteToChannelOptions) error {
+func (p *Provider) InviteToChannel(ctx context.Context, opts provider.InviteToChannelOptions) error {
 	fmt.Fprintf(os.Stderr, "[TESTPROVIDER] InviteToChannel called with opts: %+v\n", opts)
 	return nil
 }
 
-// ExportLog logs the export options and returns dummy data that reflects the options.
-func (p *Provider) ExportLog(opts export.Options) (*export.ExportedLog, error) {
-	fmt.Fprintf(os.Stderr, "[TESTPROVIDER] ExportLog called with opts: %+v\n", opts)
+// ExportLog logs the export options and hands over dummy data that reflects the options.
+func (p *Provider) ExportLog(ctx context.Context, opts export.Options, onPage export.PageFunc) (*export.LogInfo, error) {
+	logOpts := struct {
+		ChannelName  string
+		StartTime    string
+		EndTime      string
+		IncludeFiles bool
+		OutputDir    string
+	}{
+		ChannelName:  opts.ChannelName,
+		StartTime:    opts.StartTime,
+		EndTime:      opts.EndTime,
+		IncludeFiles: opts.IncludeFiles,
+		OutputDir:    opts.OutputDir,
+	}
+	fmt.Fprintf(os.Stderr, "[TESTPROVIDER] ExportLog called with opts: %+v\n", logOpts)
+	if opts.Concurrency > 1 {
+		fmt.Fprintf(os.Stderr, "[TESTPROVIDER] ExportLog concurrency: %d\n", opts.Concurrency)
+	}
+	if opts.IncludeRaw {
+		fmt.Fprintln(os.Stderr, "[TESTPROVIDER] ExportLog include raw: true")
+	}
+	if opts.Cursor != "" || opts.ExportedUntil != "" || len(opts.Threads) > 0 {
+		fmt.Fprintf(os.Stderr, "[TESTPROVIDER] ExportLog incremental: {Cursor:%s ExportedUntil:%s Threads:%d}\n", opts.Cursor, opts.ExportedUntil, len(opts.Threads))
+	}
 
 	// Create a dummy message
 	message := export.ExportedMessage{
-		Text:      "Test message from ExportLog",
-		UserName:  "testuser",
-		Timestamp: "1672531200.000000", // 2023-01-01 00:00:00 UTC
+		Text:          "Test message from ExportLog",
+		UserName:      "testuser",
+		Timestamp:     "1672531200.000000", // 2023-01-01 00:00:00 UTC
+		TimestampUnix: TestExportMessageTS,
+	}
+	if opts.IncludeRaw {
+		message.Raw = json.RawMessage(`{"type":"message","ts":"` + TestExportMessageTS + `","text":"Test message from ExportLog"}`)
 	}
 
 	// If file export is requested, add dummy file info
@@ -131,15 +296,23 @@ func (p *Provider) ExportLog(opts export.Options) (*export.ExportedLog, error) {
 		}
 	}
 
-	return &export.ExportedLog{
+	messages := []export.ExportedMessage{message}
+	// Like a real incremental export, skip what an earlier run exported.
+	if opts.ExportedUntil != "" && message.TimestampUnix <= opts.ExportedUntil {
+		messages = nil
+	}
+
+	if err := onPage(export.Page{Messages: messages}); err != nil {
+		return nil, err
+	}
+	return &export.LogInfo{
 		ChannelName:     opts.ChannelName,
 		ExportTimestamp: time.Now().UTC().Format(time.RFC3339),
-		Messages:        []export.ExportedMessage{message},
 	}, nil
 }
 
 // CreateChannel logs the call and returns a dummy channel ID.
-func (p *Provider) CreateChannel(opts provider.CreateChannelOptions) (string, error) {
+func (p *Provider) CreateChannel(ctx context.Context, opts provider.CreateChannelOptions) (string, error) {
 	fmt.Fprintf(os.Stderr, "[TESTPROVIDER] CreateChannel called with opts: %+v\n", opts)
 	return "C1234567890", nil
 }
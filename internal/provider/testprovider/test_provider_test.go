@@ -2,6 +2,7 @@ package testprovider
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"io"
 	"log"
@@ -71,6 +72,9 @@ func TestCapabilities(t *testing.T) {
 	if !caps.CanExportLogs {
 		t.Error("Expected CanExportLogs to be true")
 	}
+	if !caps.CanEditMessages {
+		t.Error("Expected CanEditMessages to be true")
+	}
 }
 
 func TestPostMessage(t *testing.T) {
@@ -78,7 +82,7 @@ func TestPostMessage(t *testing.T) {
 	opts := provider.PostMessageOptions{Text: "hello test"}
 
 	output := captureStderr(func() {
-		err := p.PostMessage(opts)
+		_, err := p.PostMessage(context.Background(), opts)
 		if err != nil {
 			t.Errorf("PostMessage() error = %v", err)
 		}
@@ -100,7 +104,7 @@ func TestPostMessage_WithBlocks(t *testing.T) {
 	opts := provider.PostMessageOptions{Blocks: blocksJSON}
 
 	output := captureStderr(func() {
-		err := p.PostMessage(opts)
+		_, err := p.PostMessage(context.Background(), opts)
 		if err != nil {
 			t.Errorf("PostMessage() error = %v", err)
 		}
@@ -124,7 +128,7 @@ func TestListChannels(t *testing.T) {
 
 	output := captureStderr(func() {
 		var channels []provider.Channel
-		channels, err = p.ListChannels()
+		channels, err = p.ListChannels(context.Background())
 		if err != nil {
 			return
 		}
@@ -152,7 +156,9 @@ func TestExportLog(t *testing.T) {
 	var err error
 
 	output := captureStderr(func() {
-		logData, err = p.ExportLog(opts)
+		logData, err = export.Collect(func(onPage export.PageFunc) (*export.LogInfo, error) {
+			return p.ExportLog(context.Background(), opts, onPage)
+		})
 	})
 
 	if err != nil {
@@ -177,3 +183,41 @@ func TestExportLog(t *testing.T) {
 		t.Error("Expected non-empty files list when IncludeFiles is true")
 	}
 }
+
+func TestUpdateMessage(t *testing.T) {
+	p, _ := NewProvider(config.Profile{}, appcontext.Context{})
+	opts := provider.UpdateMessageOptions{Channel: "#general", Timestamp: "1700000000.000100", Text: "updated"}
+
+	var result *provider.PostResult
+	output := captureStderr(func() {
+		var err error
+		result, err = p.UpdateMessage(context.Background(), opts)
+		if err != nil {
+			t.Errorf("UpdateMessage() error = %v", err)
+		}
+	})
+
+	expected := "[TESTPROVIDER] UpdateMessage called with opts: {Channel:#general Timestamp:1700000000.000100 Text:updated Blocks:}"
+	if !strings.Contains(output, expected) {
+		t.Errorf("Expected output to contain '%s', got: %s", expected, output)
+	}
+	if result == nil || result.Timestamp != opts.Timestamp {
+		t.Errorf("Expected the result to carry the message timestamp, got: %+v", result)
+	}
+}
+
+func TestDeleteMessage(t *testing.T) {
+	p, _ := NewProvider(config.Profile{}, appcontext.Context{})
+	opts := provider.DeleteMessageOptions{Channel: "#general", Timestamp: "1700000000.000100"}
+
+	output := captureStderr(func() {
+		if err := p.DeleteMessage(context.Background(), opts); err != nil {
+			t.Errorf("DeleteMessage() error = %v", err)
+		}
+	})
+
+	expected := "[TESTPROVIDER] DeleteMessage called with opts: {Channel:#general Timestamp:1700000000.000100}"
+	if !strings.Contains(output, expected) {
+		t.Errorf("Expected output to contain '%s', got: %s", expected, output)
+	}
+}
@@ -1,5 +1,10 @@
 package provider
 
+import (
+	"io"
+	"time"
+)
+
 // This file defines common, provider-agnostic data structures for API responses
 // and method options.
 
@@ -22,6 +27,22 @@ type PostMessageOptions struct {
 	OverrideUsername string
 	IconEmoji        string
 	Blocks           []byte
+
+	// ThreadTS posts the message as a reply in the thread whose parent has this timestamp.
+	ThreadTS string
+
+	// ReplyBroadcast also shows a thread reply in the channel. It requires ThreadTS.
+	ReplyBroadcast bool
+
+	// PostAt schedules the message for this time instead of posting it now.
+	// This should only be set if Capabilities().CanScheduleMessages is true.
+	PostAt time.Time
+
+	// EphemeralUserID posts the message so that only this user (ID or name) can
+	// see it in the target channel. Ephemeral messages cannot be scheduled, sent
+	// as DMs, or edited later, so PostResult only carries the channel ID.
+	// This should only be set if Capabilities().CanPostEphemeral is true.
+	EphemeralUserID string
 }
 
 // PostFileOptions defines the parameters for a PostFile call.
@@ -43,6 +64,58 @@ type PostFileOptions struct {
 	Comment          string
 	OverrideUsername string
 	IconEmoji        string
+
+	// ThreadTS shares the file as a reply in the thread whose parent has this timestamp.
+	ThreadTS string
+
+	// Title is the title of the file described by FilePath. It defaults to Filename.
+	Title string
+
+	// AltText describes the image described by FilePath for screen readers.
+	AltText string
+
+	// Snippet uploads text files as syntax-highlighted snippets. Filetype
+	// selects the highlighting; if empty, providers detect it from the file
+	// name and content.
+	Snippet bool
+
+	// Content, if set, is uploaded instead of reading FilePath, e.g. data piped
+	// to stdin. Size must be its length in bytes.
+	Content io.ReadSeeker
+	Size    int64
+
+	// Files uploads several files together in a single message with one Comment.
+	// If set, FilePath, Filename, Title, and AltText are ignored, and Filetype
+	// only applies to files that do not set their own.
+	Files []UploadFile
+}
+
+// UploadFile describes one file of a multi-file PostFile call.
+type UploadFile struct {
+	Path     string // Local path of the file content.
+	Filename string // Name of the file as shown by the provider.
+	Title    string // Title of the file. Defaults to Filename.
+	Filetype string // Filetype of the file. Detected by the provider if empty.
+	AltText  string // Description of an image for screen readers.
+
+	Content io.ReadSeeker // Content to upload instead of reading Path.
+	Size    int64         // Length of Content in bytes.
+}
+
+// UploadFiles returns the files to upload: Files if set, otherwise the single
+// file described by FilePath, Filename, Title, AltText, and Content.
+func (o PostFileOptions) UploadFiles() []UploadFile {
+	if len(o.Files) == 0 {
+		return []UploadFile{{Path: o.FilePath, Filename: o.Filename, Title: o.Title, Filetype: o.Filetype, AltText: o.AltText, Content: o.Content, Size: o.Size}}
+	}
+	files := make([]UploadFile, len(o.Files))
+	for i, f := range o.Files {
+		if f.Filetype == "" {
+			f.Filetype = o.Filetype
+		}
+		files[i] = f
+	}
+	return files
 }
 
 // GetConversationHistoryOptions defines the parameters for a GetConversationHistory call.
@@ -69,10 +142,75 @@ type InviteToChannelOptions struct {
 	Invitees []string // User names, user IDs, or user group handles
 }
 
+// UpdateMessageOptions defines the parameters for an UpdateMessage call.
+type UpdateMessageOptions struct {
+	// Channel is the channel name or ID containing the message.
+	// If left empty, the provider's default channel is used as a fallback.
+	Channel   string
+	Timestamp string // Timestamp (ts) of the message to update.
+	Text      string
+	Blocks    []byte
+}
+
+// DeleteMessageOptions defines the parameters for a DeleteMessage call.
+type DeleteMessageOptions struct {
+	// Channel is the channel name or ID containing the message.
+	// If left empty, the provider's default channel is used as a fallback.
+	Channel   string
+	Timestamp string // Timestamp (ts) of the message to delete.
+}
+
+// ListScheduledMessagesOptions defines the parameters for a ListScheduledMessages call.
+type ListScheduledMessagesOptions struct {
+	Channel string // Channel name or ID. If empty, messages for all channels are listed.
+}
+
+// DeleteScheduledMessageOptions defines the parameters for a DeleteScheduledMessage call.
+type DeleteScheduledMessageOptions struct {
+	// Channel is the channel name or ID the message is scheduled for.
+	// If left empty, the provider's default channel is used as a fallback.
+	Channel string
+	ID      string // ID of the scheduled message, as returned in PostResult.ScheduledMessageID.
+}
+
+// ReactionOptions defines the parameters for an AddReaction or RemoveReaction call.
+type ReactionOptions struct {
+	// Channel is the channel name or ID containing the message.
+	// If left empty, the provider's default channel is used as a fallback.
+	Channel   string
+	Timestamp string // Timestamp (ts) of the message.
+	Name      string // Emoji name without surrounding colons, e.g. "white_check_mark".
+}
+
+// ListReactionsOptions defines the parameters for a ListReactions call.
+type ListReactionsOptions struct {
+	// Channel is the channel name or ID containing the message.
+	// If left empty, the provider's default channel is used as a fallback.
+	Channel   string
+	Timestamp string // Timestamp (ts) of the message.
+}
+
+// PinOptions defines the parameters for an AddPin or RemovePin call.
+type PinOptions struct {
+	// Channel is the channel name or ID containing the message.
+	// If left empty, the provider's default channel is used as a fallback.
+	Channel   string
+	Timestamp string // Timestamp (ts) of the message.
+}
+
+// ListPinsOptions defines the parameters for a ListPins call.
+type ListPinsOptions struct {
+	// Channel is the channel name or ID to list pinned messages for.
+	// If left empty, the provider's default channel is used as a fallback.
+	Channel string
+}
+
 // Channel represents a channel with its name and ID.
 type Channel struct {
-	ID   string `json:"id"`
-	Name string `json:"name"`
+	ID         string `json:"id"`
+	Name       string `json:"name"`
+	IsPrivate  bool   `json:"is_private,omitempty"`
+	IsArchived bool   `json:"is_archived,omitempty"`
 }
 
 // UserInfo represents a user with their name and ID.
@@ -83,6 +221,47 @@ type UserInfo struct {
 
 // --- Response Structs ---
 
+// PostResult describes a message or file posted by PostMessage or PostFile.
+// Fields the provider could not determine are left empty.
+type PostResult struct {
+	ChannelID string   `json:"channel_id"`
+	Timestamp string   `json:"ts,omitempty"`        // Message timestamp, usable for threading, editing, or deleting.
+	FileID    string   `json:"file_id,omitempty"`   // Set by PostFile only.
+	FileIDs   []string `json:"file_ids,omitempty"`  // All uploaded files, set by PostFile when several files were uploaded.
+	Permalink string   `json:"permalink,omitempty"` // Link to the posted message (or to the file if the message is unknown).
+
+	// ScheduledMessageID and PostAt are set instead of Timestamp when the message was scheduled.
+	ScheduledMessageID string    `json:"scheduled_message_id,omitempty"`
+	PostAt             time.Time `json:"post_at,omitzero"`
+}
+
+// Reaction represents an emoji reaction on a message.
+type Reaction struct {
+	Name  string   `json:"name"`
+	Count int      `json:"count"`
+	Users []string `json:"users"` // IDs of the users who reacted. May be incomplete for popular reactions.
+}
+
+// PinnedMessage represents a message pinned to a channel.
+type PinnedMessage struct {
+	ChannelID string    `json:"channel_id"`
+	Timestamp string    `json:"ts"`
+	UserID    string    `json:"user_id,omitempty"` // Author of the message.
+	Text      string    `json:"text"`
+	Permalink string    `json:"permalink,omitempty"`
+	PinnedBy  string    `json:"pinned_by,omitempty"` // ID of the user who pinned the message.
+	PinnedAt  time.Time `json:"pinned_at,omitzero"`
+}
+
+// ScheduledMessage represents a message waiting to be posted.
+type ScheduledMessage struct {
+	ID        string    `json:"id"`
+	ChannelID string    `json:"channel_id"`
+	PostAt    time.Time `json:"post_at"`
+	CreatedAt time.Time `json:"created_at,omitzero"`
+	Text      string    `json:"text"`
+}
+
 // ConversationHistoryResponse represents the response from a conversation history API call.
 type ConversationHistoryResponse struct {
 	Messages         []Message
@@ -0,0 +1,151 @@
+// Package threadkey persists stable thread keys: user-chosen names that map to
+// the parent message of a thread, so that independent invocations of scat can
+// post into the same thread without passing timestamps around.
+package threadkey
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// DefaultFileName is the name of the state file, stored next to the config file.
+const DefaultFileName = "thread-keys.json"
+
+// Entry records the parent message of a keyed thread.
+type Entry struct {
+	Profile   string    `json:"profile"`
+	ChannelID string    `json:"channel_id"`
+	Timestamp string    `json:"ts"`
+	CreatedAt time.Time `json:"created_at"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// Expired reports whether the entry has expired at now.
+func (e Entry) Expired(now time.Time) bool {
+	return !e.ExpiresAt.IsZero() && !now.Before(e.ExpiresAt)
+}
+
+// Store reads and writes the thread key state file. Every access holds an
+// exclusive lock on a companion ".lock" file, so concurrent scat processes
+// sharing the same state file are serialized.
+type Store struct {
+	path string
+
+	// now is replaceable for testing.
+	now func() time.Time
+}
+
+// NewStore creates a Store backed by the file at path.
+func NewStore(path string) *Store {
+	return &Store{path: path, now: time.Now}
+}
+
+// Path returns the path of the state file.
+func (s *Store) Path() string {
+	return s.path
+}
+
+// Now returns the current time as seen by the store.
+func (s *Store) Now() time.Time {
+	return s.now()
+}
+
+// Entries returns all unexpired entries.
+func (s *Store) Entries() (map[string]Entry, error) {
+	var result map[string]Entry
+	err := s.withLock(func() error {
+		entries, err := s.load()
+		result = entries
+		return err
+	})
+	return result, err
+}
+
+// Update loads the unexpired entries, calls fn with them while holding the
+// lock, and saves the (possibly modified) entries if fn returns nil.
+// Expired entries are dropped from the file on every successful update.
+func (s *Store) Update(fn func(entries map[string]Entry) error) error {
+	return s.withLock(func() error {
+		entries, err := s.load()
+		if err != nil {
+			return err
+		}
+		if err := fn(entries); err != nil {
+			return err
+		}
+		return s.save(entries)
+	})
+}
+
+// withLock runs fn while holding an exclusive lock on the state file.
+func (s *Store) withLock(fn func() error) error {
+	if err := os.MkdirAll(filepath.Dir(s.path), 0700); err != nil {
+		return fmt.Errorf("failed to create directory for thread key state: %w", err)
+	}
+	lock, err := os.OpenFile(s.path+".lock", os.O_CREATE|os.O_RDWR, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to open thread key lock file: %w", err)
+	}
+	defer lock.Close()
+
+	if err := lockFile(lock); err != nil {
+		return fmt.Errorf("failed to lock thread key state: %w", err)
+	}
+	defer func() { _ = unlockFile(lock) }()
+
+	return fn()
+}
+
+// load reads the state file and drops expired entries. A missing file yields no entries.
+func (s *Store) load() (map[string]Entry, error) {
+	entries := make(map[string]Entry)
+	data, err := os.ReadFile(s.path)
+	if os.IsNotExist(err) {
+		return entries, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read thread key state: %w", err)
+	}
+	if len(data) > 0 {
+		if err := json.Unmarshal(data, &entries); err != nil {
+			return nil, fmt.Errorf("failed to parse thread key state %s: %w", s.path, err)
+		}
+	}
+
+	now := s.now()
+	for key, e := range entries {
+		if e.Expired(now) {
+			delete(entries, key)
+		}
+	}
+	return entries, nil
+}
+
+// save atomically replaces the state file with entries.
+func (s *Store) save(entries map[string]Entry) error {
+	data, err := json.MarshalIndent(entries, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal thread key state: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(s.path), filepath.Base(s.path)+".tmp-")
+	if err != nil {
+		return fmt.Errorf("failed to write thread key state: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write thread key state: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write thread key state: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), s.path); err != nil {
+		return fmt.Errorf("failed to write thread key state: %w", err)
+	}
+	return nil
+}
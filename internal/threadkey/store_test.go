@@ -0,0 +1,107 @@
+package threadkey
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestStore_UpdateAndEntries(t *testing.T) {
+	path := filepath.Join(t.TempDir(), DefaultFileName)
+	s := NewStore(path)
+
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	s.now = func() time.Time { return now }
+
+	err := s.Update(func(entries map[string]Entry) error {
+		entries["build-1"] = Entry{Profile: "default", ChannelID: "C01", Timestamp: "1700000000.000100", CreatedAt: now, ExpiresAt: now.Add(time.Hour)}
+		entries["build-2"] = Entry{Profile: "default", ChannelID: "C01", Timestamp: "1700000000.000200", CreatedAt: now, ExpiresAt: now.Add(2 * time.Hour)}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatalf("state file was not written: %v", err)
+	}
+	if perm := info.Mode().Perm(); perm != 0600 {
+		t.Errorf("state file permissions = %o, want 600", perm)
+	}
+
+	entries, err := s.Entries()
+	if err != nil {
+		t.Fatalf("Entries() error = %v", err)
+	}
+	if len(entries) != 2 || entries["build-1"].Timestamp != "1700000000.000100" {
+		t.Errorf("Entries() = %+v", entries)
+	}
+
+	// After 90 minutes the first entry has expired and is dropped on the next update.
+	now = now.Add(90 * time.Minute)
+	if err := s.Update(func(entries map[string]Entry) error {
+		if _, ok := entries["build-1"]; ok {
+			t.Error("expired entry was passed to Update")
+		}
+		return nil
+	}); err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+	entries, err = s.Entries()
+	if err != nil {
+		t.Fatalf("Entries() error = %v", err)
+	}
+	if len(entries) != 1 {
+		t.Errorf("Expected 1 unexpired entry, got %+v", entries)
+	}
+}
+
+func TestStore_UpdateErrorDoesNotSave(t *testing.T) {
+	path := filepath.Join(t.TempDir(), DefaultFileName)
+	s := NewStore(path)
+
+	err := s.Update(func(entries map[string]Entry) error {
+		entries["build-1"] = Entry{Timestamp: "1700000000.000100"}
+		return fmt.Errorf("post failed")
+	})
+	if err == nil {
+		t.Fatal("Update() expected an error, got nil")
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("state file should not exist after a failed update, stat error = %v", err)
+	}
+}
+
+func TestStore_ConcurrentUpdates(t *testing.T) {
+	path := filepath.Join(t.TempDir(), DefaultFileName)
+
+	const workers = 10
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			// Each worker uses its own Store, like separate scat processes.
+			err := NewStore(path).Update(func(entries map[string]Entry) error {
+				entries[fmt.Sprintf("key-%d", i)] = Entry{Timestamp: fmt.Sprintf("%d.000000", i)}
+				return nil
+			})
+			if err != nil {
+				t.Errorf("Update() error = %v", err)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	entries, err := NewStore(path).Entries()
+	if err != nil {
+		t.Fatalf("Entries() error = %v", err)
+	}
+	if len(entries) != workers {
+		t.Errorf("Expected %d entries, got %d: lost updates", workers, len(entries))
+	}
+}
@@ -0,0 +1,127 @@
+package util
+
+import (
+	"bytes"
+	"net/http"
+	"path/filepath"
+	"strings"
+)
+
+// SniffLen is the number of leading bytes DetectFiletype and IsText look at.
+const SniffLen = 512
+
+// extensionFiletypes maps file name extensions to Slack filetypes.
+var extensionFiletypes = map[string]string{
+	".bash":     "shell",
+	".c":        "c",
+	".cc":       "cpp",
+	".cpp":      "cpp",
+	".cs":       "csharp",
+	".css":      "css",
+	".csv":      "csv",
+	".diff":     "diff",
+	".gif":      "gif",
+	".go":       "go",
+	".gz":       "gzip",
+	".h":        "c",
+	".hpp":      "cpp",
+	".htm":      "html",
+	".html":     "html",
+	".java":     "java",
+	".jpeg":     "jpg",
+	".jpg":      "jpg",
+	".js":       "javascript",
+	".json":     "json",
+	".kt":       "kotlin",
+	".log":      "text",
+	".markdown": "markdown",
+	".md":       "markdown",
+	".patch":    "diff",
+	".pdf":      "pdf",
+	".php":      "php",
+	".png":      "png",
+	".ps1":      "powershell",
+	".py":       "python",
+	".rb":       "ruby",
+	".rs":       "rust",
+	".sh":       "shell",
+	".sql":      "sql",
+	".swift":    "swift",
+	".toml":     "toml",
+	".ts":       "typescript",
+	".txt":      "text",
+	".xml":      "xml",
+	".yaml":     "yaml",
+	".yml":      "yaml",
+	".zip":      "zip",
+	".zsh":      "shell",
+}
+
+// mimeFiletypes maps media types reported by http.DetectContentType to Slack filetypes.
+var mimeFiletypes = map[string]string{
+	"application/pdf":    "pdf",
+	"application/x-gzip": "gzip",
+	"application/zip":    "zip",
+	"image/bmp":          "bmp",
+	"image/gif":          "gif",
+	"image/jpeg":         "jpg",
+	"image/png":          "png",
+	"image/webp":         "webp",
+	"text/html":          "html",
+	"text/plain":         "text",
+	"text/xml":           "xml",
+}
+
+// shebangFiletypes maps script interpreters to Slack filetypes.
+var shebangFiletypes = map[string]string{
+	"bash":    "shell",
+	"node":    "javascript",
+	"python":  "python",
+	"python3": "python",
+	"ruby":    "ruby",
+	"sh":      "shell",
+	"zsh":     "shell",
+}
+
+// DetectFiletype returns the Slack filetype (e.g. "go", "png", "text") of a
+// file, judged by the extension of filename and, failing that, by a script's
+// #! line or the media type sniffed from head, the leading bytes of the
+// content. It returns an empty string if the type cannot be determined.
+func DetectFiletype(filename string, head []byte) string {
+	if ft, ok := extensionFiletypes[strings.ToLower(filepath.Ext(filename))]; ok {
+		return ft
+	}
+	if interpreter := shebangInterpreter(head); interpreter != "" {
+		if ft, ok := shebangFiletypes[interpreter]; ok {
+			return ft
+		}
+	}
+	mediaType, _, _ := strings.Cut(http.DetectContentType(head), ";")
+	return mimeFiletypes[mediaType]
+}
+
+// IsText reports whether head, the leading bytes of some content, looks like text.
+func IsText(head []byte) bool {
+	return strings.HasPrefix(http.DetectContentType(head), "text/")
+}
+
+// shebangInterpreter returns the interpreter named by a leading "#!" line,
+// e.g. "bash" for "#!/usr/bin/env bash" or "#!/bin/bash -e".
+func shebangInterpreter(head []byte) string {
+	line, ok := bytes.CutPrefix(head, []byte("#!"))
+	if !ok {
+		return ""
+	}
+	if i := bytes.IndexByte(line, '\n'); i >= 0 {
+		line = line[:i]
+	}
+	fields := strings.Fields(string(line))
+	if len(fields) == 0 {
+		return ""
+	}
+	interpreter := filepath.Base(fields[0])
+	if interpreter == "env" && len(fields) > 1 {
+		interpreter = fields[1]
+	}
+	return interpreter
+}
@@ -0,0 +1,39 @@
+package util
+
+import "testing"
+
+func TestDetectFiletype(t *testing.T) {
+	png := []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR")
+	tests := []struct {
+		name     string
+		filename string
+		head     []byte
+		want     string
+	}{
+		{"extension", "main.go", []byte("package main"), "go"},
+		{"extension is case-insensitive", "README.MD", []byte("# Title"), "markdown"},
+		{"extension wins over content", "notes.txt", png, "text"},
+		{"shebang", "deploy", []byte("#!/usr/bin/env bash\nset -e\n"), "shell"},
+		{"shebang with path", "tool", []byte("#!/usr/bin/python3\nprint(1)\n"), "python"},
+		{"sniffed image", "stdin-upload", png, "png"},
+		{"sniffed pdf", "stdin-upload", []byte("%PDF-1.7\n"), "pdf"},
+		{"sniffed text", "stdin-upload", []byte("plain words\n"), "text"},
+		{"unknown binary", "blob", []byte{0x00, 0x01, 0x02, 0xfe}, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := DetectFiletype(tt.filename, tt.head); got != tt.want {
+				t.Errorf("DetectFiletype(%q, ...) = %q, want %q", tt.filename, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsText(t *testing.T) {
+	if !IsText([]byte("func main() {}\n")) {
+		t.Error("IsText() = false for source code, want true")
+	}
+	if IsText([]byte("\x89PNG\r\n\x1a\n")) {
+		t.Error("IsText() = true for a PNG header, want false")
+	}
+}
@@ -0,0 +1,63 @@
+package util
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+var (
+	// tsPattern matches a Slack message timestamp such as "1700000000.000100".
+	tsPattern = regexp.MustCompile(`^\d+\.\d+$`)
+	// permalinkPathPattern matches the path of a Slack message permalink,
+	// e.g. "/archives/C0123ABCD/p1700000000000100".
+	permalinkPathPattern = regexp.MustCompile(`^/archives/([A-Z0-9]+)/p(\d{7,})$`)
+)
+
+// MessageRef identifies a Slack message, parsed from a raw timestamp or a permalink.
+type MessageRef struct {
+	ChannelID string // Channel ID from the permalink; empty for a raw timestamp.
+	Timestamp string // Timestamp (ts) of the message itself.
+	ThreadTS  string // Timestamp of the thread parent if the permalink points to a reply.
+}
+
+// ThreadRoot returns the timestamp to reply to in order to post into the
+// message's thread: the parent's ts for a reply, otherwise the message's own ts.
+func (r MessageRef) ThreadRoot() string {
+	if r.ThreadTS != "" {
+		return r.ThreadTS
+	}
+	return r.Timestamp
+}
+
+// ParseMessageRef parses either a raw message timestamp ("1700000000.000100")
+// or a message permalink ("https://example.slack.com/archives/C0123ABCD/p1700000000000100").
+func ParseMessageRef(s string) (MessageRef, error) {
+	s = strings.TrimSpace(s)
+	if tsPattern.MatchString(s) {
+		return MessageRef{Timestamp: s}, nil
+	}
+
+	u, err := url.Parse(s)
+	if err != nil || u.Scheme == "" || u.Host == "" {
+		return MessageRef{}, fmt.Errorf("invalid message reference %q: expected a timestamp like 1700000000.000100 or a message permalink", s)
+	}
+	m := permalinkPathPattern.FindStringSubmatch(u.Path)
+	if m == nil {
+		return MessageRef{}, fmt.Errorf("invalid message permalink %q: expected a path like /archives/<channel>/p<timestamp>", s)
+	}
+
+	digits := m[2]
+	ref := MessageRef{
+		ChannelID: m[1],
+		Timestamp: digits[:len(digits)-6] + "." + digits[len(digits)-6:],
+	}
+	if threadTS := u.Query().Get("thread_ts"); threadTS != "" {
+		if !tsPattern.MatchString(threadTS) {
+			return MessageRef{}, fmt.Errorf("invalid thread_ts %q in message permalink", threadTS)
+		}
+		ref.ThreadTS = threadTS
+	}
+	return ref, nil
+}
@@ -0,0 +1,67 @@
+package util
+
+import (
+	"testing"
+)
+
+func TestParseMessageRef(t *testing.T) {
+	tests := []struct {
+		name       string
+		input      string
+		want       MessageRef
+		wantThread string
+		wantErr    bool
+	}{
+		{
+			name:       "Raw timestamp",
+			input:      "1700000000.000100",
+			want:       MessageRef{Timestamp: "1700000000.000100"},
+			wantThread: "1700000000.000100",
+		},
+		{
+			name:       "Permalink",
+			input:      "https://example.slack.com/archives/C0123ABCD/p1700000000000100",
+			want:       MessageRef{ChannelID: "C0123ABCD", Timestamp: "1700000000.000100"},
+			wantThread: "1700000000.000100",
+		},
+		{
+			name:       "Permalink to a thread reply",
+			input:      "https://example.slack.com/archives/C0123ABCD/p1700000050000200?thread_ts=1700000000.000100&cid=C0123ABCD",
+			want:       MessageRef{ChannelID: "C0123ABCD", Timestamp: "1700000050.000200", ThreadTS: "1700000000.000100"},
+			wantThread: "1700000000.000100",
+		},
+		{
+			name:    "Not a timestamp",
+			input:   "yesterday",
+			wantErr: true,
+		},
+		{
+			name:    "Unrelated URL",
+			input:   "https://example.com/some/page",
+			wantErr: true,
+		},
+		{
+			name:    "Invalid thread_ts in permalink",
+			input:   "https://example.slack.com/archives/C0123ABCD/p1700000000000100?thread_ts=abc",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseMessageRef(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseMessageRef() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if got != tt.want {
+				t.Errorf("ParseMessageRef() = %+v, want %+v", got, tt.want)
+			}
+			if got.ThreadRoot() != tt.wantThread {
+				t.Errorf("ThreadRoot() = %q, want %q", got.ThreadRoot(), tt.wantThread)
+			}
+		})
+	}
+}
@@ -0,0 +1,89 @@
+package util
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+const (
+	// ProgressMinSize is the smallest transfer for which a progress bar is worth showing.
+	ProgressMinSize = 1 << 20 // 1 MiB
+
+	progressBarWidth = 30
+	progressInterval = 200 * time.Millisecond
+)
+
+// ProgressReader wraps a reader and draws a progress bar with the transfer
+// rate to out as data is read. The bar is redrawn in place at most every
+// 200ms and finished with a newline once total bytes have been read.
+type ProgressReader struct {
+	r     io.Reader
+	out   io.Writer
+	label string
+	total int64
+
+	read     int64
+	start    time.Time
+	lastDraw time.Time
+	done     bool
+
+	// now is replaceable for testing.
+	now func() time.Time
+}
+
+// NewProgressReader returns a ProgressReader reporting on a transfer of total bytes labeled label.
+func NewProgressReader(r io.Reader, out io.Writer, label string, total int64) *ProgressReader {
+	return &ProgressReader{r: r, out: out, label: label, total: total, now: time.Now}
+}
+
+// Read reads from the underlying reader and updates the progress bar.
+func (p *ProgressReader) Read(b []byte) (int, error) {
+	if p.start.IsZero() {
+		p.start = p.now()
+	}
+	n, err := p.r.Read(b)
+	p.read += int64(n)
+
+	finished := p.read >= p.total || err == io.EOF
+	if now := p.now(); !p.done && (finished || now.Sub(p.lastDraw) >= progressInterval) {
+		p.lastDraw = now
+		p.draw(now)
+		if finished {
+			fmt.Fprintln(p.out)
+			p.done = true
+		}
+	}
+	return n, err
+}
+
+// draw renders the current state of the transfer on a single line.
+func (p *ProgressReader) draw(now time.Time) {
+	fraction := 1.0
+	if p.total > 0 {
+		fraction = min(float64(p.read)/float64(p.total), 1)
+	}
+	filled := int(fraction * progressBarWidth)
+	bar := strings.Repeat("=", filled) + strings.Repeat(" ", progressBarWidth-filled)
+
+	var rate float64
+	if elapsed := now.Sub(p.start).Seconds(); elapsed > 0 {
+		rate = float64(p.read) / elapsed
+	}
+	fmt.Fprintf(p.out, "\r%s [%s] %3.0f%% %s / %s %s/s", p.label, bar, fraction*100, FormatBytes(p.read), FormatBytes(p.total), FormatBytes(int64(rate)))
+}
+
+// FormatBytes formats a byte count with a binary unit, e.g. "1.5 MiB".
+func FormatBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
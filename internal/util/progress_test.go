@@ -0,0 +1,59 @@
+package util
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFormatBytes(t *testing.T) {
+	tests := []struct {
+		n    int64
+		want string
+	}{
+		{0, "0 B"},
+		{1023, "1023 B"},
+		{1536, "1.5 KiB"},
+		{5 << 20, "5.0 MiB"},
+		{3 << 30, "3.0 GiB"},
+	}
+	for _, tt := range tests {
+		if got := FormatBytes(tt.n); got != tt.want {
+			t.Errorf("FormatBytes(%d) = %q, want %q", tt.n, got, tt.want)
+		}
+	}
+}
+
+func TestProgressReader(t *testing.T) {
+	data := bytes.Repeat([]byte("x"), 4<<20)
+	var out bytes.Buffer
+	now := time.Unix(1700000000, 0)
+
+	pr := NewProgressReader(bytes.NewReader(data), &out, "report.bin", int64(len(data)))
+	pr.now = func() time.Time {
+		now = now.Add(100 * time.Millisecond)
+		return now
+	}
+
+	n, err := io.Copy(io.Discard, pr)
+	if err != nil {
+		t.Fatalf("io.Copy() returned an unexpected error: %v", err)
+	}
+	if n != int64(len(data)) {
+		t.Errorf("Expected %d bytes to be read, got %d", len(data), n)
+	}
+
+	got := out.String()
+	if !strings.HasPrefix(got, "\rreport.bin [") {
+		t.Errorf("Expected the bar to start with the label, got: %q", got)
+	}
+	last := got[strings.LastIndex(got, "\r"):]
+	if !strings.Contains(last, "100% 4.0 MiB / 4.0 MiB") || !strings.HasSuffix(last, "/s\n") {
+		t.Errorf("Expected a finished bar with throughput, got: %q", last)
+	}
+	if strings.Count(got, "\n") != 1 {
+		t.Errorf("Expected exactly one trailing newline, got: %q", got)
+	}
+}